@@ -0,0 +1,136 @@
+package main
+
+// 管理面板模板，AdminPanelEnabled 启用时生成 pkg/handlers/admin.go，
+// 在 /admin 下注册由 ADMIN_USER、ADMIN_PASS 进行 HTTP Basic 认证的管理接口
+
+const adminHandlerTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package handlers
+
+import (
+	"net/http"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"{{.Project.ModuleName}}/pkg/models"
+)
+
+// 进程内计数器，由 CountRequests 和清理接口更新，GET /admin/metrics 返回
+var (
+	startedAt        = time.Now()
+	requestsTotal    atomic.Int64
+	requestsInFlight atomic.Int64
+	// 按状态码类别 (1xx-5xx) 统计的响应数
+	responsesByClass [6]atomic.Int64
+	purgedTotal      atomic.Int64
+)
+
+// CountRequests 统计请求数和响应状态码，需要注册为全局中间件
+func CountRequests() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestsTotal.Add(1)
+		requestsInFlight.Add(1)
+		defer requestsInFlight.Add(-1)
+
+		c.Next()
+
+		if class := c.Writer.Status() / 100; class > 0 && class < len(responsesByClass) {
+			responsesByClass[class].Add(1)
+		}
+	}
+}
+
+// RegisterAdminRoutes 注册管理接口，rg 应已使用 gin.BasicAuth 保护
+func RegisterAdminRoutes(rg *gin.RouterGroup, db *gorm.DB) {
+	rg.GET("/models", adminListModels(db))
+	rg.GET("/metrics", adminMetrics(db))
+{{- range .Models}}
+	rg.POST("/{{.PluralName}}/purge", adminPurge{{.Name}}(db))
+{{- end}}
+}
+
+type modelStats struct {
+	Name    string ` + "`json:\"name\"`" + `
+	Count   int64  ` + "`json:\"count\"`" + `
+	Deleted int64  ` + "`json:\"deleted\"`" + `
+}
+
+// 每个模型的记录数，count 不含软删除的记录，deleted 为软删除待清理的记录数
+func adminListModels(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		counters := []struct {
+			name  string
+			model interface{}
+		}{
+{{- range .Models}}
+			{"{{.Name}}", &models.{{.Name}}{}},
+{{- end}}
+		}
+
+		stats := make([]modelStats, 0, len(counters))
+		for _, counter := range counters {
+			stat := modelStats{Name: counter.name}
+			if err := db.Model(counter.model).Count(&stat.Count).Error; err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			if err := db.Unscoped().Model(counter.model).Where("deleted_at IS NOT NULL").Count(&stat.Deleted).Error; err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			stats = append(stats, stat)
+		}
+		c.JSON(http.StatusOK, stats)
+	}
+}
+{{- range .Models}}
+
+// 永久删除所有已软删除的 {{.Name}}，返回删除的记录数
+func adminPurge{{.Name}}(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result := db.Unscoped().Where("deleted_at IS NOT NULL").Delete(&models.{{.Name}}{})
+		if result.Error != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": result.Error.Error()})
+			return
+		}
+		purgedTotal.Add(result.RowsAffected)
+		c.JSON(http.StatusOK, gin.H{"purged": result.RowsAffected})
+	}
+}
+{{- end}}
+
+func adminMetrics(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var mem runtime.MemStats
+		runtime.ReadMemStats(&mem)
+
+		responses := gin.H{}
+		for class := 1; class < len(responsesByClass); class++ {
+			responses[string(rune('0'+class))+"xx"] = responsesByClass[class].Load()
+		}
+
+		metrics := gin.H{
+			"uptime_seconds":       int64(time.Since(startedAt).Seconds()),
+			"requests_total":       requestsTotal.Load(),
+			"requests_in_flight":   requestsInFlight.Load(),
+			"responses":            responses,
+			"records_purged_total": purgedTotal.Load(),
+			"goroutines":           runtime.NumGoroutine(),
+			"heap_alloc_bytes":     mem.HeapAlloc,
+		}
+		if sqlDB, err := db.DB(); err == nil {
+			stats := sqlDB.Stats()
+			metrics["db"] = gin.H{
+				"open_connections": stats.OpenConnections,
+				"in_use":           stats.InUse,
+				"idle":             stats.Idle,
+				"wait_count":       stats.WaitCount,
+			}
+		}
+		c.JSON(http.StatusOK, metrics)
+	}
+}
+`
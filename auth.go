@@ -0,0 +1,805 @@
+package main
+
+// 认证与授权相关模板
+
+const rbacMiddlewareTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package middlewares
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"{{.Project.ModuleName}}/pkg/models"
+)
+
+// RoleLookup 返回当前登录用户的角色
+type RoleLookup func(c *gin.Context) (string, error)
+
+var errNotLoggedIn = errors.New("not logged in")
+
+// UserRole 从数据库读取登录用户的 role 列，须在 {{if eq .Project.AuthMode "session"}}RequireSession{{else}}RequireAccessToken{{end}} 之后使用
+func UserRole(db *gorm.DB) RoleLookup {
+	return func(c *gin.Context) (string, error) {
+		login, ok := c.Get({{if eq .Project.AuthMode "session"}}SessionUserKey{{else}}"email"{{end}})
+		if !ok {
+			return "", errNotLoggedIn
+		}
+		var user models.{{.AuthUser.Model.Name}}
+		if err := db.Select("role").Where("{{.AuthUser.LoginField.ColumnName}} = ?", login).First(&user).Error; err != nil {
+			return "", err
+		}
+		return user.Role, nil
+	}
+}
+
+// RequireRole 要求登录用户的角色属于给定角色之一
+func RequireRole(lookup RoleLookup, roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, err := lookup(c)
+		if errors.Is(err, errNotLoggedIn) || errors.Is(err, gorm.ErrRecordNotFound) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		for _, r := range roles {
+			if r == role {
+				c.Set("role", role)
+				c.Next()
+				return
+			}
+		}
+
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+	}
+}
+
+// RequireRoleByMethod 按HTTP方法应用 RequireRole，未列出的方法不做限制
+func RequireRoleByMethod(lookup RoleLookup, permissions map[string][]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		roles, ok := permissions[c.Request.Method]
+		if !ok {
+			c.Next()
+			return
+		}
+		RequireRole(lookup, roles...)(c)
+	}
+}
+`
+
+const rbacTestTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+{{- if eq .Project.AuthMode "session"}}
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/memstore"
+{{- end}}
+	"github.com/gin-gonic/gin"
+{{- if eq .Project.AuthMode "magiclink"}}
+
+	"{{.Project.ModuleName}}/pkg/auth"
+{{- end}}
+)
+
+// 代替数据库中的 role 列，测试用户的角色为邮箱的用户名部分，如 admin@example.com 为 admin
+func testRoleLookup(c *gin.Context) (string, error) {
+	login, ok := c.Get({{if eq .Project.AuthMode "session"}}SessionUserKey{{else}}"email"{{end}})
+	if !ok {
+		return "", errNotLoggedIn
+	}
+	return strings.TrimSuffix(login.(string), "@example.com"), nil
+}
+{{- if eq .Project.AuthMode "session"}}
+
+// 经过真实的会话中间件：先请求 /login 写入会话，再带着会话 cookie 访问受保护的路由
+func serveAs(t *testing.T, handler gin.HandlerFunc, method, user string) int {
+	t.Helper()
+	r := gin.New()
+	r.Use(sessions.Sessions("session_id", memstore.NewStore([]byte("test-secret"))))
+	r.POST("/login", func(c *gin.Context) {
+		session := sessions.Default(c)
+		session.Set(SessionUserKey, c.Query("user"))
+		if err := session.Save(); err != nil {
+			c.AbortWithStatus(http.StatusInternalServerError)
+		}
+	})
+	r.Handle(method, "/", RequireSession(), handler, func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(method, "/", nil)
+	if user != "" {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/login?user="+user, nil))
+		for _, cookie := range w.Result().Cookies() {
+			req.AddCookie(cookie)
+		}
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+{{- else}}
+
+// 经过真实的访问令牌中间件：为用户签发访问令牌后访问受保护的路由
+func serveAs(t *testing.T, handler gin.HandlerFunc, method, user string) int {
+	t.Helper()
+	magicLink := auth.NewMagicLink("test-secret", "http://localhost")
+	r := gin.New()
+	r.Handle(method, "/", RequireAccessToken(magicLink), handler, func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(method, "/", nil)
+	if user != "" {
+		token, err := magicLink.IssueAccessToken(user)
+		if err != nil {
+			t.Fatalf("issue access token: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+{{- end}}
+
+func TestRequireRoleAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := RequireRole(testRoleLookup, "admin")
+
+	if got := serveAs(t, handler, http.MethodGet, "admin@example.com"); got != http.StatusOK {
+		t.Errorf("admin: got status %d, want %d", got, http.StatusOK)
+	}
+	if got := serveAs(t, handler, http.MethodGet, "nobody@example.com"); got != http.StatusForbidden {
+		t.Errorf("nobody: got status %d, want %d", got, http.StatusForbidden)
+	}
+	if got := serveAs(t, handler, http.MethodGet, ""); got != http.StatusUnauthorized {
+		t.Errorf("anonymous: got status %d, want %d", got, http.StatusUnauthorized)
+	}
+}
+
+func TestRequireRoleByMethod(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name   string
+		method string
+		roles  []string
+		user   string
+		want   int
+	}{
+		{{- range .Models}}
+		{{- $model := .}}
+		{{- range $method, $roles := .Permissions}}
+		{{- range $roles}}
+		{"{{$model.Name}} {{$method}} as {{.}}", "{{$method}}", []string{ {{- quoteList $roles -}} }, "{{.}}@example.com", http.StatusOK},
+		{{- end}}
+		{"{{$model.Name}} {{$method}} as nobody", "{{$method}}", []string{ {{- quoteList $roles -}} }, "nobody@example.com", http.StatusForbidden},
+		{"{{$model.Name}} {{$method}} without login", "{{$method}}", []string{ {{- quoteList $roles -}} }, "", http.StatusUnauthorized},
+		{{- end}}
+		{{- end}}
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := RequireRoleByMethod(testRoleLookup, map[string][]string{tc.method: tc.roles})
+			if got := serveAs(t, handler, tc.method, tc.user); got != tc.want {
+				t.Errorf("got status %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+`
+
+const sessionMiddlewareTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package middlewares
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/cookie"
+	"github.com/gin-contrib/sessions/memstore"
+	"github.com/gin-contrib/sessions/redis"
+	"github.com/gin-gonic/gin"
+
+	"{{.Project.ModuleName}}/pkg/config"
+)
+
+// SessionUserKey 会话中保存登录用户标识的键
+const SessionUserKey = "user"
+
+// SessionMiddleware 根据配置创建会话存储并返回会话中间件
+func SessionMiddleware(cfg *config.Config) (gin.HandlerFunc, error) {
+	secret := []byte(cfg.SessionSecret)
+
+	var store sessions.Store
+	switch cfg.SessionStore {
+	case "", "cookie":
+		store = cookie.NewStore(secret)
+	case "memory":
+		store = memstore.NewStore(secret)
+	case "redis":
+		redisStore, err := redis.NewStore(10, "tcp", cfg.RedisAddr, "", secret)
+		if err != nil {
+			return nil, err
+		}
+		store = redisStore
+	default:
+		return nil, fmt.Errorf("unsupported session store: %s", cfg.SessionStore)
+	}
+
+	ttl := cfg.SessionTTLHours
+	if ttl <= 0 {
+		ttl = 24
+	}
+	store.Options(sessions.Options{
+		Path:     "/",
+		MaxAge:   ttl * 3600,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return sessions.Sessions("session_id", store), nil
+}
+
+// RequireSession 要求请求携带有效的登录会话
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := sessions.Default(c).Get(SessionUserKey)
+		if user == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+		c.Set(SessionUserKey, user)
+		c.Next()
+	}
+}
+`
+
+const sessionAuthHandlerTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+{{- if .Project.TOTPEnabled}}
+	"{{.Project.ModuleName}}/pkg/auth"
+{{- end}}
+	"{{.Project.ModuleName}}/pkg/middlewares"
+	"{{.Project.ModuleName}}/pkg/models"
+)
+{{- if .Project.TOTPEnabled}}
+
+// 已通过密码校验、等待两步验证的登录标识
+const totpPendingKey = "totp_pending"
+{{- end}}
+
+type loginInput struct {
+	{{.AuthUser.LoginField.Name}} string ` + "`json:\"{{.AuthUser.LoginField.JsonTag}}\" binding:\"required\"`" + `
+	Password string ` + "`json:\"password\" binding:\"required\"`" + `
+}
+
+func RegisterAuthRoutes(rg *gin.RouterGroup, db *gorm.DB) {
+	rg.POST("/login", login(db))
+	rg.POST("/logout", logout())
+	rg.GET("/me", middlewares.RequireSession(), me(db))
+{{- if .Project.TOTPEnabled}}
+	rg.POST("/totp/setup", middlewares.RequireSession(), totpSetup(db))
+	rg.POST("/totp/verify", totpVerify(db))
+{{- end}}
+}
+
+func login(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input loginInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		var user models.{{.AuthUser.Model.Name}}
+		if result := db.Where("{{.AuthUser.LoginField.ColumnName}} = ?", input.{{.AuthUser.LoginField.Name}}).First(&user); result.Error != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+			return
+		}
+
+		if err := bcrypt.CompareHashAndPassword([]byte(user.{{.AuthUser.PasswordField.Name}}), []byte(input.Password)); err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+			return
+		}
+
+		session := sessions.Default(c)
+{{- if .Project.TOTPEnabled}}
+		if user.TOTPEnabled {
+			session.Set(totpPendingKey, user.{{.AuthUser.LoginField.Name}})
+			if err := session.Save(); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"totp_required": true})
+			return
+		}
+{{- end}}
+		session.Set(middlewares.SessionUserKey, user.{{.AuthUser.LoginField.Name}})
+		if err := session.Save(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, user)
+	}
+}
+
+func logout() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		session := sessions.Default(c)
+		session.Clear()
+		session.Options(sessions.Options{Path: "/", MaxAge: -1})
+		if err := session.Save(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "logged out"})
+	}
+}
+
+func me(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var user models.{{.AuthUser.Model.Name}}
+		if result := db.Where("{{.AuthUser.LoginField.ColumnName}} = ?", c.MustGet(middlewares.SessionUserKey)).First(&user); result.Error != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "{{.AuthUser.Model.Name}} not found"})
+			return
+		}
+		c.JSON(http.StatusOK, user)
+	}
+}
+{{- if .Project.TOTPEnabled}}
+
+func totpSetup(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var user models.{{.AuthUser.Model.Name}}
+		if result := db.Where("{{.AuthUser.LoginField.ColumnName}} = ?", c.MustGet(middlewares.SessionUserKey)).First(&user); result.Error != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "{{.AuthUser.Model.Name}} not found"})
+			return
+		}
+
+		secret, url, err := auth.Generate(user.{{.AuthUser.LoginField.Name}})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		// 在首次验证成功之前不启用两步验证
+		if result := db.Model(&user).Updates(map[string]interface{}{"totp_secret": secret, "totp_enabled": false}); result.Error != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": result.Error.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"secret": secret, "url": url})
+	}
+}
+
+func totpVerify(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input struct {
+			Code string ` + "`json:\"code\" binding:\"required\"`" + `
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		session := sessions.Default(c)
+		login := session.Get(totpPendingKey)
+		pending := login != nil
+		if !pending {
+			login = session.Get(middlewares.SessionUserKey)
+		}
+		if login == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		var user models.{{.AuthUser.Model.Name}}
+		if result := db.Where("{{.AuthUser.LoginField.ColumnName}} = ?", login).First(&user); result.Error != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "{{.AuthUser.Model.Name}} not found"})
+			return
+		}
+
+		if user.TOTPSecret == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "TOTP is not set up"})
+			return
+		}
+		if !auth.Validate(input.Code, user.TOTPSecret) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid code"})
+			return
+		}
+
+		if !user.TOTPEnabled {
+			if result := db.Model(&user).Update("totp_enabled", true); result.Error != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": result.Error.Error()})
+				return
+			}
+		}
+
+		if pending {
+			session.Delete(totpPendingKey)
+			session.Set(middlewares.SessionUserKey, login)
+			if err := session.Save(); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
+		c.JSON(http.StatusOK, user)
+	}
+}
+{{- end}}
+`
+
+const passwordHookTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package models
+
+import (
+	"encoding/json"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// MarshalJSON 输出时去掉密码哈希，登录、/auth/me 和增删改查接口返回的用户都不包含该字段；
+// 请求体仍可以通过 {{.AuthUser.PasswordField.JsonTag}} 设置明文密码
+func (u {{.AuthUser.Model.Name}}) MarshalJSON() ([]byte, error) {
+	type plain {{.AuthUser.Model.Name}}
+	return json.Marshal(struct {
+		plain
+		{{.AuthUser.PasswordField.Name}} string ` + "`json:\"{{.AuthUser.PasswordField.JsonTag}},omitempty\"`" + `
+	}{plain: plain(u)})
+}
+
+// BeforeSave 保存前对明文密码进行 bcrypt 哈希
+func (u *{{.AuthUser.Model.Name}}) BeforeSave(tx *gorm.DB) error {
+	if u.{{.AuthUser.PasswordField.Name}} == "" || strings.HasPrefix(u.{{.AuthUser.PasswordField.Name}}, "$2a$") {
+		return nil
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(u.{{.AuthUser.PasswordField.Name}}), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	u.{{.AuthUser.PasswordField.Name}} = string(hashed)
+	return nil
+}
+`
+
+const totpTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package auth
+
+import (
+	"github.com/pquerna/otp/totp"
+)
+
+// Generate 为账号生成新的TOTP密钥及 otpauth:// 链接
+func Generate(accountName string) (secret, url string, err error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      "{{.Project.ProjectName}}",
+		AccountName: accountName,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return key.Secret(), key.URL(), nil
+}
+
+// Validate 校验一次性验证码
+func Validate(code, secret string) bool {
+	return totp.Validate(code, secret)
+}
+`
+
+const magicLinkTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	loginTokenTTL  = 15 * time.Minute
+	accessTokenTTL = 7 * 24 * time.Hour
+)
+
+// MagicLink 负责签发和校验登录链接令牌与访问令牌
+type MagicLink struct {
+	secret  []byte
+	baseURL string
+}
+
+func NewMagicLink(secret, baseURL string) *MagicLink {
+	return &MagicLink{secret: []byte(secret), baseURL: baseURL}
+}
+
+// IssueLoginToken 签发写入登录链接的短期令牌
+func (m *MagicLink) IssueLoginToken(email string) (string, error) {
+	return m.issue(email, "magic_link", loginTokenTTL)
+}
+
+// LoginURL 返回包含令牌的登录链接
+func (m *MagicLink) LoginURL(token string) string {
+	return m.baseURL + "/auth/magic-link/verify?token=" + url.QueryEscape(token)
+}
+
+// VerifyLoginToken 校验登录链接令牌并返回邮箱
+func (m *MagicLink) VerifyLoginToken(token string) (string, error) {
+	return m.verify(token, "magic_link")
+}
+
+// IssueAccessToken 签发用于访问API的长期令牌
+func (m *MagicLink) IssueAccessToken(email string) (string, error) {
+	return m.issue(email, "access", accessTokenTTL)
+}
+
+// VerifyAccessToken 校验访问令牌并返回邮箱
+func (m *MagicLink) VerifyAccessToken(token string) (string, error) {
+	return m.verify(token, "access")
+}
+
+func (m *MagicLink) issue(email, purpose string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":     email,
+		"purpose": purpose,
+		"iat":     now.Unix(),
+		"exp":     now.Add(ttl).Unix(),
+	})
+	return token.SignedString(m.secret)
+}
+
+func (m *MagicLink) verify(tokenString, purpose string) (string, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return m.secret, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if claims["purpose"] != purpose {
+		return "", errors.New("invalid token purpose")
+	}
+	email, _ := claims["sub"].(string)
+	if email == "" {
+		return "", errors.New("missing subject")
+	}
+	return email, nil
+}
+`
+
+const emailSenderTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package email
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/smtp"
+
+	"{{.Project.ModuleName}}/pkg/config"
+)
+
+// Sender 邮件发送接口
+type Sender interface {
+	Send(to, subject, body string) error
+}
+
+// NewSender 根据 EMAIL_PROVIDER 创建发送器，默认输出到日志
+func NewSender(cfg *config.Config) Sender {
+	switch cfg.EmailProvider {
+	case "smtp":
+		return &SMTPSender{cfg: cfg}
+	case "sendgrid":
+		return &SendGridSender{apiKey: cfg.SendGridAPIKey, from: cfg.FromEmail}
+	default:
+		return LogSender{}
+	}
+}
+
+// LogSender 仅将邮件输出到标准输出，用于本地开发
+type LogSender struct{}
+
+func (LogSender) Send(to, subject, body string) error {
+	log.Printf("email to=%s subject=%q\n%s", to, subject, body)
+	return nil
+}
+
+// SMTPSender 通过SMTP服务器发送邮件
+type SMTPSender struct {
+	cfg *config.Config
+}
+
+func (s *SMTPSender) Send(to, subject, body string) error {
+	addr := s.cfg.SMTPHost + ":" + s.cfg.SMTPPort
+	auth := smtp.PlainAuth("", s.cfg.SMTPUser, s.cfg.SMTPPassword, s.cfg.SMTPHost)
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s", s.cfg.FromEmail, to, subject, body)
+	return smtp.SendMail(addr, auth, s.cfg.FromEmail, []string{to}, []byte(msg))
+}
+
+// SendGridSender 通过SendGrid v3 API发送邮件
+type SendGridSender struct {
+	apiKey string
+	from   string
+}
+
+func (s *SendGridSender) Send(to, subject, body string) error {
+	recipient := map[string]string{"email": to}
+	content := map[string]string{"type": "text/plain", "value": body}
+	payload, err := json.Marshal(map[string]interface{}{
+		"personalizations": []map[string]interface{}{
+			{"to": []map[string]string{recipient}},
+		},
+		"from":    map[string]string{"email": s.from},
+		"subject": subject,
+		"content": []map[string]string{content},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, "https://api.sendgrid.com/v3/mail/send", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sendgrid: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+`
+
+const tokenMiddlewareTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package middlewares
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"{{.Project.ModuleName}}/pkg/auth"
+)
+
+// RequireAccessToken 要求请求携带有效的访问令牌
+func RequireAccessToken(magicLink *auth.MagicLink) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		token := strings.TrimPrefix(header, "Bearer ")
+		if token == "" || token == header {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		email, err := magicLink.VerifyAccessToken(token)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		c.Set("email", email)
+		c.Next()
+	}
+}
+`
+
+const magicLinkAuthHandlerTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"{{.Project.ModuleName}}/pkg/auth"
+	"{{.Project.ModuleName}}/pkg/email"
+	"{{.Project.ModuleName}}/pkg/models"
+)
+
+func RegisterAuthRoutes(rg *gin.RouterGroup, db *gorm.DB, magicLink *auth.MagicLink, sender email.Sender) {
+	rg.POST("/magic-link", requestMagicLink(db, magicLink, sender))
+	rg.GET("/magic-link/verify", verifyMagicLink(db, magicLink))
+}
+
+func requestMagicLink(db *gorm.DB, magicLink *auth.MagicLink, sender email.Sender) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input struct {
+			Email string ` + "`json:\"email\" binding:\"required,email\"`" + `
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// 无论用户是否存在都返回相同响应，避免泄露账号信息
+		var user models.{{.AuthUser.Model.Name}}
+		if result := db.Where("{{.AuthUser.LoginField.ColumnName}} = ?", input.Email).First(&user); result.Error == nil {
+			token, err := magicLink.IssueLoginToken(input.Email)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+
+			body := "Click the link below to sign in to {{.Project.ProjectName}}:\n\n" + magicLink.LoginURL(token)
+			if err := sender.Send(input.Email, "Your sign-in link", body); err != nil {
+				log.Printf("failed to send magic link: %v", err)
+			}
+		}
+
+		c.JSON(http.StatusAccepted, gin.H{"status": "sent"})
+	}
+}
+
+func verifyMagicLink(db *gorm.DB, magicLink *auth.MagicLink) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		address, err := magicLink.VerifyLoginToken(c.Query("token"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			return
+		}
+
+		var user models.{{.AuthUser.Model.Name}}
+		if result := db.Where("{{.AuthUser.LoginField.ColumnName}} = ?", address).First(&user); result.Error != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			return
+		}
+
+		token, err := magicLink.IssueAccessToken(address)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"token": token})
+	}
+}
+`
@@ -0,0 +1,162 @@
+package main
+
+// 处理器基准测试模板，GenerateTests 启用时生成
+
+// 基准测试请求体中字段的取值表达式，i 为循环序号，字符串带序号以避开唯一约束
+func benchValue(f ModelField) string {
+	switch f.Type {
+	case "string":
+		return fitLength(`fmt.Sprintf("bench-%d", i)`, f)
+	case "int", "int32", "int64", "uint", "uint32", "uint64":
+		return "i + 1"
+	case "float32", "float64":
+		return "float64(i) + 0.5"
+	case "bool":
+		return "i%2 == 0"
+	case "time.Time":
+		return "time.Now()"
+	case "*time.Time":
+		return "nil"
+	case "uuid.UUID":
+		return "uuid.New()"
+	}
+	return `""`
+}
+
+// 请求体中是否有该类型的字段，用于决定基准测试的导入
+func benchUsesType(fieldType string, fields []ModelField) bool {
+	for _, f := range fields {
+		if f.Type == fieldType && f.JsonTag != "-" {
+			return true
+		}
+	}
+	return false
+}
+
+const handlerBenchTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+{{- if benchUsesType "time.Time" .Model.Fields}}
+	"time"
+{{- end}}
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+{{- if benchUsesType "uuid.UUID" .Model.Fields}}
+	"github.com/google/uuid"
+{{- end}}
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+{{if .Project.EventSourcing}}	"{{.Project.ModuleName}}/pkg/events"
+{{end}}	"{{.Project.ModuleName}}/pkg/models"
+)
+
+// 使用内存 SQLite 启动只包含 {{.Model.Name}} 路由的测试服务器
+func setup{{.Model.Name}}Bench(b *testing.B) *httptest.Server {
+	b.Helper()
+	gin.SetMode(gin.ReleaseMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		b.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		b.Fatal(err)
+	}
+	// 每个连接都是独立的内存数据库，只保留一个连接
+	sqlDB.SetMaxOpenConns(1)
+	b.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&models.{{.Model.Name}}{}{{if .Model.VersionHistory}}, &models.{{.Model.Name}}History{}{{end}}{{if .Project.EventSourcing}}, &events.StoredEvent{}{{end}}); err != nil {
+		b.Fatal(err)
+	}
+
+	r := gin.New()
+	Register{{.Model.Name}}Routes(r.Group("/api/v1"), db{{if .Project.CQRS}}, db{{end}})
+	srv := httptest.NewServer(r)
+	b.Cleanup(srv.Close)
+	return srv
+}
+
+func new{{.Model.Name}}BenchPayload(i int) []byte {
+	payload, _ := json.Marshal(map[string]interface{}{
+{{- if eq .Model.IDType "string"}}
+		"id": fmt.Sprintf("bench-%d", i),
+{{- end}}
+{{- range .Model.Fields}}{{if ne .JsonTag "-"}}
+		"{{.JsonTag}}": {{benchValue .}},
+{{- end}}{{end}}
+	})
+	return payload
+}
+
+func create{{.Model.Name}}ForBench(b *testing.B, srv *httptest.Server, i int) models.{{.Model.Name}} {
+	b.Helper()
+	resp, err := http.Post(srv.URL+"/api/v1/{{.Model.PluralName}}", "application/json", bytes.NewReader(new{{.Model.Name}}BenchPayload(i)))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		b.Fatalf("unexpected status %d", resp.StatusCode)
+	}
+
+	var record models.{{.Model.Name}}
+	if err := json.NewDecoder(resp.Body).Decode(&record); err != nil {
+		b.Fatal(err)
+	}
+	return record
+}
+
+func BenchmarkList{{.Model.Name}}(b *testing.B) {
+	srv := setup{{.Model.Name}}Bench(b)
+	for i := 0; i < 100; i++ {
+		create{{.Model.Name}}ForBench(b, srv, i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		resp, err := http.Get(srv.URL + "/api/v1/{{.Model.PluralName}}")
+		if err != nil {
+			b.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+}
+
+func BenchmarkCreate{{.Model.Name}}(b *testing.B) {
+	srv := setup{{.Model.Name}}Bench(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		create{{.Model.Name}}ForBench(b, srv, i)
+	}
+}
+
+func BenchmarkGet{{.Model.Name}}(b *testing.B) {
+	srv := setup{{.Model.Name}}Bench(b)
+	record := create{{.Model.Name}}ForBench(b, srv, 0)
+	url := fmt.Sprintf("%s/api/v1/{{.Model.PluralName}}/%v", srv.URL, record.ID)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		resp, err := http.Get(url)
+		if err != nil {
+			b.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+}
+`
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 供网页表单自动补全使用的元数据接口，同时作为生成器能力的机器可读文档
+
+// 字段类型及其在生成项目中的映射
+type fieldTypeInfo struct {
+	Type    string `json:"type"`
+	GormTag string `json:"gorm_tag"`          // 默认 gorm 标签中列名以外的部分，为空表示由 GORM 推断
+	SQLType string `json:"sql_type"`          // MySQL 列类型
+	IDType  string `json:"id_type,omitempty"` // 可作为主键时对应的 @id 类型
+}
+
+// GET /api/field-types：支持的 ModelField.Type 及对应的 GORM 标签和 SQL 类型
+func listFieldTypes(c *gin.Context) {
+	types := make([]fieldTypeInfo, 0, len(supportedFieldTypes))
+	for _, t := range supportedFieldTypes {
+		types = append(types, fieldTypeInfo{
+			Type:    t,
+			GormTag: strings.TrimLeft(strings.TrimPrefix(defaultGormTag("", t), "column:"), ";"),
+			SQLType: sqlColumnType(t),
+			IDType:  idTypesByFieldType[t],
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"field_types": types})
+}
+
+// go-playground/validator 的验证标签
+type validatorInfo struct {
+	Tag         string `json:"tag"`
+	Param       bool   `json:"param"` // 是否需要参数，如 min=3
+	Description string `json:"description"`
+}
+
+// 常用的 go-playground/validator 标签，按用途分组
+var validatorTags = []validatorInfo{
+	{"required", false, "必填，零值视为缺失"},
+	{"omitempty", false, "为空时跳过后续校验"},
+
+	{"len", true, "长度（字符串、切片）或值等于参数"},
+	{"min", true, "最小长度或最小值"},
+	{"max", true, "最大长度或最大值"},
+	{"eq", true, "等于参数"},
+	{"ne", true, "不等于参数"},
+	{"gt", true, "大于参数"},
+	{"gte", true, "大于等于参数"},
+	{"lt", true, "小于参数"},
+	{"lte", true, "小于等于参数"},
+	{"oneof", true, "取值为空格分隔的参数之一，如 oneof=draft published"},
+
+	{"alpha", false, "只包含字母"},
+	{"alphanum", false, "只包含字母和数字"},
+	{"numeric", false, "数字字符串"},
+	{"lowercase", false, "全部小写"},
+	{"uppercase", false, "全部大写"},
+	{"contains", true, "包含参数子串"},
+	{"startswith", true, "以参数开头"},
+	{"endswith", true, "以参数结尾"},
+
+	{"email", false, "邮箱地址"},
+	{"url", false, "URL"},
+	{"uri", false, "URI"},
+	{"uuid", false, "UUID"},
+	{"uuid4", false, "UUID v4"},
+	{"ip", false, "IPv4 或 IPv6 地址"},
+	{"ipv4", false, "IPv4 地址"},
+	{"ipv6", false, "IPv6 地址"},
+	{"hostname", false, "主机名 (RFC 952)"},
+	{"json", false, "合法的 JSON 字符串"},
+	{"datetime", true, "符合参数格式的时间字符串，如 datetime=2006-01-02"},
+	{"e164", false, "E.164 格式的电话号码"},
+
+	{"eqfield", true, "等于同一结构体中参数指定的字段"},
+	{"nefield", true, "不等于同一结构体中参数指定的字段"},
+	{"required_with", true, "参数指定的字段存在时必填"},
+	{"required_without", true, "参数指定的字段不存在时必填"},
+	{"dive", false, "对切片或 map 的每个元素应用后续校验"},
+}
+
+// GET /api/validators：可用于字段校验的 go-playground/validator 标签
+func listValidators(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"validators": validatorTags})
+}
@@ -0,0 +1,99 @@
+package main
+
+import "strings"
+
+// git-chglog 配置模板，ChangelogEnabled 启用时生成。
+// .chglog/CHANGELOG.tpl.md 本身是 git-chglog 的 Go 模板，原样写入
+
+type changelogFilesGenerator struct {
+	templateGenerator
+}
+
+var changelogGenerator = changelogFilesGenerator{templateGenerator{
+	name: "changelog",
+	files: func(data TemplateData) map[string]string {
+		files := map[string]string{}
+		if data.Project.ChangelogEnabled {
+			files[".chglog/config.yml"] = "chglog_config.yml.tmpl"
+			files["CHANGELOG.md"] = "CHANGELOG.md.tmpl"
+		}
+		return files
+	},
+}}
+
+func (g changelogFilesGenerator) Generate(data TemplateData, fs FileSystem) error {
+	if !data.Project.ChangelogEnabled {
+		return nil
+	}
+	if err := fs.WriteFile(".chglog/CHANGELOG.tpl.md", []byte(chglogTemplate)); err != nil {
+		return err
+	}
+	return g.templateGenerator.Generate(data, fs)
+}
+
+// 仓库网页地址，用于生成版本对比链接
+func (p ProjectConfig) RepositoryURL() string {
+	return strings.TrimSuffix(p.GitRepoURL(), ".git")
+}
+
+const chglogConfigTemplate = `style: github
+template: CHANGELOG.tpl.md
+info:
+  title: CHANGELOG
+  repository_url: {{.Project.RepositoryURL}}
+options:
+  commits:
+    filters:
+      Type:
+        - feat
+        - fix
+        - perf
+        - refactor
+        - docs
+  # 按 Conventional Commits 类型分组
+  commit_groups:
+    title_maps:
+      feat: Features
+      fix: Bug Fixes
+      perf: Performance Improvements
+      refactor: Code Refactoring
+      docs: Documentation
+  header:
+    pattern: "^(\\w*)(?:\\(([\\w\\$\\.\\-\\*\\s]*)\\))?\\:\\s(.*)$"
+    pattern_maps:
+      - Type
+      - Scope
+      - Subject
+  notes:
+    keywords:
+      - BREAKING CHANGE
+`
+
+const changelogStubTemplate = `# CHANGELOG
+
+由 git-chglog 根据 Conventional Commits 提交记录生成，发布新版本前运行 make changelog 更新。
+`
+
+const chglogTemplate = `{{ range .Versions }}
+<a name="{{ .Tag.Name }}"></a>
+## {{ if .Tag.Previous }}[{{ .Tag.Name }}]({{ $.Info.RepositoryURL }}/compare/{{ .Tag.Previous.Name }}...{{ .Tag.Name }}){{ else }}{{ .Tag.Name }}{{ end }} ({{ datetime "2006-01-02" .Tag.Date }})
+
+{{ range .CommitGroups -}}
+### {{ .Title }}
+
+{{ range .Commits -}}
+* {{ if .Scope }}**{{ .Scope }}:** {{ end }}{{ .Subject }}
+{{ end }}
+{{ end -}}
+
+{{- if .NotesGroups -}}
+{{ range .NotesGroups -}}
+### {{ .Title }}
+
+{{ range .Notes }}
+{{ .Body }}
+{{ end }}
+{{ end -}}
+{{ end -}}
+{{ end -}}
+`
@@ -0,0 +1,340 @@
+package main
+
+// 聊天室模板，ChatEnabled 启用时生成 pkg/chat（WebSocket 连接和通过 Redis Pub/Sub 在多个实例间转发消息的 Hub）、
+// GET /ws/chat/:roomId 接口和保存聊天记录的 Message 模型
+
+const chatMessageModelName = "Message"
+
+// addChatMessageModel 为 Message 模型补充的字段
+var chatMessageModelFields = []injectedField{
+	{"RoomID", "string", true},
+	{"SenderID", "string", false},
+	{"Content", "string", true},
+	{"SentAt", "time.Time", false},
+}
+
+// 添加 Message 模型，已存在时只补充缺少的字段
+func addChatMessageModel(data *TemplateData) error {
+	_, err := addInjectedModel(data, chatMessageModelName, chatMessageModelFields)
+	return err
+}
+
+var chatGenerator = templateGenerator{
+	name: "chat",
+	files: func(data TemplateData) map[string]string {
+		if !data.Project.ChatEnabled {
+			return nil
+		}
+		return map[string]string{
+			"pkg/chat/hub.go":      "chat_hub.go.tmpl",
+			"pkg/chat/client.go":   "chat_client.go.tmpl",
+			"pkg/handlers/chat.go": "chat_handler.go.tmpl",
+		}
+	},
+}
+
+const chatHubTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package chat
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"gorm.io/gorm"
+
+	"{{.Project.ModuleName}}/pkg/models"
+)
+
+// 房间 roomID 的消息发布到 Redis 频道 chat:<roomID>
+const channelPrefix = "chat:"
+
+// Redis 订阅断开后重新订阅的间隔
+const resubscribeInterval = 5 * time.Second
+
+// Hub 管理本实例上各房间的 WebSocket 连接。消息保存后发布到 Redis，每个实例都订阅 chat:*
+// 并推送给本实例上该房间的连接，因此多实例部署时连接到不同实例的用户也能互相收到消息
+type Hub struct {
+	db   *gorm.DB
+	pool *redis.Pool
+
+	mu    sync.RWMutex
+	rooms map[string]map[*Client]struct{}
+}
+
+func NewHub(redisAddr string, db *gorm.DB) *Hub {
+	return &Hub{
+		db: db,
+		pool: &redis.Pool{
+			MaxIdle:     10,
+			IdleTimeout: 5 * time.Minute,
+			Dial: func() (redis.Conn, error) {
+				return redis.Dial("tcp", redisAddr)
+			},
+		},
+		rooms: make(map[string]map[*Client]struct{}),
+	}
+}
+
+// Run 订阅 Redis 并把消息推送给本实例的连接，订阅断开后自动重试，在单独的 goroutine 中运行
+func (h *Hub) Run() {
+	for {
+		if err := h.subscribe(); err != nil {
+			log.Printf("chat: redis subscription: %v", err)
+		}
+		time.Sleep(resubscribeInterval)
+	}
+}
+
+func (h *Hub) subscribe() error {
+	conn := h.pool.Get()
+	defer conn.Close()
+
+	psc := redis.PubSubConn{Conn: conn}
+	if err := psc.PSubscribe(channelPrefix + "*"); err != nil {
+		return err
+	}
+	for {
+		switch v := psc.Receive().(type) {
+		case redis.Message:
+			h.broadcast(strings.TrimPrefix(v.Channel, channelPrefix), v.Data)
+		case error:
+			return v
+		}
+	}
+}
+
+// Publish 保存消息并发布到 Redis；Redis 不可用时只推送给本实例的连接
+func (h *Hub) Publish(msg *models.Message) error {
+	msg.SentAt = time.Now()
+	if err := h.db.Create(msg).Error; err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	conn := h.pool.Get()
+	defer conn.Close()
+	if _, err := conn.Do("PUBLISH", channelPrefix+msg.RoomID, payload); err != nil {
+		log.Printf("chat: publishing to redis: %v", err)
+		h.broadcast(msg.RoomID, payload)
+	}
+	return nil
+}
+
+func (h *Hub) register(c *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.rooms[c.roomID] == nil {
+		h.rooms[c.roomID] = make(map[*Client]struct{})
+	}
+	h.rooms[c.roomID][c] = struct{}{}
+}
+
+// unregister 移除连接并关闭其发送队列，可重复调用
+func (h *Hub) unregister(c *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	clients := h.rooms[c.roomID]
+	if _, ok := clients[c]; !ok {
+		return
+	}
+	delete(clients, c)
+	close(c.send)
+	if len(clients) == 0 {
+		delete(h.rooms, c.roomID)
+	}
+}
+
+// broadcast 推送给本实例上该房间的所有连接，发送队列已满的连接会被断开
+func (h *Hub) broadcast(roomID string, payload []byte) {
+	var slow []*Client
+	h.mu.RLock()
+	for c := range h.rooms[roomID] {
+		select {
+		case c.send <- payload:
+		default:
+			slow = append(slow, c)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, c := range slow {
+		h.unregister(c)
+	}
+}
+`
+
+const chatClientTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package chat
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"{{.Project.ModuleName}}/pkg/models"
+)
+
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = pongWait * 9 / 10
+	maxMessageSize = 4096
+	sendQueueSize  = 64
+)
+
+// Client 是聊天室中的一个 WebSocket 连接，收到的文本消息以 SenderID 的身份发送到所在房间
+type Client struct {
+	hub      *Hub
+	conn     *websocket.Conn
+	send     chan []byte
+	roomID   string
+	senderID string
+}
+
+func NewClient(hub *Hub, conn *websocket.Conn, roomID, senderID string) *Client {
+	return &Client{
+		hub:      hub,
+		conn:     conn,
+		send:     make(chan []byte, sendQueueSize),
+		roomID:   roomID,
+		senderID: senderID,
+	}
+}
+
+// Serve 加入房间并处理连接上的读写，连接关闭后返回
+func (c *Client) Serve() {
+	c.hub.register(c)
+	go c.writePump()
+	c.readPump()
+}
+
+func (c *Client) readPump() {
+	defer func() {
+		c.hub.unregister(c)
+		c.conn.Close()
+	}()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				log.Printf("chat: reading from %s: %v", c.senderID, err)
+			}
+			return
+		}
+
+		content := strings.TrimSpace(string(data))
+		if content == "" {
+			continue
+		}
+		msg := models.Message{RoomID: c.roomID, SenderID: c.senderID, Content: content}
+		if err := c.hub.Publish(&msg); err != nil {
+			log.Printf("chat: saving message: %v", err)
+		}
+	}
+}
+
+// writePump 把房间中的消息写入连接并定时发送 ping，Hub 关闭发送队列后关闭连接
+func (c *Client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case payload, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+`
+
+const chatHandlerTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package handlers
+
+import (
+{{- if eq .Project.AuthMode "session"}}
+	"fmt"
+{{- end}}
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"{{.Project.ModuleName}}/pkg/chat"
+{{- if eq .Project.AuthMode "session"}}
+	"{{.Project.ModuleName}}/pkg/middlewares"
+{{- end}}
+)
+
+// 房间 ID 会保存到每条 Message 记录中
+const maxRoomIDLength = 255
+
+// 默认只接受与服务同源的页面发起的连接
+var chatUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+// RegisterChatRoutes 注册 GET /:roomId，升级为 WebSocket 后加入房间，发送的文本消息保存为 Message 并推送给房间内所有连接
+func RegisterChatRoutes(rg *gin.RouterGroup, hub *chat.Hub) {
+	rg.GET("/:roomId", joinChatRoom(hub))
+}
+
+func joinChatRoom(hub *chat.Hub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		roomID := c.Param("roomId")
+		if len(roomID) > maxRoomIDLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "room id is too long"})
+			return
+		}
+
+		conn, err := chatUpgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			// Upgrade 已写入错误响应
+			return
+		}
+		chat.NewClient(hub, conn, roomID, chatSenderID(c)).Serve()
+	}
+}
+
+{{if eq .Project.AuthMode "none"}}// 未启用认证，发送者为 ?name= 参数，未传时为 anonymous
+{{else}}// 发送者为认证中间件设置的用户标识
+{{end}}func chatSenderID(c *gin.Context) string {
+{{- if eq .Project.AuthMode "session"}}
+	return fmt.Sprint(c.MustGet(middlewares.SessionUserKey))
+{{- else if eq .Project.AuthMode "magiclink"}}
+	return c.GetString("email")
+{{- else}}
+	return c.DefaultQuery("name", "anonymous")
+{{- end}}
+}
+`
@@ -0,0 +1,234 @@
+package main
+
+// CI 相关模板：GitHub Actions 工作流和 issue、PR 模板（GithubActionsEnabled）、pre-commit 钩子（PreCommitEnabled）
+// 和 Renovate 依赖更新配置（RenovateEnabled）
+
+const ciWorkflowTemplate = `name: CI
+
+on:
+  push:
+    branches: [main]
+  pull_request:
+
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - uses: actions/setup-go@v5
+        with:
+          go-version: "{{.Project.GoVersion}}"
+      - name: Test
+        run: make test
+      - name: Vet
+        run: go vet ./...
+{{- if .Project.VulnScanEnabled}}
+      - name: Vulnerability scan
+        run: |
+          go install golang.org/x/vuln/cmd/govulncheck@latest
+          make vuln
+{{- end}}
+`
+
+const releaseWorkflowTemplate = `name: Release
+
+on:
+  push:
+    tags: ["v*"]
+
+permissions:
+  contents: write
+
+jobs:
+  release:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+{{- if .Project.ChangelogEnabled}}
+        with:
+          # git-chglog 需要完整的提交和标签历史
+          fetch-depth: 0
+{{- end}}
+      - uses: actions/setup-go@v5
+        with:
+          go-version: "{{.Project.GoVersion}}"
+      - name: Build
+        run: make build
+{{- if .Project.SBOMEnabled}}
+      - name: Generate SBOM
+        run: |
+          go install github.com/CycloneDX/cyclonedx-gomod/cmd/cyclonedx-gomod@latest
+          make sbom
+{{- end}}
+{{- if .Project.ChangelogEnabled}}
+      - name: Generate release notes
+        run: |
+          go install github.com/git-chglog/git-chglog/cmd/git-chglog@latest
+          git-chglog -o RELEASE_NOTES.md ${{"{{"}} github.ref_name }}
+{{- end}}
+      - uses: softprops/action-gh-release@v2
+        with:
+{{- if .Project.ChangelogEnabled}}
+          body_path: RELEASE_NOTES.md
+{{- end}}
+          files: |
+            bin/{{.Project.ProjectName}}
+{{- if .Project.SBOMEnabled}}
+            .sbom/sbom.xml
+{{- end}}
+`
+
+// 使用本地已安装的工具，不依赖 pre-commit 拉取远程仓库
+const preCommitConfigTemplate = `repos:
+  - repo: local
+    hooks:
+      - id: goimports
+        name: goimports
+        entry: goimports -w
+        language: system
+        types: [go]
+      - id: go-vet
+        name: go vet
+        entry: go vet ./...
+        language: system
+        types: [go]
+        pass_filenames: false
+      - id: golangci-lint
+        name: golangci-lint
+        entry: golangci-lint run
+        language: system
+        types: [go]
+        pass_filenames: false
+      # -short 跳过耗时较长的集成测试
+      - id: go-test
+        name: go test
+        entry: go test -short ./...
+        language: system
+        types: [go]
+        pass_filenames: false
+`
+
+// 补丁版本自动合并，次版本按生态分组，每周一早上集中提交
+const renovateConfigTemplate = `{
+  "$schema": "https://docs.renovatebot.com/renovate-schema.json",
+  "extends": ["config:recommended"],
+  "baseBranches": ["main"],
+  "schedule": ["before 6am on monday"],
+  "postUpdateOptions": ["gomodTidy"],
+  "packageRules": [
+    {
+      "matchUpdateTypes": ["patch"],
+      "automerge": true
+    },
+    {
+      "matchManagers": ["gomod"],
+      "matchUpdateTypes": ["minor"],
+      "groupName": "go modules"
+    },
+    {
+      "matchManagers": ["dockerfile"],
+      "matchUpdateTypes": ["minor"],
+      "groupName": "docker base images"
+    }
+  ]
+}
+`
+
+// GitHub 结构化 issue 表单，环境信息中的 Go 版本默认填入项目使用的版本
+const bugReportTemplate = `name: 问题报告
+description: 报告一个错误或异常行为
+labels: [bug]
+body:
+  - type: markdown
+    attributes:
+      value: 提交前请先搜索是否已有相同的 issue。
+  - type: textarea
+    id: steps
+    attributes:
+      label: 复现步骤
+      description: 尽量提供最小可复现的请求，如 curl 命令和请求体
+      placeholder: |
+        1. POST /api/v1/...
+        2. ...
+    validations:
+      required: true
+  - type: textarea
+    id: expected
+    attributes:
+      label: 期望行为
+    validations:
+      required: true
+  - type: textarea
+    id: actual
+    attributes:
+      label: 实际行为
+      description: 包括响应状态码、响应体和相关日志
+      render: shell
+    validations:
+      required: true
+  - type: input
+    id: version
+    attributes:
+      label: {{.Project.ProjectName}} 版本
+      description: 发布版本号或提交哈希
+    validations:
+      required: true
+  - type: input
+    id: go-version
+    attributes:
+      label: Go 版本
+      value: "{{.Project.GoVersion}}"
+  - type: dropdown
+    id: os
+    attributes:
+      label: 操作系统
+      options:
+        - Linux
+        - macOS
+        - Windows
+        - Docker
+  - type: input
+    id: database
+    attributes:
+      label: 数据库版本
+      placeholder: MySQL 8.0
+`
+
+const featureRequestTemplate = `name: 功能建议
+description: 提出新功能或改进建议
+labels: [enhancement]
+body:
+  - type: textarea
+    id: problem
+    attributes:
+      label: 要解决的问题
+      description: 描述当前的不便之处或使用场景
+    validations:
+      required: true
+  - type: textarea
+    id: solution
+    attributes:
+      label: 期望的方案
+      description: 如涉及接口变更，请给出请求和响应示例
+    validations:
+      required: true
+  - type: textarea
+    id: alternatives
+    attributes:
+      label: 考虑过的其他方案
+`
+
+const pullRequestTemplate = `## 改动内容
+
+<!-- 说明改动内容和原因，关联的 issue 写作 Closes #123 -->
+
+## 检查清单
+
+- [ ] 新增或修改的代码有相应的测试，make test 通过
+- [ ] 接口变更已同步更新 api/ 下的 OpenAPI 规范和 README
+- [ ] 不包含破坏性变更；如有，已在下方说明迁移方式，并在提交信息中标注 BREAKING CHANGE
+
+## 破坏性变更
+
+<!-- 无则删除本节 -->
+`
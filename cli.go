@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+// 命令行模式: 读取 /export-config 导出的表单参数（JSON 或 YAML），不启动服务器直接生成项目
+//
+//	gin-crud-generator -config project.yaml [-o project.zip] [-dry-run] [-go-sum]
+func runCLI(args []string) error {
+	flags := flag.NewFlagSet("gin-crud-generator", flag.ExitOnError)
+	configPath := flags.String("config", "", "项目配置文件，格式同 /export-config 的输出")
+	output := flags.String("o", "", "生成的ZIP路径，默认为 <project_name>.zip")
+	dryRun := flags.Bool("dry-run", false, "只列出将要生成的文件及大小，不写入磁盘")
+	goSum := flags.Bool("go-sum", false, "运行 go mod tidy 生成 go.sum，需要联网，同配置文件中的 go_sum: on")
+	flags.Parse(args)
+	if *configPath == "" {
+		return errors.New("缺少 -config 参数")
+	}
+
+	content, err := os.ReadFile(*configPath)
+	if err != nil {
+		return fmt.Errorf("无法读取配置文件: %w", err)
+	}
+	// YAML 是 JSON 的超集，两种格式都可以直接解析
+	var fields map[string]string
+	if err := yaml.Unmarshal(content, &fields); err != nil {
+		return fmt.Errorf("无法解析配置文件: %w", err)
+	}
+	if *goSum {
+		if fields == nil {
+			fields = make(map[string]string)
+		}
+		fields["go_sum"] = "on"
+	}
+
+	goSumAllowed = true
+	gin.SetMode(gin.ReleaseMode)
+	data, err := templateDataFromFields(fields)
+	if err != nil {
+		return err
+	}
+
+	files := NewMemFileSystem()
+	if err := generateProjectStructure(files, data); err != nil {
+		return fmt.Errorf("无法生成项目: %w", err)
+	}
+
+	if *dryRun {
+		return printFileTable(files)
+	}
+
+	path := *output
+	if path == "" {
+		path = data.Project.ProjectName + ".zip"
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("无法创建ZIP文件: %w", err)
+	}
+	if err := files.WriteZip(f); err != nil {
+		f.Close()
+		return fmt.Errorf("无法创建ZIP文件: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("无法创建ZIP文件: %w", err)
+	}
+	fmt.Printf("已生成 %s（%d 个文件）\n", path, len(files.Files))
+	return nil
+}
+
+// 复用 /generate 的表单解析和校验，校验失败时返回响应中的错误信息
+func templateDataFromFields(fields map[string]string) (TemplateData, error) {
+	form := url.Values{}
+	for key, value := range fields {
+		form.Set(key, value)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/generate", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = req
+
+	data, ok := bindTemplateData(c)
+	if !ok {
+		return data, fmt.Errorf("配置无效: %s", strings.TrimSpace(recorder.Body.String()))
+	}
+	return data, nil
+}
+
+// 以表格形式输出文件路径和字节数
+func printFileTable(files *MemFileSystem) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	total := 0
+	for _, path := range files.Paths() {
+		size := len(files.Files[path])
+		total += size
+		fmt.Fprintf(w, "%d\t  %s\n", size, path)
+	}
+	fmt.Fprintf(w, "%d\t  共 %d 个文件\n", total, len(files.Files))
+	return w.Flush()
+}
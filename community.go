@@ -0,0 +1,205 @@
+package main
+
+// 社区文件模板，GenerateCommunityFiles 启用时生成，GitHub 会在仓库的 Community 页面中识别
+
+var communityGenerator = templateGenerator{
+	name: "community",
+	files: func(data TemplateData) map[string]string {
+		files := map[string]string{}
+		if data.Project.GenerateCommunityFiles {
+			files["CONTRIBUTING.md"] = "CONTRIBUTING.md.tmpl"
+			files["CODE_OF_CONDUCT.md"] = "CODE_OF_CONDUCT.md.tmpl"
+		}
+		return files
+	},
+}
+
+const contributingTemplate = `# 参与贡献 {{.Project.ProjectName}}
+
+感谢你愿意参与贡献！提交代码前请先阅读本指南和[行为准则](CODE_OF_CONDUCT.md)。
+
+## 分支策略
+{{if eq .Project.BranchingStrategy "gitflow"}}
+本项目使用 GitFlow:
+
+- **main**: 只包含已发布的版本，每次合并都打 v* 标签
+- **develop**: 日常集成分支，功能分支从这里创建并合并回这里
+- **feature/<名称>**: 新功能，从 develop 创建，完成后向 develop 提交 PR
+- **release/<版本>**: 发布前的准备，从 develop 创建，只修复问题，完成后合并到 main 和 develop
+- **hotfix/<名称>**: 线上紧急修复，从 main 创建，完成后合并到 main 和 develop
+{{else}}
+本项目使用主干开发 (trunk-based):
+
+- **main** 是唯一的长期分支，始终保持可发布状态
+- 从 main 创建短期分支 (如 fix/login-timeout)，尽量在一两天内合并
+- 未完成的功能通过配置开关隐藏，不要长期保留分支
+- 发布时直接在 main 上打 v* 标签
+{{end}}
+## 提交 Pull Request
+
+1. Fork 仓库并从{{if eq .Project.BranchingStrategy "gitflow"}} develop {{else}} main {{end}}创建分支
+2. 提交信息使用 [Conventional Commits](https://www.conventionalcommits.org/) 格式，如 ` + "`feat: 支持批量删除`" + `、` + "`fix: 修复分页越界`" + `
+3. 确认本地测试通过后提交 PR，在描述中说明改动内容和原因，关联相关 issue
+4. 至少需要一位维护者审核通过{{if .Project.GithubActionsEnabled}}，且 CI 通过{{end}}后才能合并
+5. 审核意见请通过追加提交处理，合并时会压缩为一个提交
+
+## 编码规范
+
+- 代码使用 gofmt 格式化，导入分组使用 goimports
+- 提交前运行 ` + "`go vet ./...`" + `，不引入新的警告
+- 导出的类型和函数需要有文档注释
+- 错误需要处理或返回，不要忽略
+- 新增接口时同步更新 api/ 下的 OpenAPI 规范
+{{- if .Project.PreCommitEnabled}}
+- 运行 ` + "`make install-hooks`" + ` 安装 pre-commit 钩子，提交时会自动检查以上内容
+{{- end}}
+
+## 运行测试
+
+` + "```bash" + `
+make test
+{{- if .Project.GenerateTests}}
+make bench   # 处理器基准测试，结果保存到 bench_results.txt
+{{- end}}
+{{- if .Project.VulnScanEnabled}}
+make vuln    # 依赖漏洞扫描
+{{- end}}
+` + "```" + `
+
+新增功能或修复问题时请附带相应的测试。
+`
+
+const codeOfConductTemplate = `# Contributor Covenant Code of Conduct
+
+## Our Pledge
+
+We as members, contributors, and leaders pledge to make participation in our
+community a harassment-free experience for everyone, regardless of age, body
+size, visible or invisible disability, ethnicity, sex characteristics, gender
+identity and expression, level of experience, education, socio-economic status,
+nationality, personal appearance, race, caste, color, religion, or sexual identity
+and orientation.
+
+We pledge to act and interact in ways that contribute to an open, welcoming,
+diverse, inclusive, and healthy community.
+
+## Our Standards
+
+Examples of behavior that contributes to a positive environment for our
+community include:
+
+* Demonstrating empathy and kindness toward other people
+* Being respectful of differing opinions, viewpoints, and experiences
+* Giving and gracefully accepting constructive feedback
+* Accepting responsibility and apologizing to those affected by our mistakes,
+  and learning from the experience
+* Focusing on what is best not just for us as individuals, but for the
+  overall community
+
+Examples of unacceptable behavior include:
+
+* The use of sexualized language or imagery, and sexual attention or
+  advances of any kind
+* Trolling, insulting or derogatory comments, and personal or political attacks
+* Public or private harassment
+* Publishing others' private information, such as a physical or email
+  address, without their explicit permission
+* Other conduct which could reasonably be considered inappropriate in a
+  professional setting
+
+## Enforcement Responsibilities
+
+Community leaders are responsible for clarifying and enforcing our standards of
+acceptable behavior and will take appropriate and fair corrective action in
+response to any behavior that they deem inappropriate, threatening, offensive,
+or harmful.
+
+Community leaders have the right and responsibility to remove, edit, or reject
+comments, commits, code, wiki edits, issues, and other contributions that are
+not aligned to this Code of Conduct, and will communicate reasons for moderation
+decisions when appropriate.
+
+## Scope
+
+This Code of Conduct applies within all community spaces, and also applies when
+an individual is officially representing the community in public spaces.
+Examples of representing our community include using an official e-mail address,
+posting via an official social media account, or acting as an appointed
+representative at an online or offline event.
+
+## Enforcement
+
+Instances of abusive, harassing, or otherwise unacceptable behavior may be
+reported to the community leaders responsible for enforcement at
+[{{.Project.AuthorEmail}}](mailto:{{.Project.AuthorEmail}}).
+All complaints will be reviewed and investigated promptly and fairly.
+
+All community leaders are obligated to respect the privacy and security of the
+reporter of any incident.
+
+## Enforcement Guidelines
+
+Community leaders will follow these Community Impact Guidelines in determining
+the consequences for any action they deem in violation of this Code of Conduct:
+
+### 1. Correction
+
+**Community Impact**: Use of inappropriate language or other behavior deemed
+unprofessional or unwelcome in the community.
+
+**Consequence**: A private, written warning from community leaders, providing
+clarity around the nature of the violation and an explanation of why the
+behavior was inappropriate. A public apology may be requested.
+
+### 2. Warning
+
+**Community Impact**: A violation through a single incident or series
+of actions.
+
+**Consequence**: A warning with consequences for continued behavior. No
+interaction with the people involved, including unsolicited interaction with
+those enforcing the Code of Conduct, for a specified period of time. This
+includes avoiding interactions in community spaces as well as external channels
+like social media. Violating these terms may lead to a temporary or
+permanent ban.
+
+### 3. Temporary Ban
+
+**Community Impact**: A serious violation of community standards, including
+sustained inappropriate behavior.
+
+**Consequence**: A temporary ban from any sort of interaction or public
+communication with the community for a specified period of time. No public or
+private interaction with the people involved, including unsolicited interaction
+with those enforcing the Code of Conduct, is allowed during this period.
+Violating these terms may lead to a permanent ban.
+
+### 4. Permanent Ban
+
+**Community Impact**: Demonstrating a pattern of violation of community
+standards, including sustained inappropriate behavior, harassment of an
+individual, or aggression toward or disparagement of classes of individuals.
+
+**Consequence**: A permanent ban from any sort of public interaction within
+the community.
+
+## Attribution
+
+This Code of Conduct is adapted from the [Contributor Covenant][homepage],
+version 2.1, available at
+[https://www.contributor-covenant.org/version/2/1/code_of_conduct.html][v2.1].
+
+Community Impact Guidelines were inspired by
+[Mozilla's code of conduct enforcement ladder][Mozilla CoC].
+
+For answers to common questions about this code of conduct, see the FAQ at
+[https://www.contributor-covenant.org/faq][FAQ]. Translations are available
+at [https://www.contributor-covenant.org/translations][translations].
+
+[homepage]: https://www.contributor-covenant.org
+[v2.1]: https://www.contributor-covenant.org/version/2/1/code_of_conduct.html
+[Mozilla CoC]: https://github.com/mozilla/diversity
+[FAQ]: https://www.contributor-covenant.org/faq
+[translations]: https://www.contributor-covenant.org/translations
+
+`
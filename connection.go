@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试连接的超时时间，包括建立连接和执行 SELECT 1
+const testConnectionTimeout = 5 * time.Second
+
+// POST /test-connection：用表单中的数据库参数（同 /introspect）建立连接并执行 SELECT 1。
+// 连接失败时仍返回 200，error 为驱动返回的错误；不记录任何连接参数
+func testConnection(c *gin.Context) {
+	driver, dsn, err := databaseDSN(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"connected": false, "error": err.Error()})
+		return
+	}
+
+	if err := pingDatabase(c.Request.Context(), driver, dsn); err != nil {
+		c.JSON(http.StatusOK, gin.H{"connected": false, "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"connected": true})
+}
+
+func pingDatabase(ctx context.Context, driver, dsn string) error {
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, testConnectionTimeout)
+	defer cancel()
+
+	var one int
+	return db.QueryRowContext(ctx, "SELECT 1").Scan(&one)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// 模型的 Validate 方法，在 BeforeCreate、BeforeUpdate 钩子中检查必填字段和字符串长度，
+// 不依赖请求绑定，直接通过 GORM 写入数据时同样生效
+
+// 解析 minlen:N、maxlen:N 中的长度，不是非负整数时返回 -1，由 ValidateModel 报错
+func parseLengthLimit(value string) int {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return -1
+	}
+	return n
+}
+
+// 校验错误中的字段名，与接口中的 JSON 字段一致
+func (f ModelField) ValidationName() string {
+	if f.JsonTag == "" || f.JsonTag == "-" {
+		return f.ColumnName()
+	}
+	return f.JsonTag
+}
+
+// 必填字段为零值的判断条件，bool 字段不能标记为 required，见 ValidateModel
+func (f ModelField) ZeroCheck() string {
+	field := "m." + f.Name
+	switch f.Type {
+	case "string":
+		return field + ` == ""`
+	case "int", "int32", "int64", "uint", "uint32", "uint64", "float32", "float64":
+		return field + " == 0"
+	case "time.Time":
+		return field + ".IsZero()"
+	case "uuid.UUID":
+		return field + " == uuid.Nil"
+	}
+	return ""
+}
+
+// 是否生成 Validate 方法
+func (m Model) HasValidation() bool {
+	for _, f := range m.Fields {
+		if (f.Required && f.ZeroCheck() != "") || f.MinLength > 0 || f.MaxLength > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// 是否有字段声明了长度限制，决定是否导入 unicode/utf8
+func (m Model) HasLengthLimits() bool {
+	for _, f := range m.Fields {
+		if f.MinLength > 0 || f.MaxLength > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// 模板自行生成的钩子方法，同名的自定义钩子代码追加到其方法体中
+func (m Model) GeneratedHook(name string) bool {
+	switch name {
+	case "BeforeCreate":
+		return m.IDType == "uuid" || m.HasValidation() || m.PushNotification
+	case "BeforeUpdate":
+		return m.HasValidation()
+	case "AfterCreate":
+		return m.SlackNotify || m.PublishNotification || m.SearchEnabled
+	case "AfterUpdate", "AfterDelete":
+		return m.SearchEnabled
+	}
+	return false
+}
+
+// 是否有模型生成 Validate 方法，决定是否生成 ValidationError
+func (data TemplateData) UsesValidation() bool {
+	for _, m := range data.Models {
+		if m.HasValidation() {
+			return true
+		}
+	}
+	return false
+}
+
+// 长度限制只适用于字符串字段，返回不合法的原因
+func fieldLengthProblem(f ModelField) string {
+	switch {
+	case f.MinLength == 0 && f.MaxLength == 0:
+		return ""
+	case f.MinLength < 0 || f.MaxLength < 0:
+		return "minlen 和 maxlen 必须是非负整数"
+	case f.Type != "string":
+		return "只有 string 字段可以设置 minlen 和 maxlen"
+	case f.MaxLength > 0 && f.MinLength > f.MaxLength:
+		return "minlen 不能大于 maxlen"
+	}
+	return ""
+}
+
+// 模型定义中的长度限制，formatModels 输出时使用
+func lengthLimitTags(f ModelField) []string {
+	var tags []string
+	if f.MinLength > 0 {
+		tags = append(tags, "minlen:"+strconv.Itoa(f.MinLength))
+	}
+	if f.MaxLength > 0 {
+		tags = append(tags, "maxlen:"+strconv.Itoa(f.MaxLength))
+	}
+	return tags
+}
+
+// 模板在 BeforeCreate、BeforeUpdate 开头生成的 Validate 调用，导出配置还原钩子时去掉
+var validateCallLines = []string{"if err := m.Validate(); err != nil {", "return err", "}"}
+
+func stripValidateCall(lines []string) []string {
+	return stripLeadingLines(lines, validateCallLines)
+}
+
+// lines 以 prefix 开头（忽略缩进）时去掉这几行
+func stripLeadingLines(lines, prefix []string) []string {
+	if len(lines) < len(prefix) {
+		return lines
+	}
+	for i, line := range prefix {
+		if strings.TrimSpace(lines[i]) != line {
+			return lines
+		}
+	}
+	return lines[len(prefix):]
+}
+
+const validationErrorTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package models
+
+import "fmt"
+
+// ValidationError 由模型的 Validate 方法返回，处理器将其转换为 422 响应
+type ValidationError struct {
+	Field string // JSON 字段名
+	Rule  string // "required"、"min_length" 或 "max_length"
+	Limit int    // 长度限制，Rule 为 required 时为 0
+}
+
+func (e *ValidationError) Error() string {
+	switch e.Rule {
+	case "min_length":
+		return fmt.Sprintf("%s must be at least %d characters", e.Field, e.Limit)
+	case "max_length":
+		return fmt.Sprintf("%s must be at most %d characters", e.Field, e.Limit)
+	}
+	return e.Field + " is required"
+}
+`
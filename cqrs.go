@@ -0,0 +1,112 @@
+package main
+
+import "strings"
+
+// CQRS 模板，CQRS 启用时为每个模型生成 pkg/commands（写操作，使用完整的 GORM 模型和主库）
+// 和 pkg/queries（读操作，使用只含展示字段的只读模型和 DB_READ_HOST 只读库）
+
+// 只读模型的字段，不含 json:"-" 的字段和 password 字段，列表、详情接口不需要返回密码哈希
+func (m Model) ViewFields() []ModelField {
+	var fields []ModelField
+	for _, f := range m.EventFields() {
+		if !strings.EqualFold(f.Name, "password") {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+var cqrsGenerator = templateGenerator{
+	name: "cqrs",
+	modelFiles: func(data TemplateData, model Model) map[string]string {
+		if !data.Project.CQRS {
+			return nil
+		}
+		return map[string]string{
+			"pkg/commands/" + model.SnakeName + ".go": "cqrs_commands.go.tmpl",
+			"pkg/queries/" + model.SnakeName + ".go":  "cqrs_queries.go.tmpl",
+		}
+	},
+}
+
+const cqrsCommandsTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package commands
+
+import (
+{{- if eq .Model.IDType "uuid"}}
+	"github.com/google/uuid"
+{{- end}}
+	"gorm.io/gorm"
+
+	"{{.Project.ModuleName}}/pkg/models"
+)
+
+// Create{{.Model.Name}} 在主库中创建 {{.Model.Name}}
+func Create{{.Model.Name}}(db *gorm.DB, m *models.{{.Model.Name}}) *gorm.DB {
+	return db.Create(m)
+}
+
+// Update{{.Model.Name}} 在主库中保存 {{.Model.Name}} 的所有字段
+func Update{{.Model.Name}}(db *gorm.DB, m *models.{{.Model.Name}}) *gorm.DB {
+	return db.Save(m)
+}
+
+// Delete{{.Model.Name}} 在主库中删除 {{.Model.Name}}{{if .Model.DeleteLoadsRecord}}，记录不存在时返回 gorm.ErrRecordNotFound{{end}}
+func Delete{{.Model.Name}}(db *gorm.DB, id {{.Model.IDGoType}}) *gorm.DB {
+{{- if .Model.DeleteLoadsRecord}}
+	// 先查询出记录，AfterDelete 钩子才能{{if .Model.VersionHistory}}写入完整的历史快照{{else}}删除搜索索引中的文档{{end}}
+	var m models.{{.Model.Name}}
+	if result := db.First(&m, "id = ?", id); result.Error != nil {
+		return result
+	}
+	return db.Delete(&m)
+{{- else}}
+	return db.Delete(&models.{{.Model.Name}}{}, "id = ?", id)
+{{- end}}
+}
+`
+
+const cqrsQueriesTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package queries
+
+import (
+	"time"
+{{if .Model.EventUsesType "uuid.UUID"}}
+	"github.com/google/uuid"
+{{- end}}
+	"gorm.io/gorm"
+)
+
+// {{.Model.Name}}View 是 {{.Model.Name}} 列表和详情接口使用的只读模型，不含 json:"-" 的字段和密码
+type {{.Model.Name}}View struct {
+	ID {{.Model.IDGoType}} ` + "`json:\"id\"`" + `
+	{{range .Model.ViewFields}}{{.Name}} {{.Type}} ` + "`gorm:\"column:{{.ColumnName}}\" json:\"{{.JsonTag}}{{if .Required}},omitempty{{end}}\"`" + `
+	{{end}}CreatedAt time.Time ` + "`json:\"{{.Project.JSONTag \"CreatedAt\"}}\"`" + `
+	UpdatedAt time.Time ` + "`json:\"{{.Project.JSONTag \"UpdatedAt\"}}\"`" + `
+}
+
+func ({{.Model.Name}}View) TableName() string {
+	return "{{.Project.TablePrefix}}{{.Model.SnakeName}}"
+}
+
+// 只读模型没有 DeletedAt 字段，需要显式过滤软删除的记录
+func not{{.Model.Name}}Deleted(db *gorm.DB) *gorm.DB {
+	return db.Where("deleted_at IS NULL")
+}
+
+// List{{.Model.Name}}s 从只读库查询 {{.Model.Name}}，scopes 为 models 中的命名查询条件
+func List{{.Model.Name}}s(db *gorm.DB, scopes ...func(*gorm.DB) *gorm.DB) ([]{{.Model.Name}}View, error) {
+	var views []{{.Model.Name}}View
+	err := db.Scopes(not{{.Model.Name}}Deleted).Scopes(scopes...).Find(&views).Error
+	return views, err
+}
+
+// Get{{.Model.Name}} 从只读库查询单个 {{.Model.Name}}，不存在时返回 gorm.ErrRecordNotFound
+func Get{{.Model.Name}}(db *gorm.DB, id {{.Model.IDGoType}}) (*{{.Model.Name}}View, error) {
+	var view {{.Model.Name}}View
+	if err := db.Scopes(not{{.Model.Name}}Deleted).First(&view, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+`
@@ -0,0 +1,159 @@
+package main
+
+// Consul 服务发现模板，ConsulEnabled 启用时生成 pkg/discovery，启动时注册服务、退出时注销，
+// 并在 .env 和环境变量未设置数据库配置时从 Consul KV 读取
+
+const defaultConsulAddress = "127.0.0.1:8500"
+
+const consulTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package discovery
+
+import (
+	"fmt"
+	"os"
+{{- if not .Project.AutoCertEnabled}}
+	"strconv"
+{{- end}}
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+
+	"{{.Project.ModuleName}}/pkg/config"
+)
+
+// Consul 中的服务名
+const serviceName = "{{.Project.KubeName}}"
+
+// Registration 是已注册到 Consul 的服务实例，退出前调用 Deregister 注销
+type Registration struct {
+	client *api.Client
+	id     string
+}
+
+// Register 将当前实例注册到 Consul，Consul 每 10 秒请求一次 /health 检查实例状态，
+// 连续失败 1 分钟后自动注销，避免进程异常退出后留下失效的实例
+func Register(cfg *config.Config) (*Registration, error) {
+	client, err := config.NewConsulClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("reading hostname: %w", err)
+	}
+{{- if .Project.AutoCertEnabled}}
+	port := 443
+{{- else}}
+	port, err := strconv.Atoi(cfg.AppPort)
+	if err != nil {
+		return nil, fmt.Errorf("invalid APP_PORT %q: %w", cfg.AppPort, err)
+	}
+{{- end}}
+
+	id := fmt.Sprintf("%s-%s-%d", serviceName, host, port)
+	registration := &api.AgentServiceRegistration{
+		ID:      id,
+		Name:    serviceName,
+		Address: host,
+		Port:    port,
+		Tags:    serviceTags(cfg.ServiceTags),
+		Check: &api.AgentServiceCheck{
+{{- if .Project.TLSEnabled}}
+			// 证书可能是自签名的，健康检查不校验证书
+			HTTP:          fmt.Sprintf("https://%s:%d/health", host, port),
+			TLSSkipVerify: true,
+{{- else}}
+			HTTP: fmt.Sprintf("http://%s:%d/health", host, port),
+{{- end}}
+			Interval:                       "10s",
+			Timeout:                        "2s",
+			DeregisterCriticalServiceAfter: "1m",
+		},
+	}
+	if err := client.Agent().ServiceRegister(registration); err != nil {
+		return nil, fmt.Errorf("registering with Consul: %w", err)
+	}
+	return &Registration{client: client, id: id}, nil
+}
+
+// Deregister 从 Consul 注销当前实例
+func (r *Registration) Deregister() error {
+	return r.client.Agent().ServiceDeregister(r.id)
+}
+
+// 逗号分隔的 SERVICE_TAGS，忽略空项
+func serviceTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+`
+
+// 生成到 pkg/config，discovery 包依赖 config，KV 读取放在 config 包中避免循环导入
+const consulConfigTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package config
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// Consul KV 中数据库配置的键前缀，如 {{.Project.KubeName}}/config/DB_HOST
+const consulKVPrefix = "{{.Project.KubeName}}/config/"
+
+// NewConsulClient 连接 CONSUL_ADDRESS 指定的 Consul agent，为空时使用 Consul 的默认地址
+func NewConsulClient(cfg *Config) (*api.Client, error) {
+	consulConfig := api.DefaultConfig()
+	if cfg.ConsulAddress != "" {
+		consulConfig.Address = cfg.ConsulAddress
+	}
+	client, err := api.NewClient(consulConfig)
+	if err != nil {
+		return nil, fmt.Errorf("creating Consul client: %w", err)
+	}
+	return client, nil
+}
+
+// loadDBConfigFromConsul 对 .env 和环境变量中为空的数据库配置，读取 Consul KV 中的同名键；
+// 都已设置时不连接 Consul
+func loadDBConfigFromConsul(cfg *Config) error {
+	fields := []struct {
+		key   string
+		value *string
+	}{
+		{"DB_HOST", &cfg.DBHost},
+		{"DB_PORT", &cfg.DBPort},
+		{"DB_USER", &cfg.DBUser},
+		{"DB_PASSWORD", &cfg.DBPass},
+		{"DB_NAME", &cfg.DBName},
+	}
+
+	var kv *api.KV
+	for _, field := range fields {
+		if *field.value != "" {
+			continue
+		}
+		if kv == nil {
+			client, err := NewConsulClient(cfg)
+			if err != nil {
+				return err
+			}
+			kv = client.KV()
+		}
+		pair, _, err := kv.Get(consulKVPrefix+field.key, nil)
+		if err != nil {
+			return fmt.Errorf("reading %s from Consul KV: %w", field.key, err)
+		}
+		if pair != nil {
+			*field.value = string(pair.Value)
+		}
+	}
+	return nil
+}
+`
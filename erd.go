@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"net/http"
+	"strings"
+	"text/template"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 模型间的多对一关系：From 通过外键字段 Field 引用 To
+type Relationship struct {
+	From  string
+	To    string
+	Field string
+}
+
+// 按 GORM 外键约定推断关系：字段名为 <模型名>ID 时视为引用该模型
+func (d TemplateData) Relationships() []Relationship {
+	names := make(map[string]bool, len(d.Models))
+	for _, m := range d.Models {
+		names[m.Name] = true
+	}
+
+	var relationships []Relationship
+	for _, m := range d.Models {
+		for _, f := range m.Fields {
+			target := strings.TrimSuffix(f.Name, "ID")
+			if target == f.Name || !names[target] {
+				continue
+			}
+			relationships = append(relationships, Relationship{From: m.Name, To: target, Field: f.Name})
+		}
+	}
+	return relationships
+}
+
+// Mermaid 的属性类型只允许字母、数字和下划线，如 time.Time 写作 time_Time
+func mermaidType(t string) string {
+	return strings.NewReplacer(".", "_", "*", "").Replace(t)
+}
+
+// 生成项目中的 docs/erd.md，GitHub 会自动渲染 Mermaid 图
+const mermaidERDTemplate = `# {{.Project.ProjectName}} 实体关系图
+
+` + "```mermaid" + `
+erDiagram
+{{- range .Models}}
+    {{.Name}} {
+{{- range .Fields}}
+        {{mermaidType .Type}} {{.Name}}
+{{- end}}
+    }
+{{- end}}
+{{- range .Relationships}}
+    {{.To}} ||--o{ {{.From}} : "{{.Field}}"
+{{- end}}
+` + "```" + `
+`
+
+// ERD 布局尺寸
+const (
+	erdBoxWidth    = 220
+	erdHeaderH     = 28
+	erdRowH        = 20
+	erdGap         = 60
+	erdMargin      = 20
+	erdTextPadding = 10
+)
+
+// 布局后的模型方框
+type erdBox struct {
+	Model      Model
+	X, Y, W, H float64
+}
+
+// 两个方框之间的连线，端点落在方框边缘
+type erdEdge struct {
+	Relationship
+	X1, Y1, X2, Y2 float64
+}
+
+// GET /erd：根据 models 参数（与 /generate 的格式相同）绘制实体关系图
+// ?format=svg（默认）返回 SVG，?format=dot 返回 Graphviz DOT
+func renderERD(c *gin.Context) {
+	data := TemplateData{Models: parseModels(c.Query("models"), defaultJSONTagStyle)}
+
+	if errs := modelErrors(data.Models); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errs})
+		return
+	}
+
+	var (
+		tmpl        *template.Template
+		contentType string
+		view        interface{}
+	)
+	switch c.DefaultQuery("format", "svg") {
+	case "svg":
+		tmpl, contentType, view = erdSVGTemplate, "image/svg+xml", layoutERD(data)
+	case "dot":
+		tmpl, contentType, view = erdDOTTemplate, "text/vnd.graphviz; charset=utf-8", data
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "不支持的格式: " + c.Query("format") + "，可选: svg, dot"})
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, view); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "无法生成ERD: " + err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// 网格布局：列数取模型数的平方根，每行高度取该行最高的方框
+func layoutERD(data TemplateData) gin.H {
+	columns := int(math.Ceil(math.Sqrt(float64(len(data.Models)))))
+	if columns == 0 {
+		columns = 1
+	}
+
+	boxes := make(map[string]*erdBox, len(data.Models))
+	var ordered []*erdBox
+	y, rowHeight, width := float64(erdMargin), 0.0, 0.0
+	for i, m := range data.Models {
+		if i > 0 && i%columns == 0 {
+			y += rowHeight + erdGap
+			rowHeight = 0
+		}
+		box := &erdBox{
+			Model: m,
+			X:     float64(erdMargin + (i%columns)*(erdBoxWidth+erdGap)),
+			Y:     y,
+			W:     erdBoxWidth,
+			H:     float64(erdHeaderH + erdRowH*len(m.Fields) + erdTextPadding),
+		}
+		rowHeight = math.Max(rowHeight, box.H)
+		width = math.Max(width, box.X+box.W+erdMargin)
+		boxes[m.Name] = box
+		ordered = append(ordered, box)
+	}
+
+	var edges []erdEdge
+	for _, r := range data.Relationships() {
+		from, to := boxes[r.From], boxes[r.To]
+		if from == to {
+			continue
+		}
+		x1, y1 := boxEdgePoint(from, to)
+		x2, y2 := boxEdgePoint(to, from)
+		edges = append(edges, erdEdge{Relationship: r, X1: x1, Y1: y1, X2: x2, Y2: y2})
+	}
+
+	return gin.H{
+		"Boxes":  ordered,
+		"Edges":  edges,
+		"Width":  math.Max(width, erdMargin*2),
+		"Height": y + rowHeight + erdMargin,
+	}
+}
+
+// 从方框 b 的中心指向 other 中心的连线与 b 边框的交点
+func boxEdgePoint(b, other *erdBox) (float64, float64) {
+	cx, cy := b.X+b.W/2, b.Y+b.H/2
+	dx, dy := other.X+other.W/2-cx, other.Y+other.H/2-cy
+	if dx == 0 && dy == 0 {
+		return cx, cy
+	}
+	scale := math.Inf(1)
+	if dx != 0 {
+		scale = math.Min(scale, (b.W/2)/math.Abs(dx))
+	}
+	if dy != 0 {
+		scale = math.Min(scale, (b.H/2)/math.Abs(dy))
+	}
+	return cx + dx*scale, cy + dy*scale
+}
+
+// DOT 记录标签中需要转义的字符
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "{", `\{`, "}", `\}`, "|", `\|`, "<", `\<`, ">", `\>`)
+
+var erdTemplateFuncs = template.FuncMap{
+	"xml": template.HTMLEscapeString,
+	"dot": dotEscaper.Replace,
+	"add": func(a float64, b int) float64 { return a + float64(b) },
+	"row": func(y float64, i int) float64 { return y + float64(erdHeaderH+erdRowH*(i+1)) - 5 },
+}
+
+var erdSVGTemplate = template.Must(template.New("erd.svg").Funcs(erdTemplateFuncs).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="{{.Height}}" viewBox="0 0 {{.Width}} {{.Height}}" font-family="monospace" font-size="13">
+  <defs>
+    <marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto-start-reverse">
+      <path d="M 0 0 L 10 5 L 0 10 z" fill="#555"/>
+    </marker>
+  </defs>
+{{- range .Edges}}
+  <line x1="{{.X1}}" y1="{{.Y1}}" x2="{{.X2}}" y2="{{.Y2}}" stroke="#555" stroke-width="1.5" marker-end="url(#arrow)">
+    <title>{{xml .From}}.{{xml .Field}} → {{xml .To}}</title>
+  </line>
+{{- end}}
+{{- range .Boxes}}
+  <g>
+    <rect x="{{.X}}" y="{{.Y}}" width="{{.W}}" height="{{.H}}" fill="#fff" stroke="#2c3e50" stroke-width="1.5" rx="4"/>
+    <rect x="{{.X}}" y="{{.Y}}" width="{{.W}}" height="28" fill="#3498db" rx="4"/>
+    <text x="{{add .X 10}}" y="{{add .Y 19}}" fill="#fff" font-weight="bold">{{xml .Model.Name}}</text>
+{{- $box := .}}
+{{- range $i, $f := .Model.Fields}}
+    <text x="{{add $box.X 10}}" y="{{row $box.Y $i}}">{{xml $f.Name}}: {{xml $f.Type}}</text>
+{{- end}}
+  </g>
+{{- end}}
+</svg>
+`))
+
+var erdDOTTemplate = template.Must(template.New("erd.dot").Funcs(erdTemplateFuncs).Parse(`digraph erd {
+  rankdir=LR;
+  node [shape=record, fontname="monospace"];
+{{- range .Models}}
+  "{{dot .Name}}" [label="{ {{- dot .Name}}|{{range .Fields}}{{dot .Name}}: {{dot .Type}}\l{{end}}}"];
+{{- end}}
+{{- range .Relationships}}
+  "{{dot .From}}" -> "{{dot .To}}" [label="{{dot .Field}}"];
+{{- end}}
+}
+`))
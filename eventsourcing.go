@@ -0,0 +1,265 @@
+package main
+
+import "strings"
+
+// 事件溯源模板，EventSourcing 启用时为每个模型生成 pkg/events 下的命令和事件类型，
+// 创建、更新接口应用命令后在同一事务中把事件写入 event_store 表
+
+// 命令包含的字段，不含 json:"-" 的敏感字段；事件使用 ViewFields，不记录密码哈希
+func (m Model) EventFields() []ModelField {
+	return m.HistoryFields()
+}
+
+// 命令或事件中是否有该类型的字段，主键也计算在内，用于决定导入
+func (m Model) EventUsesType(fieldType string) bool {
+	if m.IDGoType() == fieldType {
+		return true
+	}
+	for _, f := range m.EventFields() {
+		if strings.TrimPrefix(f.Type, "*") == fieldType {
+			return true
+		}
+	}
+	return false
+}
+
+var eventGenerator = templateGenerator{
+	name: "events",
+	files: func(data TemplateData) map[string]string {
+		if !data.Project.EventSourcing {
+			return nil
+		}
+		return map[string]string{
+			"pkg/events/store.go":        "event_store.go.tmpl",
+			"migrations/event_store.sql": "event_store.sql.tmpl",
+		}
+	},
+	modelFiles: func(data TemplateData, model Model) map[string]string {
+		if !data.Project.EventSourcing {
+			return nil
+		}
+		return map[string]string{
+			"pkg/events/" + model.SnakeName + "_commands.go": "event_commands.go.tmpl",
+			"pkg/events/" + model.SnakeName + "_events.go":   "event_events.go.tmpl",
+		}
+	},
+}
+
+const eventStoreTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package events
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Event 是写入事件存储的领域事件，以 JSON 保存在 payload 列
+type Event interface {
+	AggregateType() string
+	AggregateID() string
+	EventType() string
+}
+
+// Payload 是事件的 JSON 内容，以文本写入数据库，接口中原样输出为 JSON
+type Payload json.RawMessage
+
+func (p Payload) Value() (driver.Value, error) {
+	return string(p), nil
+}
+
+func (p *Payload) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		*p = append(Payload(nil), v...)
+	case string:
+		*p = Payload(v)
+	case nil:
+		*p = nil
+	default:
+		return fmt.Errorf("events: cannot scan %T into Payload", value)
+	}
+	return nil
+}
+
+func (p Payload) MarshalJSON() ([]byte, error) {
+	if len(p) == 0 {
+		return []byte("null"), nil
+	}
+	return p, nil
+}
+
+// StoredEvent 是 event_store 表中的一条事件记录，只追加不修改
+type StoredEvent struct {
+	ID            uint64    ` + "`gorm:\"primaryKey\" json:\"id\"`" + `
+	AggregateType string    ` + "`gorm:\"size:64;not null;index:idx_{{.Project.TablePrefix}}event_store_aggregate,priority:1\" json:\"aggregate_type\"`" + `
+	AggregateID   string    ` + "`gorm:\"size:64;not null;index:idx_{{.Project.TablePrefix}}event_store_aggregate,priority:2\" json:\"aggregate_id\"`" + `
+	EventType     string    ` + "`gorm:\"size:64;not null\" json:\"event_type\"`" + `
+	Payload       Payload   ` + "`gorm:\"type:{{if eq .Project.DBDriver \"postgres\"}}jsonb{{else}}json{{end}};not null\" json:\"payload\"`" + `
+	CreatedAt     time.Time ` + "`json:\"created_at\"`" + `
+}
+
+func (StoredEvent) TableName() string {
+	return "{{.Project.TablePrefix}}event_store"
+}
+
+// EventStore 读写 event_store 表
+type EventStore struct {
+	db *gorm.DB
+}
+
+// NewEventStore 传入事务时，事件与模型的变更一起提交或回滚
+func NewEventStore(db *gorm.DB) *EventStore {
+	return &EventStore{db: db}
+}
+
+// Append 追加一条事件
+func (s *EventStore) Append(event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encoding %s event: %w", event.EventType(), err)
+	}
+	return s.db.Create(&StoredEvent{
+		AggregateType: event.AggregateType(),
+		AggregateID:   event.AggregateID(),
+		EventType:     event.EventType(),
+		Payload:       payload,
+	}).Error
+}
+
+// Load 按写入顺序返回聚合的所有事件
+func (s *EventStore) Load(aggregateType, aggregateID string) ([]StoredEvent, error) {
+	var stored []StoredEvent
+	err := s.db.Where("aggregate_type = ? AND aggregate_id = ?", aggregateType, aggregateID).Order("id").Find(&stored).Error
+	return stored, err
+}
+`
+
+const eventCommandsTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package events
+
+import (
+{{- if .Model.EventUsesType "time.Time"}}
+	"time"
+{{end}}
+{{- if .Model.EventUsesType "uuid.UUID"}}
+	"github.com/google/uuid"
+{{end}}
+	"{{.Project.ModuleName}}/pkg/models"
+)
+
+// Create{{.Model.Name}}Command 创建 {{.Model.Name}}
+type Create{{.Model.Name}}Command struct {
+{{- if not .Model.NumericID}}
+	ID {{.Model.IDGoType}} ` + "`json:\"id\"`" + `
+{{- end}}
+{{- range .Model.EventFields}}
+	{{.Name}} {{.Type}} ` + "`json:\"{{.JsonTag}}\"`" + `
+{{- end}}
+}
+
+// Apply 把命令中的字段写入 m
+func (cmd Create{{.Model.Name}}Command) Apply(m *models.{{.Model.Name}}) {
+{{- if not .Model.NumericID}}
+	m.ID = cmd.ID
+{{- end}}
+{{- range .Model.EventFields}}
+	m.{{.Name}} = cmd.{{.Name}}
+{{- end}}
+}
+
+// Update{{.Model.Name}}Command 更新 {{.Model.Name}}，未传的字段保持原值
+type Update{{.Model.Name}}Command struct {
+{{- range .Model.EventFields}}
+	{{.Name}} *{{.Type}} ` + "`json:\"{{.JsonTag}}\"`" + `
+{{- end}}
+}
+
+// Apply 把命令中传入的字段写入 m
+func (cmd Update{{.Model.Name}}Command) Apply(m *models.{{.Model.Name}}) {
+{{- range .Model.EventFields}}
+	if cmd.{{.Name}} != nil {
+		m.{{.Name}} = *cmd.{{.Name}}
+	}
+{{- end}}
+}
+`
+
+const eventEventsTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package events
+
+import (
+	"fmt"
+{{- if .Model.EventUsesType "time.Time"}}
+	"time"
+{{- end}}
+{{- if .Model.EventUsesType "uuid.UUID"}}
+
+	"github.com/google/uuid"
+{{- end}}
+
+	"{{.Project.ModuleName}}/pkg/models"
+)
+
+// {{.Model.Name}}CreatedEvent 记录创建后的 {{.Model.Name}}
+type {{.Model.Name}}CreatedEvent struct {
+	ID {{.Model.IDGoType}} ` + "`json:\"id\"`" + `
+{{- range .Model.ViewFields}}
+	{{.Name}} {{.Type}} ` + "`json:\"{{.JsonTag}}\"`" + `
+{{- end}}
+}
+
+func New{{.Model.Name}}CreatedEvent(m *models.{{.Model.Name}}) {{.Model.Name}}CreatedEvent {
+	return {{.Model.Name}}CreatedEvent{
+		ID: m.ID,
+{{- range .Model.ViewFields}}
+		{{.Name}}: m.{{.Name}},
+{{- end}}
+	}
+}
+
+func ({{.Model.Name}}CreatedEvent) AggregateType() string { return "{{.Model.Name}}" }
+
+func (e {{.Model.Name}}CreatedEvent) AggregateID() string { return fmt.Sprint(e.ID) }
+
+func ({{.Model.Name}}CreatedEvent) EventType() string { return "{{.Model.Name}}Created" }
+
+// {{.Model.Name}}UpdatedEvent 记录更新后的 {{.Model.Name}}
+type {{.Model.Name}}UpdatedEvent struct {
+	ID {{.Model.IDGoType}} ` + "`json:\"id\"`" + `
+{{- range .Model.ViewFields}}
+	{{.Name}} {{.Type}} ` + "`json:\"{{.JsonTag}}\"`" + `
+{{- end}}
+}
+
+func New{{.Model.Name}}UpdatedEvent(m *models.{{.Model.Name}}) {{.Model.Name}}UpdatedEvent {
+	return {{.Model.Name}}UpdatedEvent{
+		ID: m.ID,
+{{- range .Model.ViewFields}}
+		{{.Name}}: m.{{.Name}},
+{{- end}}
+	}
+}
+
+func ({{.Model.Name}}UpdatedEvent) AggregateType() string { return "{{.Model.Name}}" }
+
+func (e {{.Model.Name}}UpdatedEvent) AggregateID() string { return fmt.Sprint(e.ID) }
+
+func ({{.Model.Name}}UpdatedEvent) EventType() string { return "{{.Model.Name}}Updated" }
+`
+
+const eventStoreMigrationTemplate = `-- 事件溯源的事件存储表，只追加不修改
+CREATE TABLE IF NOT EXISTS ` + "`{{.Project.TablePrefix}}event_store`" + ` (
+  ` + "`id`" + ` bigint unsigned NOT NULL AUTO_INCREMENT,
+  ` + "`aggregate_type`" + ` varchar(64) NOT NULL,
+  ` + "`aggregate_id`" + ` varchar(64) NOT NULL,
+  ` + "`event_type`" + ` varchar(64) NOT NULL,
+  ` + "`payload`" + ` json NOT NULL,
+  ` + "`created_at`" + ` datetime(3) NULL,
+  PRIMARY KEY (` + "`id`" + `),
+  KEY ` + "`idx_{{.Project.TablePrefix}}event_store_aggregate`" + ` (` + "`aggregate_type`" + `, ` + "`aggregate_id`" + `)
+) ENGINE=InnoDB DEFAULT CHARSET={{.Project.MySQLCharset}} COLLATE={{.Project.MySQLCollation}};
+`
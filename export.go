@@ -0,0 +1,911 @@
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 模型模板自动添加的字段，导出时跳过
+var builtinModelFields = map[string]bool{
+	"CreatedAt": true,
+	"UpdatedAt": true,
+	"DeletedAt": true,
+}
+
+// addRoleField 添加到认证用户模型的字段，导出时跳过，重新生成时会再次添加
+const roleFieldName = "Role"
+
+// addTOTPFields 添加的字段，导出时跳过，重新生成时会再次添加
+var totpModelFields = map[string]bool{
+	"TOTPSecret":  true,
+	"TOTPEnabled": true,
+}
+
+var (
+	makefileBinaryPattern = regexp.MustCompile(`go build -o bin/(\S+)`)
+	makefileCountPattern  = regexp.MustCompile(`(?m)^COUNT \?= (\d+)$`)
+	registerRoutesPattern = regexp.MustCompile(`^Register(\w+)Routes$`)
+	tableNamePattern      = regexp.MustCompile(`(?m)^\treturn "(\w+)"$`)
+)
+
+// 上传的项目ZIP及其解压后内容的大小上限，生成的项目远小于此
+const (
+	maxExportUploadBytes  = 10 << 20
+	maxExportContentBytes = 50 << 20
+)
+
+// POST /export-config：上传之前生成的项目ZIP，还原出 /generate 接受的表单参数
+// 服务端不保留生成的ZIP，因此需要通过 multipart 字段 project 上传；?format=yaml 返回 YAML
+func exportConfig(c *gin.Context) {
+	// 多留 1 MB 给 multipart 头和其他字段
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxExportUploadBytes+1<<20)
+	file, err := c.FormFile("project")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) || err == nil && file.Size > maxExportUploadBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("上传文件不能超过 %d MB", maxExportUploadBytes>>20)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "缺少上传文件 project"})
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无法读取上传文件: " + err.Error()})
+		return
+	}
+	defer f.Close()
+	content, err := io.ReadAll(io.LimitReader(f, maxExportUploadBytes))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无法读取上传文件: " + err.Error()})
+		return
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无法打开ZIP文件: " + err.Error()})
+		return
+	}
+	data, err := extractConfigFromZip(reader)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	fields := exportFormFields(data)
+	if c.Query("format") == "yaml" {
+		c.YAML(http.StatusOK, fields)
+		return
+	}
+	c.JSON(http.StatusOK, fields)
+}
+
+// 从生成的项目ZIP还原项目配置和模型定义，解压内容超过 maxExportContentBytes 时报错
+func extractConfigFromZip(reader *zip.Reader) (TemplateData, error) {
+	var data TemplateData
+
+	files := make(map[string][]byte)
+	remaining := int64(maxExportContentBytes)
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return data, fmt.Errorf("无法读取 %s: %w", f.Name, err)
+		}
+		content, err := io.ReadAll(io.LimitReader(rc, remaining+1))
+		rc.Close()
+		if err != nil {
+			return data, fmt.Errorf("无法读取 %s: %w", f.Name, err)
+		}
+		if remaining -= int64(len(content)); remaining < 0 {
+			return data, fmt.Errorf("ZIP解压后超过 %d MB，不是生成的项目", maxExportContentBytes>>20)
+		}
+		files[f.Name] = content
+	}
+
+	// 单仓库模式的项目位于 go.work 引用的子目录中
+	monorepo := false
+	if work, ok := files["go.work"]; ok {
+		dirs := parseGoWork(work)
+		if len(dirs) > 1 {
+			return data, fmt.Errorf("ZIP中包含多个项目 (%s)，请分别打包各子目录后导出", strings.Join(dirs, ", "))
+		}
+		if len(dirs) == 1 {
+			files = subdirFiles(files, dirs[0])
+			monorepo = true
+		}
+	}
+
+	goMod, ok := files["go.mod"]
+	if !ok {
+		return data, fmt.Errorf("ZIP中没有 go.mod，不是生成的项目")
+	}
+
+	project := &data.Project
+	project.ModuleName, project.GoVersion = parseGoMod(goMod)
+	project.MonorepoEnabled = monorepo
+	_, project.GoSum = files["go.sum"]
+	env := parseEnvFile(files[".env"])
+	project.Port = env["APP_PORT"]
+	project.Language = env["APP_LANGUAGE"]
+	project.DBDriver = "mysql"
+	if bytes.Contains(goMod, []byte("gorm.io/driver/postgres")) {
+		project.DBDriver = "postgres"
+	}
+	project.MySQLCharset = env["DB_CHARSET"]
+	project.MySQLCollation = env["DB_COLLATION"]
+	project.MaxRequestBodyMB, _ = strconv.Atoi(env["MAX_REQUEST_BODY_MB"])
+	project.RequestTimeoutSec, _ = strconv.Atoi(env["REQUEST_TIMEOUT_SEC"])
+	project.RateLimitRPS, _ = strconv.Atoi(env["RATE_LIMIT_RPS"])
+	project.RateLimitByAPIKey = bytes.Contains(files["pkg/middlewares/rate_limit.go"], []byte("X-API-Key"))
+	project.IPWhitelist = env["IP_WHITELIST"]
+	project.IPBlacklist = env["IP_BLACKLIST"]
+	_, project.TLSEnabled = files["scripts/generate-dev-cert.sh"]
+	_, project.HTTP2Push = files["pkg/middlewares/prefetch.go"]
+	_, project.SwaggerUI = files["docs/embed.go"]
+	if _, ok := files["terraform/main.tf"]; ok {
+		project.IaCDriver = "terraform"
+	} else if _, ok := files["infra/Pulumi.yaml"]; ok {
+		project.IaCDriver = "pulumi"
+	}
+	_, project.KubernetesEnabled = files["k8s/deployment.yaml"]
+	_, project.ArgoCD = files["argocd/application.yaml"]
+	_, project.IstioEnabled = files["k8s/virtualservice.yaml"]
+	_, project.GithubActionsEnabled = files[".github/workflows/ci.yml"]
+	project.SBOMEnabled = bytes.Contains(files["Makefile"], []byte("\nsbom:"))
+	project.VulnScanEnabled = bytes.Contains(files["Makefile"], []byte("\nvuln:"))
+	_, project.ChangelogEnabled = files[".chglog/config.yml"]
+	_, project.PreCommitEnabled = files[".pre-commit-config.yaml"]
+	_, project.RenovateEnabled = files[".renovaterc.json"]
+	if contributing, ok := files["CONTRIBUTING.md"]; ok {
+		project.GenerateCommunityFiles = true
+		project.BranchingStrategy = "trunk"
+		if bytes.Contains(contributing, []byte("GitFlow")) {
+			project.BranchingStrategy = "gitflow"
+		}
+	}
+	if m := conductContactPattern.FindSubmatch(files["CODE_OF_CONDUCT.md"]); m != nil {
+		project.AuthorEmail = string(m[1])
+	}
+	project.LetsEncryptDomain = env["LETSENCRYPT_DOMAIN"]
+	project.LetsEncryptEmail = env["LETSENCRYPT_EMAIL"]
+	if project.LetsEncryptDomain != "" {
+		project.TLSEnabled = true
+	}
+	project.TLSCertFile = env["TLS_CERT_FILE"]
+	project.TLSKeyFile = env["TLS_KEY_FILE"]
+	if m := makefileBinaryPattern.FindSubmatch(files["Makefile"]); m != nil {
+		project.ProjectName = string(m[1])
+	}
+	project.FileHeader = parseFileHeader(files["cmd/main.go"])
+
+	// 可选功能根据生成的文件判断
+	_, project.RBAC = files["pkg/middlewares/rbac.go"]
+	_, project.HelmEnabled = files["helm/"+project.ProjectName+"/Chart.yaml"]
+	project.License = detectLicense(files["LICENSE"])
+	// 未填写作者时 README 中是由项目名生成的默认版权所有者
+	if m := copyrightOwnerPattern.FindSubmatch(files["README.md"]); m != nil && string(m[1]) != project.CopyrightOwner() {
+		project.AuthorName = string(m[1])
+	}
+	_, project.TOTPEnabled = files["pkg/auth/totp.go"]
+	_, project.QuotaEnabled = files["pkg/quota/quota.go"]
+	_, project.DatadogEnabled = files["pkg/tracing/datadog.go"]
+	_, project.SentryEnabled = files["pkg/monitoring/sentry.go"]
+	_, project.MetricsEnabled = files["pkg/tracing/metrics.go"]
+	_, project.LokiEnabled = files["pkg/logger/loki.go"]
+	_, project.ConsulEnabled = files["pkg/discovery/consul.go"]
+	_, project.GRPCEnabled = files["pkg/grpc/health.go"]
+	project.ConsulAddress = env["CONSUL_ADDRESS"]
+	_, project.SeedEnabled = files["cmd/seed/main.go"]
+	_, project.SecurityHeaders = files["pkg/middlewares/security.go"]
+	_, project.AdminPanelEnabled = files["pkg/handlers/admin.go"]
+	_, project.StripeEnabled = files["pkg/payments/stripe.go"]
+	_, project.EmailEnabled = files["pkg/notifications/email.go"]
+	_, project.SlackEnabled = files["pkg/notifications/slack.go"]
+	_, project.FCMEnabled = files["pkg/notifications/fcm.go"]
+	_, project.TwilioEnabled = files["pkg/notifications/sms.go"]
+	_, project.ChatEnabled = files["pkg/chat/hub.go"]
+	_, project.NotificationCenter = files["pkg/notifications/publisher.go"]
+	_, project.ElasticsearchEnabled = files["pkg/search/elasticsearch.go"]
+	project.ElasticsearchURL = env["ELASTICSEARCH_URL"]
+	_, project.AlgoliaEnabled = files["pkg/search/algolia.go"]
+	project.SlackWebhookURL = env["SLACK_WEBHOOK_URL"]
+	project.GenerateTests = bytes.Contains(files["Makefile"], []byte("\nbench:"))
+	_, project.EventSourcing = files["pkg/events/store.go"]
+	project.CQRS = bytes.Contains(files["pkg/database/database.go"], []byte("func InitReadDB("))
+
+	project.AuthMode = "none"
+	if _, ok := files["pkg/middlewares/session.go"]; ok {
+		project.AuthMode = "session"
+	} else if _, ok := files["pkg/middlewares/token.go"]; ok {
+		project.AuthMode = "magiclink"
+	}
+
+	for _, backend := range []string{"otlp", "jaeger", "zipkin"} {
+		if _, ok := files["pkg/tracing/"+backend+".go"]; ok {
+			project.TracingBackend = backend
+		}
+	}
+
+	if m := makefileCountPattern.FindSubmatch(files["Makefile"]); m != nil {
+		project.SeedCount, _ = strconv.Atoi(string(m[1]))
+	}
+
+	// 模型定义，按文件路径排序以保证结果稳定
+	var modelPaths []string
+	for name := range files {
+		if path.Dir(name) == "pkg/models" && strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_password.go") &&
+			!strings.HasSuffix(name, "_history.go") && name != "pkg/models/history_context.go" && name != "pkg/models/validation.go" {
+			modelPaths = append(modelPaths, name)
+		}
+	}
+	sort.Strings(modelPaths)
+
+	for _, name := range modelPaths {
+		models, err := parseModelFile(name, files[name], project.TOTPEnabled)
+		if err != nil {
+			return data, err
+		}
+		data.Models = append(data.Models, models...)
+	}
+	// 去掉 addRoleField 添加到认证用户模型的字段
+	if user := findAuthUser(data.Models, project.AuthMode); project.RBAC && user != nil {
+		for i := range data.Models {
+			m := &data.Models[i]
+			if m.Name != user.Model.Name {
+				continue
+			}
+			fields := m.Fields[:0]
+			for _, f := range m.Fields {
+				if f.Name != roleFieldName {
+					fields = append(fields, f)
+				}
+			}
+			m.Fields = fields
+		}
+	}
+	if len(data.Models) > 0 {
+		project.TablePrefix = parseTablePrefix(files["pkg/models/"+data.Models[0].SnakeName+".go"], data.Models[0])
+	}
+	for i := range data.Models {
+		m := &data.Models[i]
+		for j := range m.Fields {
+			f := &m.Fields[j]
+			f.GormTag = restoreIndexTag(f.GormTag, project.TablePrefix+m.SnakeName, f.ColumnName())
+		}
+		_, m.VersionHistory = files["pkg/models/"+m.SnakeName+"_history.go"]
+		m.ParanoidMode = bytes.Contains(files["pkg/handlers/"+m.SnakeName+".go"], []byte("func Register"+m.Name+"AdminRoutes("))
+	}
+	if project.FCMEnabled {
+		data.Models = stripInjectedModel(data.Models, notificationModelName, notificationModelFields)
+	}
+	if project.TwilioEnabled {
+		data.Models = stripInjectedModel(data.Models, smsLogModelName, smsLogModelFields)
+	}
+	if project.NotificationCenter {
+		data.Models = stripInjectedModel(data.Models, notificationModelName, notificationCenterModelFields)
+	}
+	if project.ChatEnabled {
+		data.Models = stripInjectedModel(data.Models, chatMessageModelName, chatMessageModelFields)
+	}
+	project.JSONTagStyle = detectJSONTagStyle(data.Models)
+
+	if server, ok := files["pkg/api/server.go"]; ok {
+		order, permissions, err := parseServerRoutes(server)
+		if err != nil {
+			return data, err
+		}
+		for i := range data.Models {
+			data.Models[i].Permissions = permissions[data.Models[i].Name]
+		}
+
+		// 恢复模型定义的原始顺序，即路由注册顺序
+		position := make(map[string]int, len(order))
+		for i, name := range order {
+			position[name] = i
+		}
+		sort.SliceStable(data.Models, func(i, j int) bool {
+			return position[data.Models[i].Name] < position[data.Models[j].Name]
+		})
+	}
+
+	return data, nil
+}
+
+// 按 LICENSE 开头的标题识别许可证
+func detectLicense(content []byte) string {
+	if len(content) == 0 {
+		return "none"
+	}
+	head := string(content)
+	if len(head) > 200 {
+		head = head[:200]
+	}
+	switch {
+	case strings.Contains(head, "MIT License"):
+		return "MIT"
+	case strings.Contains(head, "Apache License"):
+		return "Apache-2.0"
+	case strings.Contains(head, "GNU AFFERO GENERAL PUBLIC LICENSE"):
+		return "AGPL-3.0"
+	case strings.Contains(head, "GNU GENERAL PUBLIC LICENSE"):
+		return "GPL-3.0"
+	case strings.Contains(head, "Mozilla Public License"):
+		return "MPL-2.0"
+	case strings.Contains(head, "All rights reserved."):
+		return "Proprietary"
+	}
+	return "none"
+}
+
+var copyrightOwnerPattern = regexp.MustCompile(`Copyright © (.+)`)
+
+// 行为准则 Enforcement 一节中的举报邮箱
+var conductContactPattern = regexp.MustCompile(`\(mailto:([^)]+)\)`)
+
+// 根据字段的 JSON 标签推断命名风格，取第一个与所有字段一致的风格
+func detectJSONTagStyle(models []Model) string {
+	for _, style := range supportedJSONTagStyles {
+		matches := true
+		for _, m := range models {
+			for _, f := range m.Fields {
+				if f.JsonTag != "-" && f.JsonTag != toJSONTag(f.Name, style) {
+					matches = false
+				}
+			}
+		}
+		if matches {
+			return style
+		}
+	}
+	return defaultJSONTagStyle
+}
+
+// 模型 TableName() 返回值去掉模型蛇形名后即为表名前缀
+func parseTablePrefix(content []byte, model Model) string {
+	m := tableNamePattern.FindSubmatch(content)
+	if m == nil {
+		return ""
+	}
+	return strings.TrimSuffix(string(m[1]), model.SnakeName)
+}
+
+// 从 go.mod 读取模块名和Go版本
+func parseGoMod(content []byte) (module, goVersion string) {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		switch fields[0] {
+		case "module":
+			module = fields[1]
+		case "go":
+			goVersion = fields[1]
+		}
+	}
+	return module, goVersion
+}
+
+// 读取 Go 文件 package 子句之前的注释行，即生成时添加的文件头
+func parseFileHeader(content []byte) string {
+	var header strings.Builder
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "//") {
+			break
+		}
+		header.WriteString(line + "\n")
+	}
+	return header.String()
+}
+
+// 解析 .env 的 KEY=VALUE 行，忽略注释
+func parseEnvFile(content []byte) map[string]string {
+	env := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		if i := strings.Index(value, " #"); i >= 0 {
+			value = value[:i]
+		}
+		env[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return env
+}
+
+// 用 go/ast 解析模型文件中的结构体定义
+func parseModelFile(name string, content []byte, skipTOTP bool) ([]Model, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, name, content, 0)
+	if err != nil {
+		return nil, fmt.Errorf("无法解析 %s: %w", name, err)
+	}
+
+	var models []Model
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+
+			model := newModel(typeSpec.Name.Name)
+			for _, field := range structType.Fields.List {
+				for _, ident := range field.Names {
+					if builtinModelFields[ident.Name] || (skipTOTP && totpModelFields[ident.Name]) {
+						continue
+					}
+					f := modelFieldFromAST(ident.Name, field)
+					f.Order = len(model.Fields)
+					model.Fields = append(model.Fields, f)
+				}
+			}
+			normalizeIDField(&model)
+			models = append(models, model)
+		}
+	}
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if ok && fn.Recv == nil {
+			addScopeFromAST(models, fn)
+			continue
+		}
+		if !ok || fn.Recv == nil || (!isGormHookName(fn.Name.Name) && fn.Name.Name != "Validate") {
+			continue
+		}
+		star, ok := fn.Recv.List[0].Type.(*ast.StarExpr)
+		if !ok {
+			continue
+		}
+		for i := range models {
+			ident, ok := star.X.(*ast.Ident)
+			if !ok || ident.Name != models[i].Name {
+				continue
+			}
+			if fn.Name.Name == "Validate" {
+				addLengthLimitsFromAST(&models[i], fn, fset, content)
+			} else {
+				addHookFromAST(&models[i], fn, fset, content)
+			}
+		}
+	}
+	return models, nil
+}
+
+// Validate 方法中的长度检查，如 utf8.RuneCountInString(m.Name) < 3
+var lengthCheckPattern = regexp.MustCompile(`utf8\.RuneCountInString\(m\.(\w+)\) ([<>]) (\d+)`)
+
+// 从 Validate 方法还原字段的 minlen、maxlen
+func addLengthLimitsFromAST(m *Model, fn *ast.FuncDecl, fset *token.FileSet, content []byte) {
+	body := content[fset.Position(fn.Body.Lbrace).Offset:fset.Position(fn.Body.Rbrace).Offset]
+	for _, match := range lengthCheckPattern.FindAllSubmatch(body, -1) {
+		limit, _ := strconv.Atoi(string(match[3]))
+		for i := range m.Fields {
+			if m.Fields[i].Name != string(match[1]) {
+				continue
+			}
+			if string(match[2]) == "<" {
+				m.Fields[i].MinLength = limit
+			} else {
+				m.Fields[i].MaxLength = limit
+			}
+		}
+	}
+}
+
+// 还原钩子方法体，去掉模板生成的 UUID 主键赋值、Validate 调用和末尾的 return nil
+func addHookFromAST(m *Model, fn *ast.FuncDecl, fset *token.FileSet, content []byte) {
+	start := fset.Position(fn.Body.Lbrace).Offset + 1
+	end := fset.Position(fn.Body.Rbrace).Offset
+	var lines []string
+	for _, line := range strings.Split(string(content[start:end]), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	if fn.Name.Name == "BeforeCreate" && m.IDType == "uuid" && len(lines) >= 3 &&
+		lines[0] == "if m.ID == uuid.Nil {" {
+		lines = lines[3:]
+	}
+	if fn.Name.Name == "BeforeCreate" || fn.Name.Name == "BeforeUpdate" {
+		lines = stripValidateCall(lines)
+	}
+	if fn.Name.Name == "BeforeCreate" {
+		lines = stripLeadingLines(lines, sendPushCallLines)
+	}
+	if fn.Name.Name == "AfterCreate" && len(lines) > 0 && lines[0] == "notifications.NotifyCreated("+strconv.Quote(m.Name)+", m.ID)" {
+		m.SlackNotify = true
+		lines = lines[1:]
+	}
+	if fn.Name.Name == "AfterCreate" && len(lines) > 0 && lines[0] == publishNotificationCallLine {
+		lines = lines[1:]
+	}
+	if (fn.Name.Name == "AfterCreate" || fn.Name.Name == "AfterUpdate") && len(lines) > 0 && lines[0] == indexSearchCallLine {
+		m.SearchEnabled = true
+		lines = lines[1:]
+	}
+	if fn.Name.Name == "AfterUpdate" && len(lines) > 0 && lines[0] == updateSearchIndexCallLine {
+		m.SearchEnabled = true
+		lines = lines[1:]
+	}
+	if fn.Name.Name == "AfterDelete" && len(lines) > 0 && lines[0] == removeSearchIndexCallLine {
+		lines = lines[1:]
+	}
+	if len(lines) > 0 && lines[len(lines)-1] == "return nil" {
+		lines = lines[:len(lines)-1]
+	}
+	for _, line := range lines {
+		m.addHookLine(fn.Name.Name, line)
+	}
+}
+
+// 还原 scope 函数: func <Model><Scope>(db *gorm.DB) *gorm.DB { return db.Where("...") }
+func addScopeFromAST(models []Model, fn *ast.FuncDecl) {
+	if len(fn.Body.List) != 1 {
+		return
+	}
+	ret, ok := fn.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return
+	}
+	call, ok := ret.Results[0].(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return
+	}
+	if sel, ok := call.Fun.(*ast.SelectorExpr); !ok || sel.Sel.Name != "Where" {
+		return
+	}
+	condition := stringLiteral(call.Args[0])
+	if condition == "" {
+		return
+	}
+	for i := range models {
+		name := strings.TrimPrefix(fn.Name.Name, models[i].Name)
+		if name != fn.Name.Name && name != "" {
+			models[i].Scopes = append(models[i].Scopes, ScopeDef{Name: name, Condition: condition})
+			return
+		}
+	}
+}
+
+func modelFieldFromAST(name string, field *ast.Field) ModelField {
+	f := ModelField{Name: name, Type: exprString(field.Type)}
+	if field.Tag == nil {
+		return f
+	}
+
+	tagValue, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return f
+	}
+	tag := reflect.StructTag(tagValue)
+	// 模型模板把默认值追加在 gorm 标签末尾，还原为 default: 选项
+	f.GormTag = tag.Get("gorm")
+	if before, value, ok := strings.Cut(f.GormTag, ";default:"); ok {
+		f.GormTag, f.Default = before, value
+	} else if value, ok := strings.CutPrefix(f.GormTag, "default:"); ok {
+		f.GormTag, f.Default = "", value
+	}
+
+	// 模型模板为必填字段添加 omitempty
+	jsonName, options, _ := strings.Cut(tag.Get("json"), ",")
+	f.JsonTag = jsonName
+	f.Required = strings.Contains(options, "omitempty")
+	return f
+}
+
+// 字段类型表达式，如 string、time.Time
+func exprString(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return exprString(e.X) + "." + e.Sel.Name
+	case *ast.StarExpr:
+		return "*" + exprString(e.X)
+	default:
+		return fmt.Sprintf("%T", expr)
+	}
+}
+
+// 解析 server.go 中的 Register<Model>Routes 调用，返回模型的注册顺序，
+// 以及从 RequireRoleByMethod 参数还原的 @perm 规则（按模型名）
+func parseServerRoutes(content []byte) ([]string, map[string]map[string][]string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "server.go", content, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("无法解析 server.go: %w", err)
+	}
+
+	var order []string
+	permissions := make(map[string]map[string][]string)
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		m := registerRoutesPattern.FindStringSubmatch(sel.Sel.Name)
+		if m == nil {
+			return true
+		}
+		order = append(order, m[1])
+
+		// Register<Model>Routes 的参数中查找 map[string][]string 字面量
+		ast.Inspect(call, func(n ast.Node) bool {
+			lit, ok := n.(*ast.CompositeLit)
+			if !ok {
+				return true
+			}
+			if _, isMap := lit.Type.(*ast.MapType); !isMap {
+				return true
+			}
+			rules := make(map[string][]string)
+			for _, elt := range lit.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+				method := stringLiteral(kv.Key)
+				roles, ok := kv.Value.(*ast.CompositeLit)
+				if method == "" || !ok {
+					continue
+				}
+				for _, role := range roles.Elts {
+					if r := stringLiteral(role); r != "" {
+						rules[method] = append(rules[method], r)
+					}
+				}
+			}
+			permissions[m[1]] = rules
+			return false
+		})
+		return false
+	})
+	return order, permissions, nil
+}
+
+func stringLiteral(expr ast.Expr) string {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return ""
+	}
+	s, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+// 转换为 /generate 接受的表单参数，复选框只在启用时输出
+func exportFormFields(data TemplateData) map[string]string {
+	p := data.Project
+	fields := map[string]string{
+		"project_name": p.ProjectName,
+		"module_name":  p.ModuleName,
+		"port":         p.Port,
+		"go_version":   p.GoVersion,
+		"auth_mode":    p.AuthMode,
+		"models":       formatModels(data.Models),
+	}
+	if p.Language != "" {
+		fields["language"] = p.Language
+	}
+	if p.FileHeader != "" {
+		fields["file_header"] = p.FileHeader
+	}
+	if p.TablePrefix != "" {
+		fields["table_prefix"] = p.TablePrefix
+	}
+	if p.DBDriver != "" && p.DBDriver != "mysql" {
+		fields["db_driver"] = p.DBDriver
+	}
+	if p.MySQLCharset != "" && p.MySQLCharset != defaultMySQLCharset {
+		fields["mysql_charset"] = p.MySQLCharset
+	}
+	if p.MySQLCollation != "" && p.MySQLCollation != defaultMySQLCollation {
+		fields["mysql_collation"] = p.MySQLCollation
+	}
+	if p.JSONTagStyle != "" && p.JSONTagStyle != defaultJSONTagStyle {
+		fields["json_tag_style"] = p.JSONTagStyle
+	}
+	if p.TracingBackend != "" {
+		fields["tracing_backend"] = p.TracingBackend
+	}
+	if p.ConsulEnabled && p.ConsulAddress != defaultConsulAddress {
+		fields["consul_address"] = p.ConsulAddress
+	}
+	if p.ElasticsearchEnabled && p.ElasticsearchURL != defaultElasticsearchURL {
+		fields["elasticsearch_url"] = p.ElasticsearchURL
+	}
+	if p.SlackEnabled && p.SlackWebhookURL != "" {
+		fields["slack_webhook_url"] = p.SlackWebhookURL
+	}
+	if p.AutoCertEnabled() {
+		fields["letsencrypt_domain"] = p.LetsEncryptDomain
+		if p.LetsEncryptEmail != "" {
+			fields["letsencrypt_email"] = p.LetsEncryptEmail
+		}
+	} else if p.TLSEnabled {
+		fields["tls_cert_file"] = p.TLSCertFile
+		fields["tls_key_file"] = p.TLSKeyFile
+	}
+	if p.IPWhitelist != "" {
+		fields["ip_whitelist"] = p.IPWhitelist
+	}
+	if p.IPBlacklist != "" {
+		fields["ip_blacklist"] = p.IPBlacklist
+	}
+	if p.SeedCount > 0 {
+		fields["seed_count"] = strconv.Itoa(p.SeedCount)
+	}
+	fields["max_request_body_mb"] = strconv.Itoa(p.MaxRequestBodyMB)
+	fields["request_timeout_sec"] = strconv.Itoa(p.RequestTimeoutSec)
+	if p.RateLimitRPS > 0 {
+		fields["rate_limit_rps"] = strconv.Itoa(p.RateLimitRPS)
+	}
+	// 安全响应头默认启用，只在关闭时输出
+	if !p.SecurityHeaders {
+		fields["security_headers"] = "off"
+	}
+	if p.IaCDriver != "" && p.IaCDriver != "none" {
+		fields["iac_driver"] = p.IaCDriver
+	}
+	if p.License != "" && p.License != "none" {
+		fields["license"] = p.License
+	}
+	if p.AuthorName != "" {
+		fields["author_name"] = p.AuthorName
+	}
+	if p.AuthorEmail != "" {
+		fields["author_email"] = p.AuthorEmail
+	}
+	if p.GenerateCommunityFiles {
+		fields["branching_strategy"] = p.BranchingStrategy
+	}
+
+	checkboxes := map[string]bool{
+		"rbac":    p.RBAC,
+		"totp":    p.TOTPEnabled,
+		"quota":   p.QuotaEnabled,
+		"datadog": p.DatadogEnabled,
+		"sentry":  p.SentryEnabled,
+		"metrics": p.MetricsEnabled,
+		"loki":    p.LokiEnabled,
+		"consul":  p.ConsulEnabled,
+		"seed":    p.SeedEnabled,
+
+		"generate_tests": p.GenerateTests,
+		"tls":            p.TLSEnabled,
+		"http2_push":     p.HTTP2Push,
+		"swagger_ui":     p.SwaggerUI,
+		"grpc":           p.GRPCEnabled,
+		"helm":           p.HelmEnabled,
+		"kubernetes":     p.KubernetesEnabled,
+		"argocd":         p.ArgoCD,
+		"istio":          p.IstioEnabled,
+		"github_actions": p.GithubActionsEnabled,
+		"sbom":           p.SBOMEnabled,
+		"vuln_scan":      p.VulnScanEnabled,
+		"changelog":      p.ChangelogEnabled,
+		"pre_commit":     p.PreCommitEnabled,
+		"renovate":       p.RenovateEnabled,
+		"monorepo":       p.MonorepoEnabled,
+		"go_sum":         p.GoSum,
+
+		"community_files":       p.GenerateCommunityFiles,
+		"rate_limit_by_api_key": p.RateLimitByAPIKey,
+		"admin_panel":           p.AdminPanelEnabled,
+		"stripe":                p.StripeEnabled,
+		"email_notifications":   p.EmailEnabled,
+		"slack":                 p.SlackEnabled,
+		"fcm":                   p.FCMEnabled,
+		"twilio":                p.TwilioEnabled,
+		"chat":                  p.ChatEnabled,
+		"notification_center":   p.NotificationCenter,
+		"elasticsearch":         p.ElasticsearchEnabled,
+		"algolia":               p.AlgoliaEnabled,
+		"event_sourcing":        p.EventSourcing,
+		"cqrs":                  p.CQRS,
+	}
+	for name, enabled := range checkboxes {
+		if enabled {
+			fields[name] = "on"
+		}
+	}
+	return fields
+}
+
+// 将模型格式化为表单中的文本格式，是 parseModels 的逆操作
+func formatModels(models []Model) string {
+	blocks := make([]string, 0, len(models))
+	for _, m := range models {
+		lines := []string{m.Name}
+		if m.IDType != "" && m.IDType != "uint" {
+			lines = append(lines, "@id "+m.IDType)
+		}
+		if m.VersionHistory {
+			lines = append(lines, "@history")
+		}
+		if m.ParanoidMode {
+			lines = append(lines, "@paranoid")
+		}
+		if m.SlackNotify {
+			lines = append(lines, "@slack")
+		}
+		if m.SearchEnabled {
+			lines = append(lines, "@search")
+		}
+		for _, f := range m.Fields {
+			parts := []string{f.Name, f.Type}
+			if f.Required {
+				parts = append(parts, "required")
+			}
+			if f.GormTag != "" && f.GormTag != defaultGormTag(toSnakeCase(f.Name), f.Type) {
+				parts = append(parts, `gorm:"`+f.GormTag+`"`)
+			}
+			if f.Default != "" {
+				parts = append(parts, "default:"+f.Default)
+			}
+			parts = append(parts, lengthLimitTags(f)...)
+			lines = append(lines, strings.Join(parts, " "))
+		}
+
+		methods := make([]string, 0, len(m.Permissions))
+		for method := range m.Permissions {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			lines = append(lines, "@perm "+method+" "+strings.Join(m.Permissions[method], ","))
+		}
+		for _, s := range m.Scopes {
+			lines = append(lines, "@scope "+s.Name+" "+s.Condition)
+		}
+		for _, h := range m.Hooks {
+			for _, line := range strings.Split(h.Body, "\n") {
+				lines = append(lines, "@hook "+h.Name+" "+line)
+			}
+		}
+
+		blocks = append(blocks, strings.Join(lines, "\n"))
+	}
+	return strings.Join(blocks, "\n\n")
+}
@@ -0,0 +1,215 @@
+package main
+
+import "fmt"
+
+// Firebase Cloud Messaging 推送模板，FCMEnabled 启用时生成 pkg/notifications/fcm.go、
+// POST /notifications/send 接口和 Notification 模型，Notification 的 BeforeCreate 钩子发送推送
+
+const notificationModelName = "Notification"
+
+// 生成器自动添加到模型的字段，按名称匹配已有字段
+type injectedField struct {
+	name, fieldType string
+	required        bool
+}
+
+// addNotificationModel 为 Notification 模型补充的字段
+var notificationModelFields = []injectedField{
+	{"DeviceToken", "string", true},
+	{"Title", "string", true},
+	{"Body", "string", false},
+	{"SentAt", "time.Time", false},
+}
+
+// 添加 Notification 模型并在 BeforeCreate 中发送推送
+func addNotificationModel(data *TemplateData) error {
+	m, err := addInjectedModel(data, notificationModelName, notificationModelFields)
+	if err != nil {
+		return err
+	}
+	m.PushNotification = true
+	return nil
+}
+
+// 添加名为 name 的模型，已存在时只补充缺少的字段；同名字段类型不同时返回错误
+func addInjectedModel(data *TemplateData, name string, fields []injectedField) (*Model, error) {
+	index := -1
+	for i, m := range data.Models {
+		if m.Name == name {
+			index = i
+		}
+	}
+	if index < 0 {
+		data.Models = append(data.Models, newModel(name))
+		index = len(data.Models) - 1
+	}
+
+	m := &data.Models[index]
+	for _, nf := range fields {
+		if f := m.field(nf.name); f != nil {
+			if f.Type != nf.fieldType {
+				return nil, fmt.Errorf("模型 %s 的字段 %s 需要是 %s 类型", m.Name, nf.name, nf.fieldType)
+			}
+			continue
+		}
+		m.Fields = append(m.Fields, ModelField{
+			Name:     nf.name,
+			Type:     nf.fieldType,
+			JsonTag:  toJSONTag(nf.name, data.Project.JSONTagStyle),
+			GormTag:  defaultGormTag(toSnakeCase(nf.name), nf.fieldType),
+			Required: nf.required,
+			Order:    m.nextFieldOrder(),
+		})
+	}
+	return m, nil
+}
+
+// 导出时去掉 addInjectedModel 添加的字段，没有其他字段的模型整个去掉，重新生成时会再次添加
+func stripInjectedModel(models []Model, name string, fields []injectedField) []Model {
+	var kept []Model
+	for _, m := range models {
+		if m.Name == name {
+			var rest []ModelField
+			for _, f := range m.Fields {
+				if !isInjectedField(f, fields) {
+					rest = append(rest, f)
+				}
+			}
+			if len(rest) == 0 {
+				continue
+			}
+			m.Fields = rest
+		}
+		kept = append(kept, m)
+	}
+	return kept
+}
+
+func isInjectedField(f ModelField, fields []injectedField) bool {
+	for _, nf := range fields {
+		if f.Name == nf.name && f.Type == nf.fieldType {
+			return true
+		}
+	}
+	return false
+}
+
+// 模板在 Notification 的 BeforeCreate 中生成的推送调用，导出配置还原钩子时去掉
+var sendPushCallLines = []string{"if err := m.sendPush(tx); err != nil {", "return err", "}"}
+
+// 按名称查找字段
+func (m *Model) field(name string) *ModelField {
+	for i := range m.Fields {
+		if m.Fields[i].Name == name {
+			return &m.Fields[i]
+		}
+	}
+	return nil
+}
+
+const fcmTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package notifications
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	firebase "firebase.google.com/go/v4"
+	"firebase.google.com/go/v4/messaging"
+	"google.golang.org/api/option"
+)
+
+var fcmClient *messaging.Client
+
+// ConfigureFCM 使用 FIREBASE_CREDENTIALS_FILE 中的服务账号初始化 FCM 客户端，启动时调用一次。
+// 文件路径为空时推送只输出到日志
+func ConfigureFCM(ctx context.Context, credentialsFile string) error {
+	if credentialsFile == "" {
+		return nil
+	}
+
+	app, err := firebase.NewApp(ctx, nil, option.WithCredentialsFile(credentialsFile))
+	if err != nil {
+		return fmt.Errorf("initializing Firebase app: %w", err)
+	}
+	client, err := app.Messaging(ctx)
+	if err != nil {
+		return fmt.Errorf("initializing FCM client: %w", err)
+	}
+	fcmClient = client
+	return nil
+}
+
+// SendPush 向设备 token 发送推送通知，返回 FCM 的消息 ID
+func SendPush(ctx context.Context, deviceToken, title, body string) (string, error) {
+	if fcmClient == nil {
+		log.Printf("push to=%s title=%q\n%s", deviceToken, title, body)
+		return "", nil
+	}
+
+	return fcmClient.Send(ctx, &messaging.Message{
+		Token: deviceToken,
+		Notification: &messaging.Notification{
+			Title: title,
+			Body:  body,
+		},
+	})
+}
+`
+
+const notificationSendHandlerTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"{{.Project.ModuleName}}/pkg/models"
+)
+
+type sendNotificationInput struct {
+	DeviceToken string ` + "`json:\"device_token\" binding:\"required\"`" + `
+	Title       string ` + "`json:\"title\" binding:\"required\"`" + `
+	Body        string ` + "`json:\"body\"`" + `
+{{- if .Project.NotificationCenter}}
+	// 通知中心按用户统计未读数
+	UserID uint   ` + "`json:\"user_id\" binding:\"required\"`" + `
+	Type   string ` + "`json:\"type\"`" + `
+{{- end}}
+}
+
+// RegisterNotificationSendRoutes 注册 POST /send，保存 Notification 记录，
+// 推送由 Notification 的 BeforeCreate 钩子发送，发送失败时不保存记录
+func RegisterNotificationSendRoutes(rg *gin.RouterGroup, db *gorm.DB) {
+	rg.POST("/send", sendNotification(db))
+}
+
+func sendNotification(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input sendNotificationInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		notification := models.Notification{
+			DeviceToken: input.DeviceToken,
+			Title:       input.Title,
+			Body:        input.Body,
+{{- if .Project.NotificationCenter}}
+			UserID:      input.UserID,
+			Type:        input.Type,
+{{- end}}
+		}
+		if result := db.WithContext(c.Request.Context()).Create(&notification); result.Error != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": result.Error.Error()})
+			return
+		}
+
+		c.JSON(http.StatusCreated, notification)
+	}
+}
+`
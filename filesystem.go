@@ -0,0 +1,81 @@
+package main
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// 生成文件的写入目标，路径均为相对项目根目录的斜杠路径
+type FileSystem interface {
+	MkdirAll(dir string) error
+	WriteFile(path string, content []byte) error
+}
+
+// 写入磁盘上的目录
+type OSFileSystem struct {
+	BaseDir string
+}
+
+func (fs OSFileSystem) MkdirAll(dir string) error {
+	return os.MkdirAll(filepath.Join(fs.BaseDir, dir), 0755)
+}
+
+func (fs OSFileSystem) WriteFile(path string, content []byte) error {
+	fullPath := filepath.Join(fs.BaseDir, path)
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(fullPath, content, 0644)
+}
+
+// 在内存中收集生成结果，不触碰磁盘
+type MemFileSystem struct {
+	Files map[string][]byte
+}
+
+func NewMemFileSystem() *MemFileSystem {
+	return &MemFileSystem{Files: make(map[string][]byte)}
+}
+
+func (fs *MemFileSystem) MkdirAll(dir string) error {
+	return nil
+}
+
+func (fs *MemFileSystem) WriteFile(path string, content []byte) error {
+	fs.Files[filepath.ToSlash(path)] = content
+	return nil
+}
+
+// 按路径排序的文件列表
+func (fs *MemFileSystem) Paths() []string {
+	paths := make([]string, 0, len(fs.Files))
+	for path := range fs.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// 将内存中的文件打包为ZIP并写入 w
+func (fs *MemFileSystem) WriteZip(w io.Writer) error {
+	zipWriter := zip.NewWriter(w)
+	modified := time.Now()
+	for _, path := range fs.Paths() {
+		zipEntry, err := zipWriter.CreateHeader(&zip.FileHeader{
+			Name:     path,
+			Method:   zip.Deflate,
+			Modified: modified,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := zipEntry.Write(fs.Files[path]); err != nil {
+			return err
+		}
+	}
+	return zipWriter.Close()
+}
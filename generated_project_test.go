@@ -0,0 +1,160 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 按表单字段生成项目到临时目录并下载依赖，返回项目目录。
+// -short、没有 Go 工具链或无法下载依赖（如离线）时跳过
+func generateTestProject(t *testing.T, fields map[string]string) string {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("生成项目并下载依赖较慢，-short 时跳过")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("没有 Go 工具链")
+	}
+
+	data, err := templateDataFromFields(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := generateProjectStructure(OSFileSystem{BaseDir: dir}, data); err != nil {
+		t.Fatalf("无法生成项目: %v", err)
+	}
+	if out, err := runGo(dir, "mod", "download"); err != nil {
+		t.Skipf("无法下载依赖: %v\n%s", err, out)
+	}
+	if out, err := runGo(dir, "mod", "tidy"); err != nil {
+		t.Fatalf("go mod tidy 失败: %v\n%s", err, out)
+	}
+	return dir
+}
+
+// 在生成的项目中运行 go 命令，不受外层工作区影响
+func runGo(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod")
+	return cmd.CombinedOutput()
+}
+
+func TestGeneratedCodeLints(t *testing.T) {
+	dir := generateTestProject(t, representativeFields)
+
+	if out, err := runGo(dir, "vet", "./..."); err != nil {
+		t.Errorf("go vet 失败: %v\n%s", err, out)
+	}
+
+	goroot, err := runGo(dir, "env", "GOROOT")
+	if err != nil {
+		t.Fatalf("go env GOROOT 失败: %v\n%s", err, goroot)
+	}
+	gofmt := filepath.Join(strings.TrimSpace(string(goroot)), "bin", "gofmt")
+	cmd := exec.Command(gofmt, "-l", ".")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("gofmt 失败: %v\n%s", err, out)
+	}
+	if files := strings.TrimSpace(string(out)); files != "" {
+		t.Errorf("以下文件未按 gofmt 格式化:\n%s", files)
+	}
+
+	t.Run("staticcheck", func(t *testing.T) {
+		staticcheck, err := exec.LookPath("staticcheck")
+		if err != nil {
+			t.Skip("PATH 中没有 staticcheck")
+		}
+		cmd := exec.Command(staticcheck, "./...")
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("staticcheck 失败: %v\n%s", err, out)
+		}
+	})
+}
+
+// 各配置合起来覆盖全部集成开关，每个开关至少在一个生成的项目中参与编译
+var buildTestConfigs = []struct {
+	name   string
+	fields map[string]string
+}{
+	{name: "representative", fields: representativeFields},
+	{name: "observability", fields: map[string]string{
+		"project_name":    "ops",
+		"module_name":     "example.com/ops",
+		"port":            "8080",
+		"db_driver":       "postgres",
+		"sentry":          "on",
+		"metrics":         "on",
+		"tracing_backend": "otlp",
+		"loki":            "on",
+		"consul":          "on",
+		"admin_panel":     "on",
+		"swagger_ui":      "on",
+		"generate_tests":  "on",
+		"seed":            "on",
+		"models":          "Device\n@id uuid\n@paranoid\n@history\nname string required\nowner uuid.UUID",
+	}},
+	{name: "integrations", fields: map[string]string{
+		"project_name":        "market",
+		"module_name":         "example.com/market",
+		"port":                "8080",
+		"auth_mode":           "magiclink",
+		"rbac":                "on",
+		"quota":               "on",
+		"grpc":                "on",
+		"tracing_backend":     "jaeger",
+		"elasticsearch":       "on",
+		"stripe":              "on",
+		"email_notifications": "on",
+		"slack":               "on",
+		"slack_webhook_url":   "https://hooks.slack.com/services/T000/B000/XXXX",
+		"fcm":                 "on",
+		"twilio":              "on",
+		"chat":                "on",
+		"notification_center": "on",
+		"event_sourcing":      "on",
+		"cqrs":                "on",
+		"models":              "User\nemail string required\n\nProduct\n@search\n@slack\n@paranoid\nname string required\nprice float64",
+	}},
+	{name: "algolia", fields: map[string]string{
+		"project_name":    "catalog",
+		"module_name":     "example.com/catalog",
+		"port":            "8080",
+		"auth_mode":       "session",
+		"totp":            "on",
+		"tracing_backend": "zipkin",
+		"algolia":         "on",
+		"tls":             "on",
+		"http2_push":      "on",
+		"models":          "User\nemail string required\npassword string required\n\nArticle\n@search\n@id int64\ntitle string required",
+	}},
+}
+
+func TestGeneratedProjectBuilds(t *testing.T) {
+	for _, tt := range buildTestConfigs {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := generateTestProject(t, tt.fields)
+
+			if out, err := runGo(dir, "build", "./..."); err != nil {
+				t.Errorf("go build 失败: %v\n%s", err, out)
+			}
+		})
+	}
+}
+
+// 生成的 RBAC 测试经过会话中间件登录，覆盖管理员可以访问受保护的路由
+func TestGeneratedMiddlewareTests(t *testing.T) {
+	dir := generateTestProject(t, representativeFields)
+
+	if out, err := runGo(dir, "test", "./pkg/middlewares/"); err != nil {
+		t.Errorf("go test 失败: %v\n%s", err, out)
+	}
+}
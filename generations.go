@@ -0,0 +1,222 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"hash"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	_ "modernc.org/sqlite"
+)
+
+// 生成历史的 SQLite 数据库路径，可通过 HISTORY_DB 环境变量修改
+const defaultHistoryDB = "history.db"
+
+// 生成历史，数据库打开失败时为 nil，此时不记录历史
+var history *historyStore
+
+// 每条记录保存完整的 TemplateData，可据此重新生成项目
+type historyStore struct {
+	db *sql.DB
+}
+
+// 历史列表中的一条记录，不含完整配置
+type historyEntry struct {
+	ID          int64           `json:"id"`
+	CreatedAt   time.Time       `json:"created_at"`
+	ProjectName string          `json:"project_name"`
+	ModuleName  string          `json:"module_name"`
+	Models      json.RawMessage `json:"models"`
+	ZipSHA256   string          `json:"zip_sha256"`
+}
+
+const historySchema = `CREATE TABLE IF NOT EXISTS generations (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	created_at   DATETIME NOT NULL,
+	project_name TEXT NOT NULL,
+	module_name  TEXT NOT NULL,
+	models       TEXT NOT NULL,
+	config       TEXT NOT NULL,
+	zip_sha256   TEXT NOT NULL
+)`
+
+func openHistoryStore(path string) (*historyStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite 同一时间只允许一个写入者
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(historySchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &historyStore{db: db}, nil
+}
+
+func historyDBPath() string {
+	if path := os.Getenv("HISTORY_DB"); path != "" {
+		return path
+	}
+	return defaultHistoryDB
+}
+
+func (s *historyStore) Record(data TemplateData, zipHash string) error {
+	models, err := json.Marshal(data.Models)
+	if err != nil {
+		return err
+	}
+	config, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(
+		`INSERT INTO generations (created_at, project_name, module_name, models, config, zip_sha256) VALUES (?, ?, ?, ?, ?, ?)`,
+		time.Now().UTC(), data.Project.ProjectName, data.Project.ModuleName, string(models), string(config), zipHash,
+	)
+	return err
+}
+
+// 按时间倒序分页查询，同时返回总数
+func (s *historyStore) List(offset, limit int) ([]historyEntry, int, error) {
+	var total int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM generations`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.db.Query(
+		`SELECT id, created_at, project_name, module_name, models, zip_sha256 FROM generations ORDER BY id DESC LIMIT ? OFFSET ?`,
+		limit, offset,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := []historyEntry{}
+	for rows.Next() {
+		var e historyEntry
+		var models string
+		if err := rows.Scan(&e.ID, &e.CreatedAt, &e.ProjectName, &e.ModuleName, &models, &e.ZipSHA256); err != nil {
+			return nil, 0, err
+		}
+		e.Models = json.RawMessage(models)
+		entries = append(entries, e)
+	}
+	return entries, total, rows.Err()
+}
+
+// 记录不存在时返回 sql.ErrNoRows
+func (s *historyStore) Config(id int64) (TemplateData, error) {
+	var data TemplateData
+	var config string
+	if err := s.db.QueryRow(`SELECT config FROM generations WHERE id = ?`, id).Scan(&config); err != nil {
+		return data, err
+	}
+	err := json.Unmarshal([]byte(config), &data)
+	return data, err
+}
+
+// 记录一次生成，zipHash 为写入响应时同步计算的 SHA-256；失败只记日志，不影响下载
+func recordGeneration(data TemplateData, zipHash hash.Hash) {
+	if history == nil {
+		return
+	}
+	if err := history.Record(data, hex.EncodeToString(zipHash.Sum(nil))); err != nil {
+		log.Printf("无法记录生成历史: %v", err)
+	}
+}
+
+// GET /history?page=1&page_size=20：分页列出生成记录，最新的在前
+func listHistory(c *gin.Context) {
+	if history == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "生成历史未启用"})
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "page 必须是正整数"})
+		return
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "page_size 必须在 1 到 100 之间"})
+		return
+	}
+
+	entries, total, err := history.List((page-1)*pageSize, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "无法查询生成历史: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"items":     entries,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
+func lookupHistoryConfig(c *gin.Context) (TemplateData, bool) {
+	if history == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "生成历史未启用"})
+		return TemplateData{}, false
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "记录不存在"})
+		return TemplateData{}, false
+	}
+
+	data, err := history.Config(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "记录不存在"})
+		return data, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "无法读取生成记录: " + err.Error()})
+		return data, false
+	}
+	return data, true
+}
+
+// GET /history/:id/config：生成时使用的 TemplateData
+func historyConfig(c *gin.Context) {
+	data, ok := lookupHistoryConfig(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// GET /history/:id/download：按记录的配置重新生成 ZIP，文件时间戳与原 ZIP 不同，哈希不会一致
+func downloadHistory(c *gin.Context) {
+	data, ok := lookupHistoryConfig(c)
+	if !ok {
+		return
+	}
+
+	files := NewMemFileSystem()
+	if err := generateProjectStructure(files, data); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "无法生成项目: " + err.Error()})
+		return
+	}
+
+	c.Header("Content-Description", "File Transfer")
+	c.Header("Content-Disposition", "attachment; filename="+data.Project.ProjectName+".zip")
+	c.Header("Content-Type", "application/zip")
+	c.Status(http.StatusOK)
+
+	if err := files.WriteZip(c.Writer); err != nil {
+		log.Printf("无法创建ZIP文件: %v", err)
+		c.Abort()
+	}
+}
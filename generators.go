@@ -0,0 +1,398 @@
+package main
+
+// 文件生成器，第三方可实现此接口生成额外文件（如 Elasticsearch 映射）
+type Generator interface {
+	Generate(data TemplateData, fs FileSystem) error
+}
+
+// 已注册的生成器，generateProjectStructure 按顺序调用
+var generators = []Generator{
+	projectGenerator,
+	configGenerator,
+	modelGenerator,
+	handlerGenerator,
+	eventGenerator,
+	cqrsGenerator,
+	apiSpecGenerator,
+	middlewareGenerator,
+	authGenerator,
+	notificationGenerator,
+	chatGenerator,
+	searchGenerator,
+	observabilityGenerator,
+	seedGenerator,
+	docsGenerator,
+	swaggerGenerator,
+	infraGenerator,
+	helmGenerator,
+	kubernetesGenerator,
+	githubGenerator,
+	changelogGenerator,
+	licenseGenerator,
+	communityGenerator,
+}
+
+// 追加自定义生成器
+func RegisterGenerator(g Generator) {
+	generators = append(generators, g)
+}
+
+// 生成器名称，用于进度上报；未实现 Name 的生成器统一为 "custom"
+func generatorName(g Generator) string {
+	if named, ok := g.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+	return "custom"
+}
+
+// 按名称筛选生成器，only 为空时返回全部
+func selectGenerators(only []string) []Generator {
+	if len(only) == 0 {
+		return generators
+	}
+
+	var selected []Generator
+	for _, g := range generators {
+		for _, name := range only {
+			if generatorName(g) == name {
+				selected = append(selected, g)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+// 是否存在该名称的生成器
+func isGeneratorCategory(name string) bool {
+	for _, g := range generators {
+		if generatorName(g) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// 基于模板的内置生成器，name 即其生成的文件类别
+type templateGenerator struct {
+	name string
+	// 项目级文件（路径 -> 模板名称），模板接收 TemplateData
+	files func(data TemplateData) map[string]string
+	// 模型级文件（路径 -> 模板名称），模板接收 Project 与 Model
+	modelFiles func(data TemplateData, model Model) map[string]string
+}
+
+func (g templateGenerator) Name() string {
+	return g.name
+}
+
+func (g templateGenerator) Generate(data TemplateData, fs FileSystem) error {
+	if g.files != nil {
+		for path, tmpl := range g.files(data) {
+			if err := generateBuiltinFile(fs, path, tmpl, data.Delims, data); err != nil {
+				return err
+			}
+		}
+	}
+
+	if g.modelFiles != nil {
+		for _, model := range data.Models {
+			modelData := struct {
+				Project ProjectConfig
+				Model   Model
+			}{data.Project, model}
+			for path, tmpl := range g.modelFiles(data, model) {
+				if err := generateBuiltinFile(fs, path, tmpl, data.Delims, modelData); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+var projectGenerator = templateGenerator{
+	name: "project",
+	files: func(data TemplateData) map[string]string {
+		files := map[string]string{
+			"cmd/main.go":              "main.go.tmpl",
+			"cmd/migrate/main.go":      "migrate_command.go.tmpl",
+			"pkg/database/database.go": "database.go.tmpl",
+			"pkg/api/server.go":        "server.go.tmpl",
+			"go.mod":                   "go.mod.tmpl",
+			"README.md":                "README.md.tmpl",
+			"Dockerfile":               "Dockerfile.tmpl",
+			".gitignore":               "gitignore.tmpl",
+			"Makefile":                 "Makefile.tmpl",
+		}
+		if data.Project.TLSEnabled && !data.Project.AutoCertEnabled() {
+			files["scripts/generate-dev-cert.sh"] = "generate-dev-cert.sh.tmpl"
+		}
+		if data.Project.GRPCEnabled {
+			files["pkg/grpc/health.go"] = "grpc_health.go.tmpl"
+		}
+		return files
+	},
+}
+
+var configGenerator = templateGenerator{
+	name: "config",
+	files: func(data TemplateData) map[string]string {
+		files := map[string]string{
+			"pkg/config/config.go": "config.go.tmpl",
+			".env":                 "env.tmpl",
+		}
+		if data.Project.ConsulEnabled {
+			files["pkg/config/consul.go"] = "consul_config.go.tmpl"
+			files["pkg/discovery/consul.go"] = "consul.go.tmpl"
+		}
+		return files
+	},
+}
+
+var modelGenerator = templateGenerator{
+	name: "models",
+	files: func(data TemplateData) map[string]string {
+		files := map[string]string{}
+		if data.Project.AuthMode == "session" {
+			files["pkg/models/"+data.AuthUser.Model.SnakeName+"_password.go"] = "password_hook.go.tmpl"
+		}
+		if data.UsesVersionHistory() {
+			files["pkg/models/history_context.go"] = "history_context.go.tmpl"
+		}
+		if data.UsesValidation() {
+			files["pkg/models/validation.go"] = "validation.go.tmpl"
+		}
+		return files
+	},
+	modelFiles: func(data TemplateData, model Model) map[string]string {
+		files := map[string]string{
+			"pkg/models/" + model.SnakeName + ".go":  "model.go.tmpl",
+			"migrations/" + model.SnakeName + ".sql": "migration.sql.tmpl",
+		}
+		if model.VersionHistory {
+			files["pkg/models/"+model.SnakeName+"_history.go"] = "history_model.go.tmpl"
+			files["migrations/"+model.SnakeName+"_histories.sql"] = "history_migration.sql.tmpl"
+		}
+		return files
+	},
+}
+
+var handlerGenerator = templateGenerator{
+	name: "handlers",
+	files: func(data TemplateData) map[string]string {
+		files := map[string]string{
+			"pkg/handlers/language.go": "handler_language.go.tmpl",
+			"pkg/i18n/messages.go":     "i18n_messages.go.tmpl",
+		}
+		switch data.Project.AuthMode {
+		case "session":
+			files["pkg/handlers/auth.go"] = "session_auth_handler.go.tmpl"
+		case "magiclink":
+			files["pkg/handlers/auth.go"] = "magiclink_auth_handler.go.tmpl"
+		}
+		if data.Project.AdminPanelEnabled {
+			files["pkg/handlers/admin.go"] = "admin.go.tmpl"
+		}
+		if data.Project.StripeEnabled {
+			files["pkg/handlers/payments.go"] = "payment_handler.go.tmpl"
+			files["pkg/payments/stripe.go"] = "stripe.go.tmpl"
+		}
+		return files
+	},
+	modelFiles: func(data TemplateData, model Model) map[string]string {
+		files := map[string]string{
+			"pkg/handlers/" + model.SnakeName + ".go": "handler.go.tmpl",
+		}
+		if data.Project.GenerateTests {
+			files["pkg/handlers/"+model.SnakeName+"_bench_test.go"] = "handler_bench_test.go.tmpl"
+		}
+		return files
+	},
+}
+
+var apiSpecGenerator = templateGenerator{
+	name: "api",
+	modelFiles: func(data TemplateData, model Model) map[string]string {
+		return map[string]string{
+			"api/" + model.SnakeName + ".yaml": "api_spec.yaml.tmpl",
+		}
+	},
+}
+
+var middlewareGenerator = templateGenerator{
+	name: "middlewares",
+	files: func(data TemplateData) map[string]string {
+		files := map[string]string{
+			"pkg/middlewares/logger.go": "logger.go.tmpl",
+		}
+		if data.Project.MaxRequestBodyMB > 0 {
+			files["pkg/middlewares/body_limit.go"] = "body_limit.go.tmpl"
+		}
+		if data.Project.RateLimitRPS > 0 {
+			files["pkg/middlewares/rate_limit.go"] = "rate_limit.go.tmpl"
+		}
+		if data.Project.RequestTimeoutSec > 0 {
+			files["pkg/middlewares/timeout.go"] = "timeout.go.tmpl"
+		}
+		if data.Project.QuotaEnabled {
+			files["pkg/quota/quota.go"] = "quota.go.tmpl"
+		}
+		if data.Project.SecurityHeaders {
+			files["pkg/middlewares/security.go"] = "security.go.tmpl"
+		}
+		if data.Project.IPFilterEnabled() {
+			files["pkg/middlewares/ipfilter.go"] = "ipfilter.go.tmpl"
+		}
+		if data.Project.HTTP2Push {
+			files["pkg/middlewares/prefetch.go"] = "prefetch.go.tmpl"
+		}
+		if data.Project.IstioEnabled {
+			files["pkg/middlewares/client_cert.go"] = "client_cert.go.tmpl"
+		}
+		switch data.Project.AuthMode {
+		case "session":
+			files["pkg/middlewares/session.go"] = "session.go.tmpl"
+		case "magiclink":
+			files["pkg/middlewares/token.go"] = "token.go.tmpl"
+		}
+		if data.Project.RBAC {
+			files["pkg/middlewares/rbac.go"] = "rbac.go.tmpl"
+			files["pkg/middlewares/rbac_test.go"] = "rbac_test.go.tmpl"
+		}
+		return files
+	},
+}
+
+var authGenerator = templateGenerator{
+	name: "auth",
+	files: func(data TemplateData) map[string]string {
+		files := map[string]string{}
+		if data.Project.TOTPEnabled {
+			files["pkg/auth/totp.go"] = "totp.go.tmpl"
+		}
+		if data.Project.AuthMode == "magiclink" {
+			files["pkg/auth/magiclink.go"] = "magiclink.go.tmpl"
+			files["pkg/email/sender.go"] = "email_sender.go.tmpl"
+		}
+		return files
+	},
+}
+
+var observabilityGenerator = templateGenerator{
+	name: "observability",
+	files: func(data TemplateData) map[string]string {
+		files := map[string]string{}
+		if data.Project.DatadogEnabled {
+			files["pkg/tracing/datadog.go"] = "datadog.go.tmpl"
+		}
+		if data.Project.SentryEnabled {
+			files["pkg/monitoring/sentry.go"] = "sentry.go.tmpl"
+		}
+		if data.Project.MetricsEnabled {
+			files["pkg/tracing/metrics.go"] = "metrics.go.tmpl"
+		}
+		if data.Project.LokiEnabled {
+			files["pkg/logger/loki.go"] = "loki.go.tmpl"
+		}
+		if data.Project.TracingBackend != "" {
+			files["pkg/tracing/tracer.go"] = "tracer.go.tmpl"
+			files["pkg/tracing/"+data.Project.TracingBackend+".go"] = map[string]string{
+				"otlp":   "otlp.go.tmpl",
+				"jaeger": "jaeger.go.tmpl",
+				"zipkin": "zipkin.go.tmpl",
+			}[data.Project.TracingBackend]
+		}
+		return files
+	},
+}
+
+var seedGenerator = templateGenerator{
+	name: "seeds",
+	files: func(data TemplateData) map[string]string {
+		files := map[string]string{}
+		if data.Project.SeedEnabled {
+			files["cmd/seed/main.go"] = "seed_command.go.tmpl"
+		}
+		return files
+	},
+	modelFiles: func(data TemplateData, model Model) map[string]string {
+		files := map[string]string{}
+		if data.Project.SeedEnabled {
+			files["pkg/seeds/"+model.SnakeName+"_seed.go"] = "seed.go.tmpl"
+		}
+		return files
+	},
+}
+
+var infraGenerator = templateGenerator{
+	name: "infrastructure",
+	files: func(data TemplateData) map[string]string {
+		files := map[string]string{}
+		switch data.Project.IaCDriver {
+		case "terraform":
+			files["terraform/main.tf"] = "terraform_main.tf.tmpl"
+			files["terraform/variables.tf"] = "terraform_variables.tf.tmpl"
+			files["terraform/outputs.tf"] = "terraform_outputs.tf.tmpl"
+		case "pulumi":
+			files["infra/Pulumi.yaml"] = "Pulumi.yaml.tmpl"
+			files["infra/go.mod"] = "pulumi_go.mod.tmpl"
+			files["infra/main.go"] = "pulumi_main.go.tmpl"
+		}
+		return files
+	},
+}
+
+var kubernetesGenerator = templateGenerator{
+	name: "kubernetes",
+	files: func(data TemplateData) map[string]string {
+		files := map[string]string{}
+		if data.Project.KubernetesEnabled {
+			// kubectl apply -f k8s/ 按文件名顺序创建资源，命名空间需要最先创建
+			files["k8s/00-namespace.yaml"] = "k8s_namespace.yaml.tmpl"
+			files["k8s/deployment.yaml"] = "k8s_deployment.yaml.tmpl"
+			files["k8s/service.yaml"] = "k8s_service.yaml.tmpl"
+		}
+		if data.Project.IstioEnabled {
+			files["k8s/virtualservice.yaml"] = "virtualservice.yaml.tmpl"
+			files["k8s/destinationrule.yaml"] = "destinationrule.yaml.tmpl"
+		}
+		if data.Project.KubernetesEnabled || data.Project.HelmEnabled {
+			files["skaffold.yaml"] = "skaffold.yaml.tmpl"
+		}
+		if data.Project.ArgoCD {
+			files["argocd/application.yaml"] = "argocd_application.yaml.tmpl"
+		}
+		return files
+	},
+}
+
+var githubGenerator = templateGenerator{
+	name: "github",
+	files: func(data TemplateData) map[string]string {
+		files := map[string]string{}
+		if data.Project.GithubActionsEnabled {
+			files[".github/workflows/ci.yml"] = "ci.yml.tmpl"
+			files[".github/workflows/release.yml"] = "release.yml.tmpl"
+			files[".github/ISSUE_TEMPLATE/bug_report.yml"] = "bug_report.yml.tmpl"
+			files[".github/ISSUE_TEMPLATE/feature_request.yml"] = "feature_request.yml.tmpl"
+			files[".github/PULL_REQUEST_TEMPLATE.md"] = "PULL_REQUEST_TEMPLATE.md.tmpl"
+		}
+		if data.Project.PreCommitEnabled {
+			files[".pre-commit-config.yaml"] = "pre-commit-config.yaml.tmpl"
+		}
+		if data.Project.RenovateEnabled {
+			files[".renovaterc.json"] = "renovaterc.json.tmpl"
+		}
+		return files
+	},
+}
+
+var docsGenerator = templateGenerator{
+	name: "docs",
+	files: func(data TemplateData) map[string]string {
+		return map[string]string{
+			"docs/erd.md": "erd.md.tmpl",
+		}
+	},
+}
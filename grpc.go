@@ -0,0 +1,103 @@
+package main
+
+// gRPC 健康检查模板，GRPCEnabled 启用时在 GRPC_PORT 上启动独立的 gRPC 服务器，
+// 提供 grpc.health.v1 协议的健康检查，供 Kubernetes 的 gRPC 探针使用
+
+// .env 中的 GRPC_PORT，与应用端口冲突时顺延一位
+func (p ProjectConfig) GRPCPort() string {
+	if p.Port == "9090" {
+		return "9091"
+	}
+	return "9090"
+}
+
+const grpcHealthTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package grpc
+
+import (
+	"context"
+	"net"
+	"time"
+
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// 健康检查请求中可指定的服务名，为空表示整个服务器
+const serviceName = "{{.Project.KubeName}}"
+
+// HealthServer 实现 grpc.health.v1 协议，数据库可连接时为 SERVING，否则为 NOT_SERVING
+type HealthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	db *gorm.DB
+}
+
+func NewHealthServer(db *gorm.DB) *HealthServer {
+	return &HealthServer{db: db}
+}
+
+// Check 返回当前状态，未知的服务名返回 NOT_FOUND
+func (s *HealthServer) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	if req.Service != "" && req.Service != serviceName {
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", req.Service)
+	}
+	return &grpc_health_v1.HealthCheckResponse{Status: s.status(ctx)}, nil
+}
+
+// Watch 立即发送当前状态，之后每 5 秒检查一次，状态变化时再发送；
+// 未知的服务名按协议发送 SERVICE_UNKNOWN 并保持连接
+func (s *HealthServer) Watch(req *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	ctx := stream.Context()
+	if req.Service != "" && req.Service != serviceName {
+		if err := stream.Send(&grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN}); err != nil {
+			return err
+		}
+		<-ctx.Done()
+		return status.FromContextError(ctx.Err()).Err()
+	}
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+	last := grpc_health_v1.HealthCheckResponse_UNKNOWN
+	for {
+		if current := s.status(ctx); current != last {
+			if err := stream.Send(&grpc_health_v1.HealthCheckResponse{Status: current}); err != nil {
+				return err
+			}
+			last = current
+		}
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *HealthServer) status(ctx context.Context) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return grpc_health_v1.HealthCheckResponse_NOT_SERVING
+	}
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return grpc_health_v1.HealthCheckResponse_NOT_SERVING
+	}
+	return grpc_health_v1.HealthCheckResponse_SERVING
+}
+
+// Serve 在 port 上启动 gRPC 服务器并注册健康检查服务，阻塞直到服务器退出
+func Serve(port string, db *gorm.DB) error {
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return err
+	}
+	server := gogrpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(server, NewHealthServer(db))
+	return server.Serve(listener)
+}
+`
@@ -0,0 +1,224 @@
+package main
+
+import "path"
+
+// Helm chart 模板，HelmEnabled 启用时在 helm/<project>/ 下生成。
+// templates/ 目录中的文件本身是 Helm 模板，与生成器的模板语法冲突，因此原样写入，
+// 项目相关的默认值都放在 values.yaml 中
+
+type helmChartGenerator struct {
+	templateGenerator
+}
+
+var helmGenerator = helmChartGenerator{templateGenerator{
+	name: "helm",
+	files: func(data TemplateData) map[string]string {
+		files := map[string]string{}
+		if data.Project.HelmEnabled {
+			dir := "helm/" + data.Project.ProjectName + "/"
+			files[dir+"Chart.yaml"] = "helm_chart.yaml.tmpl"
+			files[dir+"values.yaml"] = "helm_values.yaml.tmpl"
+		}
+		return files
+	},
+}}
+
+func (g helmChartGenerator) Generate(data TemplateData, fs FileSystem) error {
+	if !data.Project.HelmEnabled {
+		return nil
+	}
+	dir := path.Join("helm", data.Project.ProjectName, "templates")
+	for name, content := range helmChartTemplates {
+		if err := fs.WriteFile(path.Join(dir, name), []byte(content)); err != nil {
+			return err
+		}
+	}
+	return g.templateGenerator.Generate(data, fs)
+}
+
+const helmChartYAMLTemplate = `apiVersion: v2
+name: {{.Project.ProjectName}}
+description: {{.Project.ProjectName}} CRUD API
+type: application
+version: 0.1.0
+appVersion: "1.0.0"
+`
+
+const helmValuesTemplate = `replicaCount: 2
+
+image:
+  repository: {{.Project.KubeName}}
+  tag: latest
+  pullPolicy: IfNotPresent
+
+# 容器监听端口，与 APP_PORT 一致
+containerPort: {{.Project.Port}}
+
+service:
+  type: ClusterIP
+  port: 80
+
+ingress:
+  enabled: false
+  className: ""
+  host: {{.Project.ProjectName}}.local
+  # 非空时启用 TLS，证书需预先创建为该名称的 Secret
+  tlsSecretName: ""
+
+# 写入 ConfigMap 的配置，以环境变量形式覆盖镜像中 .env 的同名配置
+config:
+  APP_PORT: "{{.Project.Port}}"
+  APP_LANGUAGE: "{{.Project.Language}}"
+  DB_HOST: mysql
+  DB_PORT: "3306"
+  DB_USER: root
+  DB_NAME: {{.Project.ProjectName}}
+
+# 写入 Secret 的敏感配置，部署时应通过 --set 或单独的 values 文件覆盖
+secrets:
+  DB_PASSWORD: change_me
+{{- if eq .Project.AuthMode "magiclink"}}
+  JWT_SECRET: change_me
+{{- end}}
+{{- if eq .Project.AuthMode "session"}}
+  SESSION_SECRET: change_me
+{{- end}}
+
+resources:
+  requests:
+    cpu: 100m
+    memory: 128Mi
+  limits:
+    cpu: 500m
+    memory: 256Mi
+`
+
+var helmChartTemplates = map[string]string{
+	"_helpers.tpl": `{{- define "app.fullname" -}}
+{{- printf "%s-%s" .Release.Name .Chart.Name | trunc 63 | trimSuffix "-" -}}
+{{- end -}}
+
+{{- define "app.labels" -}}
+app.kubernetes.io/name: {{ .Chart.Name }}
+app.kubernetes.io/instance: {{ .Release.Name }}
+app.kubernetes.io/version: {{ .Chart.AppVersion | quote }}
+app.kubernetes.io/managed-by: {{ .Release.Service }}
+{{- end -}}
+
+{{- define "app.selectorLabels" -}}
+app.kubernetes.io/name: {{ .Chart.Name }}
+app.kubernetes.io/instance: {{ .Release.Name }}
+{{- end -}}
+`,
+	"deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ include "app.fullname" . }}
+  labels:
+    {{- include "app.labels" . | nindent 4 }}
+spec:
+  replicas: {{ .Values.replicaCount }}
+  selector:
+    matchLabels:
+      {{- include "app.selectorLabels" . | nindent 6 }}
+  template:
+    metadata:
+      labels:
+        {{- include "app.selectorLabels" . | nindent 8 }}
+      annotations:
+        # 配置变更后滚动重启
+        checksum/config: {{ include (print $.Template.BasePath "/configmap.yaml") . | sha256sum }}
+        checksum/secret: {{ include (print $.Template.BasePath "/secret.yaml") . | sha256sum }}
+    spec:
+      containers:
+        - name: {{ .Chart.Name }}
+          image: "{{ .Values.image.repository }}:{{ .Values.image.tag }}"
+          imagePullPolicy: {{ .Values.image.pullPolicy }}
+          ports:
+            - name: http
+              containerPort: {{ .Values.containerPort }}
+          envFrom:
+            - configMapRef:
+                name: {{ include "app.fullname" . }}
+            - secretRef:
+                name: {{ include "app.fullname" . }}
+          livenessProbe:
+            httpGet:
+              path: /health
+              port: http
+          readinessProbe:
+            httpGet:
+              path: /health
+              port: http
+          resources:
+            {{- toYaml .Values.resources | nindent 12 }}
+`,
+	"service.yaml": `apiVersion: v1
+kind: Service
+metadata:
+  name: {{ include "app.fullname" . }}
+  labels:
+    {{- include "app.labels" . | nindent 4 }}
+spec:
+  type: {{ .Values.service.type }}
+  ports:
+    - port: {{ .Values.service.port }}
+      targetPort: http
+      name: http
+  selector:
+    {{- include "app.selectorLabels" . | nindent 4 }}
+`,
+	"ingress.yaml": `{{- if .Values.ingress.enabled -}}
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: {{ include "app.fullname" . }}
+  labels:
+    {{- include "app.labels" . | nindent 4 }}
+spec:
+  {{- with .Values.ingress.className }}
+  ingressClassName: {{ . }}
+  {{- end }}
+  {{- with .Values.ingress.tlsSecretName }}
+  tls:
+    - hosts:
+        - {{ $.Values.ingress.host }}
+      secretName: {{ . }}
+  {{- end }}
+  rules:
+    - host: {{ .Values.ingress.host }}
+      http:
+        paths:
+          - path: /
+            pathType: Prefix
+            backend:
+              service:
+                name: {{ include "app.fullname" . }}
+                port:
+                  name: http
+{{- end }}
+`,
+	"configmap.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ include "app.fullname" . }}
+  labels:
+    {{- include "app.labels" . | nindent 4 }}
+data:
+  {{- range $key, $value := .Values.config }}
+  {{ $key }}: {{ $value | quote }}
+  {{- end }}
+`,
+	"secret.yaml": `apiVersion: v1
+kind: Secret
+metadata:
+  name: {{ include "app.fullname" . }}
+  labels:
+    {{- include "app.labels" . | nindent 4 }}
+type: Opaque
+stringData:
+  {{- range $key, $value := .Values.secrets }}
+  {{ $key }}: {{ $value | quote }}
+  {{- end }}
+`,
+}
@@ -0,0 +1,156 @@
+package main
+
+// 版本历史相关模板，模型使用 @history 选项启用
+
+// 历史表中模型字段的 MySQL 列类型
+func sqlColumnType(goType string) string {
+	switch goType {
+	case "int", "int32":
+		return "int"
+	case "int64":
+		return "bigint"
+	case "uint", "uint32":
+		return "int unsigned"
+	case "uint64":
+		return "bigint unsigned"
+	case "float32":
+		return "float"
+	case "float64":
+		return "double"
+	case "bool":
+		return "tinyint(1)"
+	case "time.Time", "*time.Time":
+		return "datetime(3)"
+	case "uuid.UUID":
+		return "char(36)"
+	default:
+		return "longtext"
+	}
+}
+
+// 主键在历史表 record_id 列中的 MySQL 类型，与 GORM 自动迁移的主键类型一致
+func (m Model) IDSQLType() string {
+	switch m.IDType {
+	case "int64":
+		return "bigint"
+	case "string":
+		return "varchar(191)"
+	case "uuid":
+		return "char(36)"
+	default:
+		return "bigint unsigned"
+	}
+}
+
+// 历史表快照的字段，不含 json:"-" 的敏感字段
+func (m Model) HistoryFields() []ModelField {
+	var fields []ModelField
+	for _, f := range m.Fields {
+		if f.JsonTag != "-" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// 是否有模型启用了版本历史
+func (data TemplateData) UsesVersionHistory() bool {
+	for _, m := range data.Models {
+		if m.VersionHistory {
+			return true
+		}
+	}
+	return false
+}
+
+const historyModelTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package models
+
+import (
+	"time"
+{{if .Model.UsesFieldType "uuid.UUID"}}
+	"github.com/google/uuid"
+{{- end}}
+	"gorm.io/gorm"
+)
+
+// {{.Model.Name}}History 是 {{.Model.Name}} 每次变更后的快照
+type {{.Model.Name}}History struct {
+	ID uint ` + "`gorm:\"primaryKey\" json:\"id\"`" + `
+	RecordID {{.Model.IDGoType}} ` + "`gorm:\"{{indexTag \"index\" (print .Project.TablePrefix .Model.SnakeName \"_histories\") \"record_id\"}}\" json:\"{{.Project.JSONTag \"RecordID\"}}\"`" + `
+	{{range .Model.HistoryFields}}{{.Name}} {{.Type}} ` + "`gorm:\"column:{{.ColumnName}}\" json:\"{{.JsonTag}}\"`" + `
+	{{end}}ChangedAt  time.Time ` + "`json:\"{{.Project.JSONTag \"ChangedAt\"}}\"`" + `
+	ChangedBy  uint      ` + "`json:\"{{.Project.JSONTag \"ChangedBy\"}}\"`" + `
+	ChangeType string    ` + "`gorm:\"size:16\" json:\"{{.Project.JSONTag \"ChangeType\"}}\"`" + ` // created、updated 或 deleted
+}
+
+func ({{.Model.Name}}History) TableName() string {
+	return "{{.Project.TablePrefix}}{{.Model.SnakeName}}_histories"
+}
+
+// AfterCreate 记录创建历史
+func (m *{{.Model.Name}}) AfterCreate(tx *gorm.DB) error {
+	return m.recordHistory(tx, "created")
+}
+
+// AfterUpdate 记录更新历史
+func (m *{{.Model.Name}}) AfterUpdate(tx *gorm.DB) error {
+	return m.recordHistory(tx, "updated")
+}
+
+// AfterDelete 记录删除历史，删除前需要先查询出记录，否则快照为空
+func (m *{{.Model.Name}}) AfterDelete(tx *gorm.DB) error {
+	return m.recordHistory(tx, "deleted")
+}
+
+func (m *{{.Model.Name}}) recordHistory(tx *gorm.DB, changeType string) error {
+	history := {{.Model.Name}}History{
+		RecordID:   m.ID,
+{{- range .Model.HistoryFields}}
+		{{.Name}}: m.{{.Name}},
+{{- end}}
+		ChangedAt:  time.Now(),
+		ChangedBy:  ChangedBy(tx.Statement.Context),
+		ChangeType: changeType,
+	}
+	// 新会话，避免带上当前语句的条件
+	return tx.Session(&gorm.Session{NewDB: true}).Create(&history).Error
+}
+`
+
+const historyContextTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package models
+
+import "context"
+
+type changedByKey struct{}
+
+// WithChangedBy 在 context 中记录操作人ID，通过 db.WithContext 传入后写入历史记录的 ChangedBy
+func WithChangedBy(ctx context.Context, userID uint) context.Context {
+	return context.WithValue(ctx, changedByKey{}, userID)
+}
+
+// ChangedBy 读取 WithChangedBy 设置的操作人ID，未设置时为 0
+func ChangedBy(ctx context.Context) uint {
+	if ctx == nil {
+		return 0
+	}
+	userID, _ := ctx.Value(changedByKey{}).(uint)
+	return userID
+}
+`
+
+const historyMigrationTemplate = `-- {{.Model.Name}} 的版本历史表
+CREATE TABLE IF NOT EXISTS ` + "`{{.Project.TablePrefix}}{{.Model.SnakeName}}_histories`" + ` (
+  ` + "`id`" + ` bigint unsigned NOT NULL AUTO_INCREMENT,
+  ` + "`record_id`" + ` {{.Model.IDSQLType}} NOT NULL,
+{{- range .Model.HistoryFields}}
+  ` + "`{{.ColumnName}}`" + ` {{sqlColumnType .Type}} NULL,
+{{- end}}
+  ` + "`changed_at`" + ` datetime(3) NOT NULL,
+  ` + "`changed_by`" + ` bigint unsigned NOT NULL DEFAULT 0,
+  ` + "`change_type`" + ` varchar(16) NOT NULL,
+  PRIMARY KEY (` + "`id`" + `),
+  KEY ` + "`{{sanitizeIndexName (print .Project.TablePrefix .Model.SnakeName \"_histories\") \"record_id\"}}`" + ` (` + "`record_id`" + `)
+) ENGINE=InnoDB DEFAULT CHARSET={{.Project.MySQLCharset}} COLLATE={{.Project.MySQLCollation}};
+`
@@ -0,0 +1,354 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 生成前回调，可修改模板数据
+type PreGenerateHook func(data *TemplateData) error
+
+// 生成后回调，可修改或增删生成结果，键为相对项目根目录的路径
+type PostGenerateHook func(files map[string][]byte) error
+
+// 生成钩子注册表，第三方模板插件通过它扩展生成流程而无需修改 main.go
+type HookRegistry struct {
+	pre  []PreGenerateHook
+	post []PostGenerateHook
+}
+
+func (r *HookRegistry) RegisterPreGenerate(fn func(data *TemplateData) error) {
+	r.pre = append(r.pre, fn)
+}
+
+func (r *HookRegistry) RegisterPostGenerate(fn func(files map[string][]byte) error) {
+	r.post = append(r.post, fn)
+}
+
+// 按注册顺序执行生成前回调，遇到错误立即返回
+func (r *HookRegistry) RunPreGenerate(data *TemplateData) error {
+	for _, fn := range r.pre {
+		if err := fn(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 按注册顺序执行生成后回调，遇到错误立即返回
+func (r *HookRegistry) RunPostGenerate(files map[string][]byte) error {
+	for _, fn := range r.post {
+		if err := fn(files); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateProjectStructure 使用的全局钩子
+var Hooks = newDefaultHookRegistry()
+
+// 默认注册去重字段、生成时间戳和格式化 Go 代码；字段排序会改变生成代码的字段顺序，需显式注册
+func newDefaultHookRegistry() *HookRegistry {
+	r := &HookRegistry{}
+	r.RegisterPreGenerate(DedupeModelFields)
+	r.RegisterPreGenerate(SetTimestamp)
+	r.RegisterPostGenerate(FormatGoFiles)
+	return r
+}
+
+// 内置钩子：按字段名字母顺序排序模型字段
+func SortModelFields(data *TemplateData) error {
+	for i := range data.Models {
+		fields := append([]ModelField(nil), data.Models[i].Fields...)
+		sort.SliceStable(fields, func(a, b int) bool {
+			return fields[a].Name < fields[b].Name
+		})
+		data.Models[i].Fields = fields
+	}
+	return nil
+}
+
+// 内置钩子：去除重复字段（忽略大小写），保留第一次出现的定义
+func DedupeModelFields(data *TemplateData) error {
+	for i := range data.Models {
+		seen := make(map[string]bool)
+		var fields []ModelField
+		for _, f := range data.Models[i].Fields {
+			key := strings.ToLower(f.Name)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			fields = append(fields, f)
+		}
+		data.Models[i].Fields = fields
+	}
+	return nil
+}
+
+// 内置钩子：按 gofmt 格式化生成的 .go 文件并将导入分组，模板中对齐结构体标签等细节不必手工处理。
+// 无法解析的文件（如自定义模板有语法错误）保持原样，由编译时报告具体位置
+func FormatGoFiles(files map[string][]byte) error {
+	for path, content := range files {
+		if !strings.HasSuffix(path, ".go") {
+			continue
+		}
+		formatted, err := format.Source(groupImports(content))
+		if err != nil {
+			log.Printf("无法格式化 %s，保持原样: %v", path, err)
+			continue
+		}
+		files[path] = formatted
+	}
+	return nil
+}
+
+// 与 goimports 相同，导入块分为标准库和其他两组，组内顺序由 gofmt 排序。
+// 模板按条件输出导入时组间空行会被 {{- 去掉；块内有注释时保持原样
+func groupImports(src []byte) []byte {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, parser.ImportsOnly|parser.ParseComments)
+	if err != nil {
+		return src
+	}
+
+	// 从后往前替换，前面导入块的偏移量不受影响
+	for i := len(f.Decls) - 1; i >= 0; i-- {
+		decl, ok := f.Decls[i].(*ast.GenDecl)
+		if !ok || decl.Tok != token.IMPORT || !decl.Lparen.IsValid() || hasCommentIn(f, decl.Lparen, decl.Rparen) {
+			continue
+		}
+
+		var std, other []string
+		for _, spec := range decl.Specs {
+			imp := spec.(*ast.ImportSpec)
+			text := string(src[fset.Position(imp.Pos()).Offset:fset.Position(imp.End()).Offset])
+			path, _ := strconv.Unquote(imp.Path.Value)
+			// 标准库路径的第一段不含点号
+			if strings.Contains(strings.Split(path, "/")[0], ".") {
+				other = append(other, text)
+			} else {
+				std = append(std, text)
+			}
+		}
+
+		var block bytes.Buffer
+		block.WriteString("\n")
+		for _, text := range std {
+			block.WriteString("\t" + text + "\n")
+		}
+		if len(std) > 0 && len(other) > 0 {
+			block.WriteString("\n")
+		}
+		for _, text := range other {
+			block.WriteString("\t" + text + "\n")
+		}
+
+		start := fset.Position(decl.Lparen).Offset + 1
+		end := fset.Position(decl.Rparen).Offset
+		grouped := make([]byte, 0, len(src)+1)
+		grouped = append(grouped, src[:start]...)
+		grouped = append(grouped, block.Bytes()...)
+		src = append(grouped, src[end:]...)
+	}
+	return src
+}
+
+// from 和 to 之间是否有注释
+func hasCommentIn(f *ast.File, from, to token.Pos) bool {
+	for _, group := range f.Comments {
+		if group.Pos() > from && group.End() < to {
+			return true
+		}
+	}
+	return false
+}
+
+// go mod tidy 的超时时间，首次下载依赖可能较慢
+const goModTidyTimeout = 2 * time.Minute
+
+// 最多缓存的 go mod tidy 结果数
+const goSumCacheSize = 64
+
+// go mod tidy 的结果按 goSumCacheKey 缓存；相同 key 的并发请求等待正在运行的 tidy，不同 key 互不阻塞
+var goSumCache = struct {
+	sync.Mutex
+	entries map[string]goSumResult
+	running map[string]*goSumCall
+}{entries: make(map[string]goSumResult), running: make(map[string]*goSumCall)}
+
+// 正在运行的 go mod tidy，done 关闭后 ok 表示是否成功
+type goSumCall struct {
+	done   chan struct{}
+	result goSumResult
+	ok     bool
+}
+
+type goSumResult struct {
+	goMod []byte
+	goSum []byte
+}
+
+// tidy 的结果只取决于各 go.mod 和导入的包，与模型字段等其他内容无关
+func goSumCacheKey(files map[string][]byte) string {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	h := sha256.New()
+	fset := token.NewFileSet()
+	for _, path := range paths {
+		switch {
+		case filepath.Base(path) == "go.mod":
+			fmt.Fprintf(h, "%s\n%s\n", path, files[path])
+		case strings.HasSuffix(path, ".go"):
+			f, err := parser.ParseFile(fset, path, files[path], parser.ImportsOnly)
+			if err != nil {
+				fmt.Fprintf(h, "%s\n%s\n", path, files[path])
+				continue
+			}
+			fmt.Fprintf(h, "%s\n", filepath.Dir(path))
+			for _, imp := range f.Imports {
+				fmt.Fprintf(h, "%s\n", imp.Path.Value)
+			}
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// 是否允许运行 go mod tidy：命令行模式始终允许；Web 服务器上每次运行都会下载依赖并占用进程，
+// 需设置 ALLOW_GO_SUM=1 显式开启，见 loadGoSumAllowed
+var goSumAllowed bool
+
+func loadGoSumAllowed() bool {
+	if os.Getenv("ALLOW_GO_SUM") == "1" {
+		return true
+	}
+	log.Println("未设置 ALLOW_GO_SUM=1，不提供 go.sum 生成")
+	return false
+}
+
+// 生成器所在环境有 Go 工具链时运行 go mod tidy，补充 go.sum 并更新 go.mod，ProjectConfig.GoSum 开启时调用。
+// 没有 Go、没有 go.mod（只重新生成部分文件）或 tidy 失败（如无法联网）时保持原样
+func GenerateGoSum(files map[string][]byte) error {
+	if _, ok := files["go.mod"]; !ok {
+		return nil
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		return nil
+	}
+
+	key := goSumCacheKey(files)
+	goSumCache.Lock()
+	if cached, ok := goSumCache.entries[key]; ok {
+		goSumCache.Unlock()
+		cached.apply(files)
+		return nil
+	}
+	if call, ok := goSumCache.running[key]; ok {
+		goSumCache.Unlock()
+		<-call.done
+		if call.ok {
+			call.result.apply(files)
+		}
+		return nil
+	}
+	call := &goSumCall{done: make(chan struct{})}
+	goSumCache.running[key] = call
+	goSumCache.Unlock()
+
+	defer func() {
+		goSumCache.Lock()
+		delete(goSumCache.running, key)
+		if call.ok {
+			if len(goSumCache.entries) >= goSumCacheSize {
+				for k := range goSumCache.entries {
+					delete(goSumCache.entries, k)
+					break
+				}
+			}
+			goSumCache.entries[key] = call.result
+		}
+		goSumCache.Unlock()
+		close(call.done)
+	}()
+
+	result, ok, err := runGoModTidy(goBin, files)
+	if err != nil || !ok {
+		return err
+	}
+	call.result, call.ok = result, true
+	result.apply(files)
+	return nil
+}
+
+func (r goSumResult) apply(files map[string][]byte) {
+	files["go.mod"] = r.goMod
+	files["go.sum"] = r.goSum
+}
+
+// 在临时目录中运行 go mod tidy；tidy 本身失败时只记录日志，ok 为 false
+func runGoModTidy(goBin string, files map[string][]byte) (result goSumResult, ok bool, err error) {
+	dir, err := os.MkdirTemp("", "gin-crud-tidy-*")
+	if err != nil {
+		return result, false, err
+	}
+	defer os.RemoveAll(dir)
+
+	// 写入全部文件：嵌套模块的 go.mod 和 go:embed 引用的文件都会影响包加载
+	project := OSFileSystem{BaseDir: dir}
+	for path, content := range files {
+		if err := project.WriteFile(path, content); err != nil {
+			return result, false, err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), goModTidyTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, goBin, "mod", "tidy")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		log.Printf("go mod tidy 失败，跳过 go.sum: %v\n%s", err, out)
+		return result, false, nil
+	}
+
+	tidied, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return result, false, err
+	}
+	goSum, err := os.ReadFile(filepath.Join(dir, "go.sum"))
+	if err != nil {
+		return result, false, err
+	}
+	if len(tidied) == 0 {
+		tidied = files["go.mod"]
+	}
+	return goSumResult{goMod: tidied, goSum: goSum}, true, nil
+}
+
+// 内置钩子：记录生成时间
+func SetTimestamp(data *TemplateData) error {
+	data.Timestamp = time.Now().Format("2006-01-02 15:04:05")
+	return nil
+}
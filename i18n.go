@@ -0,0 +1,91 @@
+package main
+
+const i18nMessagesTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package i18n
+
+import "fmt"
+
+// 缺少对应语言或消息时回退到英文
+const fallbackLanguage = "en"
+
+// 各语言的错误消息，值为 fmt 格式串
+var Messages = map[string]map[string]string{
+	"en": {
+		"invalid_id":            "Invalid ID",
+		"not_found":             "%s not found",
+		"unknown_scope":         "Unknown scope %s",
+		"validation_required":   "%s is required",
+		"validation_min_length": "%s must be at least %d characters",
+		"validation_max_length": "%s must be at most %d characters",
+	},
+	"zh": {
+		"invalid_id":            "无效的ID",
+		"not_found":             "%s 不存在",
+		"unknown_scope":         "未知的查询条件 %s",
+		"validation_required":   "%s 为必填项",
+		"validation_min_length": "%s 至少需要 %d 个字符",
+		"validation_max_length": "%s 最多 %d 个字符",
+	},
+	"es": {
+		"invalid_id":            "ID no válido",
+		"not_found":             "%s no encontrado",
+		"unknown_scope":         "Ámbito desconocido %s",
+		"validation_required":   "%s es obligatorio",
+		"validation_min_length": "%s debe tener al menos %d caracteres",
+		"validation_max_length": "%s debe tener como máximo %d caracteres",
+	},
+	"fr": {
+		"invalid_id":            "ID invalide",
+		"not_found":             "%s introuvable",
+		"unknown_scope":         "Portée inconnue %s",
+		"validation_required":   "%s est obligatoire",
+		"validation_min_length": "%s doit contenir au moins %d caractères",
+		"validation_max_length": "%s doit contenir au plus %d caractères",
+	},
+}
+
+// 翻译消息，未知的 key 原样返回
+func T(lang, key string, args ...interface{}) string {
+	format, ok := Messages[lang][key]
+	if !ok {
+		format, ok = Messages[fallbackLanguage][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+`
+
+const handlerLanguageTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package handlers
+{{- if .UsesValidation}}
+
+import (
+	"{{.Project.ModuleName}}/pkg/i18n"
+	"{{.Project.ModuleName}}/pkg/models"
+)
+{{- end}}
+
+// 错误消息使用的语言，启动时由 SetLanguage 根据配置设置
+var language = "{{.Project.Language}}"
+
+func SetLanguage(lang string) {
+	if lang != "" {
+		language = lang
+	}
+}
+{{- if .UsesValidation}}
+
+// 模型校验错误的本地化消息
+func validationMessage(err *models.ValidationError) string {
+	if err.Rule == "required" {
+		return i18n.T(language, "validation_required", err.Field)
+	}
+	return i18n.T(language, "validation_"+err.Rule, err.Field, err.Limit)
+}
+{{- end}}
+`
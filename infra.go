@@ -0,0 +1,624 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Terraform 模板，IaCDriver 为 "terraform" 时在 terraform/ 下生成 AWS 资源定义：
+// RDS 数据库、ECS Fargate 服务、应用负载均衡器及安全组
+
+// RDS 数据库名只能包含字母和数字，且以字母开头
+func (p ProjectConfig) RDSDatabaseName() string {
+	var b strings.Builder
+	for _, r := range p.ProjectName {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+const terraformMainTemplate = `terraform {
+  required_version = ">= 1.3"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+
+provider "aws" {
+  region = var.aws_region
+}
+
+locals {
+  # 负载均衡器等资源名只允许小写字母、数字和连字符
+  name = lower(replace("{{.Project.ProjectName}}", "_", "-"))
+}
+
+# 安全组：负载均衡器对外开放 80 端口，应用只接受负载均衡器的流量，数据库只接受应用的连接
+resource "aws_security_group" "alb" {
+  name   = "${local.name}-alb"
+  vpc_id = var.vpc_id
+
+  ingress {
+    from_port   = 80
+    to_port     = 80
+    protocol    = "tcp"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  egress {
+    from_port   = 0
+    to_port     = 0
+    protocol    = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+}
+
+resource "aws_security_group" "app" {
+  name   = "${local.name}-app"
+  vpc_id = var.vpc_id
+
+  ingress {
+    from_port       = var.app_port
+    to_port         = var.app_port
+    protocol        = "tcp"
+    security_groups = [aws_security_group.alb.id]
+  }
+
+  egress {
+    from_port   = 0
+    to_port     = 0
+    protocol    = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+}
+
+resource "aws_security_group" "db" {
+  name   = "${local.name}-db"
+  vpc_id = var.vpc_id
+
+  ingress {
+    from_port       = 3306
+    to_port         = 3306
+    protocol        = "tcp"
+    security_groups = [aws_security_group.app.id]
+  }
+}
+
+# 数据库
+resource "aws_db_subnet_group" "this" {
+  name       = "${local.name}-db"
+  subnet_ids = var.private_subnet_ids
+}
+
+resource "aws_db_instance" "this" {
+  identifier             = "${local.name}-db"
+  engine                 = "mysql"
+  engine_version         = "8.0"
+  instance_class         = var.db_instance_class
+  allocated_storage      = var.db_allocated_storage
+  db_name                = var.db_name
+  username               = var.db_username
+  password               = var.db_password
+  db_subnet_group_name   = aws_db_subnet_group.this.name
+  vpc_security_group_ids = [aws_security_group.db.id]
+  skip_final_snapshot    = var.db_skip_final_snapshot
+}
+
+# 负载均衡器
+resource "aws_lb" "this" {
+  name               = "${local.name}-alb"
+  load_balancer_type = "application"
+  security_groups    = [aws_security_group.alb.id]
+  subnets            = var.public_subnet_ids
+}
+
+resource "aws_lb_target_group" "this" {
+  name        = "${local.name}-tg"
+  port        = var.app_port
+  protocol    = "HTTP"
+  target_type = "ip"
+  vpc_id      = var.vpc_id
+
+  health_check {
+    path    = "/health"
+    matcher = "200"
+  }
+}
+
+resource "aws_lb_listener" "http" {
+  load_balancer_arn = aws_lb.this.arn
+  port              = 80
+  protocol          = "HTTP"
+
+  default_action {
+    type             = "forward"
+    target_group_arn = aws_lb_target_group.this.arn
+  }
+}
+
+# ECS
+resource "aws_ecs_cluster" "this" {
+  name = local.name
+}
+
+resource "aws_cloudwatch_log_group" "app" {
+  name              = "/ecs/${local.name}"
+  retention_in_days = 30
+}
+
+resource "aws_iam_role" "task_execution" {
+  name = "${local.name}-task-execution"
+
+  assume_role_policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [{
+      Effect    = "Allow"
+      Principal = { Service = "ecs-tasks.amazonaws.com" }
+      Action    = "sts:AssumeRole"
+    }]
+  })
+}
+
+resource "aws_iam_role_policy_attachment" "task_execution" {
+  role       = aws_iam_role.task_execution.name
+  policy_arn = "arn:aws:iam::aws:policy/service-role/AmazonECSTaskExecutionRolePolicy"
+}
+
+resource "aws_ecs_task_definition" "app" {
+  family                   = local.name
+  requires_compatibilities = ["FARGATE"]
+  network_mode             = "awsvpc"
+  cpu                      = var.task_cpu
+  memory                   = var.task_memory
+  execution_role_arn       = aws_iam_role.task_execution.arn
+
+  # 环境变量覆盖镜像中 .env 的同名配置
+  container_definitions = jsonencode([{
+    name         = local.name
+    image        = var.image
+    essential    = true
+    portMappings = [{ containerPort = var.app_port }]
+    environment = [
+      { name = "APP_PORT", value = tostring(var.app_port) },
+      { name = "DB_HOST", value = aws_db_instance.this.address },
+      { name = "DB_PORT", value = tostring(aws_db_instance.this.port) },
+      { name = "DB_USER", value = var.db_username },
+      { name = "DB_PASSWORD", value = var.db_password },
+      { name = "DB_NAME", value = var.db_name },
+    ]
+    logConfiguration = {
+      logDriver = "awslogs"
+      options = {
+        awslogs-group         = aws_cloudwatch_log_group.app.name
+        awslogs-region        = var.aws_region
+        awslogs-stream-prefix = local.name
+      }
+    }
+  }])
+}
+
+resource "aws_ecs_service" "app" {
+  name            = local.name
+  cluster         = aws_ecs_cluster.this.id
+  task_definition = aws_ecs_task_definition.app.arn
+  desired_count   = var.desired_count
+  launch_type     = "FARGATE"
+
+  network_configuration {
+    subnets          = var.private_subnet_ids
+    security_groups  = [aws_security_group.app.id]
+    assign_public_ip = false
+  }
+
+  load_balancer {
+    target_group_arn = aws_lb_target_group.this.arn
+    container_name   = local.name
+    container_port   = var.app_port
+  }
+
+  depends_on = [aws_lb_listener.http]
+}
+`
+
+const terraformVariablesTemplate = `variable "aws_region" {
+  description = "AWS 区域"
+  type        = string
+  default     = "us-east-1"
+}
+
+variable "vpc_id" {
+  description = "部署所在的 VPC"
+  type        = string
+}
+
+variable "public_subnet_ids" {
+  description = "负载均衡器使用的公有子网，至少两个可用区"
+  type        = list(string)
+}
+
+variable "private_subnet_ids" {
+  description = "ECS 任务和数据库使用的私有子网，需要能通过 NAT 拉取镜像"
+  type        = list(string)
+}
+
+variable "image" {
+  description = "由项目 Dockerfile 构建并推送的镜像，如 <account>.dkr.ecr.<region>.amazonaws.com/{{.Project.ProjectName}}:latest"
+  type        = string
+}
+
+variable "app_port" {
+  description = "容器监听端口，与 .env 中的 APP_PORT 一致"
+  type        = number
+  default     = {{.Project.Port}}
+}
+
+variable "desired_count" {
+  description = "ECS 任务数量"
+  type        = number
+  default     = 2
+}
+
+variable "task_cpu" {
+  description = "任务 CPU 单位"
+  type        = number
+  default     = 256
+}
+
+variable "task_memory" {
+  description = "任务内存（MiB）"
+  type        = number
+  default     = 512
+}
+
+variable "db_name" {
+  description = "数据库名"
+  type        = string
+  default     = "{{.Project.RDSDatabaseName}}"
+}
+
+variable "db_username" {
+  description = "数据库用户名"
+  type        = string
+  default     = "app"
+}
+
+variable "db_password" {
+  description = "数据库密码"
+  type        = string
+  sensitive   = true
+}
+
+variable "db_instance_class" {
+  description = "RDS 实例规格"
+  type        = string
+  default     = "db.t3.micro"
+}
+
+variable "db_allocated_storage" {
+  description = "RDS 存储空间（GB）"
+  type        = number
+  default     = 20
+}
+
+variable "db_skip_final_snapshot" {
+  description = "删除实例时是否跳过最终快照，生产环境应设为 false"
+  type        = bool
+  default     = true
+}
+`
+
+const terraformOutputsTemplate = `output "alb_dns_name" {
+  description = "负载均衡器地址，API 位于 http://<alb_dns_name>/api/v1"
+  value       = aws_lb.this.dns_name
+}
+
+output "db_endpoint" {
+  description = "RDS 连接地址"
+  value       = aws_db_instance.this.endpoint
+}
+
+output "ecs_cluster_name" {
+  value = aws_ecs_cluster.this.name
+}
+
+output "ecs_service_name" {
+  value = aws_ecs_service.app.name
+}
+`
+
+// Pulumi 程序模板，IaCDriver 为 "pulumi" 时在 infra/ 下生成，提供与 Terraform 相同的 AWS 资源。
+// infra/ 是独立的 Go 模块，避免 Pulumi SDK 进入应用的依赖
+
+const pulumiProjectTemplate = `name: {{.Project.KubeName}}-infra
+runtime: go
+description: {{.Project.ProjectName}} 的 AWS 基础设施 (RDS、ECS Fargate、ALB)
+`
+
+const pulumiGoModTemplate = `module {{.Project.ModuleName}}/infra
+
+go {{.Project.GoVersion}}
+
+require (
+	github.com/pulumi/pulumi-aws/sdk/v6 v6.32.0
+	github.com/pulumi/pulumi/sdk/v3 v3.113.0
+)
+`
+
+const pulumiMainTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package main
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/cloudwatch"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/ec2"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/ecs"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/iam"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/lb"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/rds"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+// 负载均衡器等资源名只允许小写字母、数字和连字符
+const name = "{{.Project.KubeName}}"
+
+func main() {
+	pulumi.Run(func(ctx *pulumi.Context) error {
+		cfg := config.New(ctx, "")
+		region := config.New(ctx, "aws").Require("region")
+		vpcID := cfg.Require("vpcId")
+		var publicSubnetIDs, privateSubnetIDs []string
+		cfg.RequireObject("publicSubnetIds", &publicSubnetIDs)
+		cfg.RequireObject("privateSubnetIds", &privateSubnetIDs)
+		// 由项目 Dockerfile 构建并推送的镜像
+		image := cfg.Require("image")
+		appPort := intOr(cfg.GetInt("appPort"), {{.Project.Port}})
+		desiredCount := intOr(cfg.GetInt("desiredCount"), 2)
+		dbName := stringOr(cfg.Get("dbName"), "{{.Project.RDSDatabaseName}}")
+		dbUsername := stringOr(cfg.Get("dbUsername"), "app")
+		dbPassword := cfg.RequireSecret("dbPassword")
+		dbInstanceClass := stringOr(cfg.Get("dbInstanceClass"), "db.t3.micro")
+
+		// 安全组：负载均衡器对外开放 80 端口，应用只接受负载均衡器的流量，数据库只接受应用的连接
+		allEgress := ec2.SecurityGroupEgressArray{
+			ec2.SecurityGroupEgressArgs{
+				Protocol:   pulumi.String("-1"),
+				FromPort:   pulumi.Int(0),
+				ToPort:     pulumi.Int(0),
+				CidrBlocks: pulumi.StringArray{pulumi.String("0.0.0.0/0")},
+			},
+		}
+		albSG, err := ec2.NewSecurityGroup(ctx, name+"-alb", &ec2.SecurityGroupArgs{
+			VpcId: pulumi.String(vpcID),
+			Ingress: ec2.SecurityGroupIngressArray{
+				ec2.SecurityGroupIngressArgs{
+					Protocol:   pulumi.String("tcp"),
+					FromPort:   pulumi.Int(80),
+					ToPort:     pulumi.Int(80),
+					CidrBlocks: pulumi.StringArray{pulumi.String("0.0.0.0/0")},
+				},
+			},
+			Egress: allEgress,
+		})
+		if err != nil {
+			return err
+		}
+		appSG, err := ec2.NewSecurityGroup(ctx, name+"-app", &ec2.SecurityGroupArgs{
+			VpcId: pulumi.String(vpcID),
+			Ingress: ec2.SecurityGroupIngressArray{
+				ec2.SecurityGroupIngressArgs{
+					Protocol:       pulumi.String("tcp"),
+					FromPort:       pulumi.Int(appPort),
+					ToPort:         pulumi.Int(appPort),
+					SecurityGroups: pulumi.StringArray{albSG.ID().ToStringOutput()},
+				},
+			},
+			Egress: allEgress,
+		})
+		if err != nil {
+			return err
+		}
+		dbSG, err := ec2.NewSecurityGroup(ctx, name+"-db", &ec2.SecurityGroupArgs{
+			VpcId: pulumi.String(vpcID),
+			Ingress: ec2.SecurityGroupIngressArray{
+				ec2.SecurityGroupIngressArgs{
+					Protocol:       pulumi.String("tcp"),
+					FromPort:       pulumi.Int(3306),
+					ToPort:         pulumi.Int(3306),
+					SecurityGroups: pulumi.StringArray{appSG.ID().ToStringOutput()},
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		// 数据库
+		subnetGroup, err := rds.NewSubnetGroup(ctx, name+"-db", &rds.SubnetGroupArgs{
+			SubnetIds: pulumi.ToStringArray(privateSubnetIDs),
+		})
+		if err != nil {
+			return err
+		}
+		db, err := rds.NewInstance(ctx, name+"-db", &rds.InstanceArgs{
+			Engine:              pulumi.String("mysql"),
+			EngineVersion:       pulumi.String("8.0"),
+			InstanceClass:       pulumi.String(dbInstanceClass),
+			AllocatedStorage:    pulumi.Int(20),
+			DbName:              pulumi.String(dbName),
+			Username:            pulumi.String(dbUsername),
+			Password:            dbPassword,
+			DbSubnetGroupName:   subnetGroup.Name,
+			VpcSecurityGroupIds: pulumi.StringArray{dbSG.ID().ToStringOutput()},
+			SkipFinalSnapshot:   pulumi.Bool(true),
+		})
+		if err != nil {
+			return err
+		}
+
+		// 负载均衡器
+		alb, err := lb.NewLoadBalancer(ctx, name+"-alb", &lb.LoadBalancerArgs{
+			LoadBalancerType: pulumi.String("application"),
+			SecurityGroups:   pulumi.StringArray{albSG.ID().ToStringOutput()},
+			Subnets:          pulumi.ToStringArray(publicSubnetIDs),
+		})
+		if err != nil {
+			return err
+		}
+		targetGroup, err := lb.NewTargetGroup(ctx, name+"-tg", &lb.TargetGroupArgs{
+			Port:       pulumi.Int(appPort),
+			Protocol:   pulumi.String("HTTP"),
+			TargetType: pulumi.String("ip"),
+			VpcId:      pulumi.String(vpcID),
+			HealthCheck: &lb.TargetGroupHealthCheckArgs{
+				Path:    pulumi.String("/health"),
+				Matcher: pulumi.String("200"),
+			},
+		})
+		if err != nil {
+			return err
+		}
+		listener, err := lb.NewListener(ctx, name+"-http", &lb.ListenerArgs{
+			LoadBalancerArn: alb.Arn,
+			Port:            pulumi.Int(80),
+			Protocol:        pulumi.String("HTTP"),
+			DefaultActions: lb.ListenerDefaultActionArray{
+				lb.ListenerDefaultActionArgs{
+					Type:           pulumi.String("forward"),
+					TargetGroupArn: targetGroup.Arn,
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		// ECS
+		cluster, err := ecs.NewCluster(ctx, name, nil)
+		if err != nil {
+			return err
+		}
+		logGroup, err := cloudwatch.NewLogGroup(ctx, name, &cloudwatch.LogGroupArgs{
+			Name:            pulumi.String("/ecs/" + name),
+			RetentionInDays: pulumi.Int(30),
+		})
+		if err != nil {
+			return err
+		}
+		executionRole, err := iam.NewRole(ctx, name+"-task-execution", &iam.RoleArgs{
+			AssumeRolePolicy: pulumi.String(` + "`" + `{
+  "Version": "2012-10-17",
+  "Statement": [{
+    "Effect": "Allow",
+    "Principal": {"Service": "ecs-tasks.amazonaws.com"},
+    "Action": "sts:AssumeRole"
+  }]
+}` + "`" + `),
+		})
+		if err != nil {
+			return err
+		}
+		_, err = iam.NewRolePolicyAttachment(ctx, name+"-task-execution", &iam.RolePolicyAttachmentArgs{
+			Role:      executionRole.Name,
+			PolicyArn: pulumi.String("arn:aws:iam::aws:policy/service-role/AmazonECSTaskExecutionRolePolicy"),
+		})
+		if err != nil {
+			return err
+		}
+
+		// 环境变量覆盖镜像中 .env 的同名配置
+		containerDefinitions := pulumi.All(db.Address, db.Port, dbPassword, logGroup.Name).ApplyT(
+			func(args []interface{}) (string, error) {
+				env := map[string]string{
+					"APP_PORT":    strconv.Itoa(appPort),
+					"DB_HOST":     args[0].(string),
+					"DB_PORT":     strconv.Itoa(args[1].(int)),
+					"DB_USER":     dbUsername,
+					"DB_PASSWORD": args[2].(string),
+					"DB_NAME":     dbName,
+				}
+				var environment []map[string]string
+				for k, v := range env {
+					environment = append(environment, map[string]string{"name": k, "value": v})
+				}
+				container := map[string]interface{}{
+					"name":         name,
+					"image":        image,
+					"essential":    true,
+					"portMappings": []interface{}{map[string]int{"containerPort": appPort}},
+					"environment":  environment,
+					"logConfiguration": map[string]interface{}{
+						"logDriver": "awslogs",
+						"options": map[string]string{
+							"awslogs-group":         args[3].(string),
+							"awslogs-region":        region,
+							"awslogs-stream-prefix": name,
+						},
+					},
+				}
+				definitions, err := json.Marshal([]interface{}{container})
+				return string(definitions), err
+			}).(pulumi.StringOutput)
+
+		taskDefinition, err := ecs.NewTaskDefinition(ctx, name, &ecs.TaskDefinitionArgs{
+			Family:                  pulumi.String(name),
+			RequiresCompatibilities: pulumi.StringArray{pulumi.String("FARGATE")},
+			NetworkMode:             pulumi.String("awsvpc"),
+			Cpu:                     pulumi.String("256"),
+			Memory:                  pulumi.String("512"),
+			ExecutionRoleArn:        executionRole.Arn,
+			ContainerDefinitions:    containerDefinitions,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = ecs.NewService(ctx, name, &ecs.ServiceArgs{
+			Cluster:        cluster.Arn,
+			TaskDefinition: taskDefinition.Arn,
+			DesiredCount:   pulumi.Int(desiredCount),
+			LaunchType:     pulumi.String("FARGATE"),
+			NetworkConfiguration: &ecs.ServiceNetworkConfigurationArgs{
+				Subnets:        pulumi.ToStringArray(privateSubnetIDs),
+				SecurityGroups: pulumi.StringArray{appSG.ID().ToStringOutput()},
+				AssignPublicIp: pulumi.Bool(false),
+			},
+			LoadBalancers: ecs.ServiceLoadBalancerArray{
+				ecs.ServiceLoadBalancerArgs{
+					TargetGroupArn: targetGroup.Arn,
+					ContainerName:  pulumi.String(name),
+					ContainerPort:  pulumi.Int(appPort),
+				},
+			},
+		}, pulumi.DependsOn([]pulumi.Resource{listener}))
+		if err != nil {
+			return err
+		}
+
+		ctx.Export("serviceUrl", pulumi.Sprintf("http://%s/api/v1", alb.DnsName))
+		ctx.Export("dbEndpoint", db.Endpoint)
+		return nil
+	})
+}
+
+func intOr(v, fallback int) int {
+	if v == 0 {
+		return fallback
+	}
+	return v
+}
+
+func stringOr(v, fallback string) string {
+	if v == "" {
+		return fallback
+	}
+	return v
+}
+`
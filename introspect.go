@@ -0,0 +1,276 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+)
+
+// 连接和查询数据库的超时时间
+const introspectTimeout = 10 * time.Second
+
+// 模型模板自带的列，反向生成时跳过
+var builtinModelColumns = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+	"deleted_at": true,
+}
+
+// 表结构中的一列
+type columnInfo struct {
+	Table   string
+	Name    string
+	GoType  string
+	NotNull bool
+	Primary bool
+}
+
+// POST /introspect：读取现有数据库的表结构并返回对应的模型定义
+func introspectDatabase(c *gin.Context) {
+	driver, dsn, err := databaseDSN(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无法连接数据库: " + err.Error()})
+		return
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), introspectTimeout)
+	defer cancel()
+
+	var columns []columnInfo
+	if driver == "mysql" {
+		columns, err = mysqlColumns(ctx, db, c.PostForm("db_name"))
+	} else {
+		columns, err = postgresColumns(ctx, db)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "无法读取表结构: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, modelsFromColumns(columns))
+}
+
+// 根据表单中的 db_driver、db_host、db_port、db_user、db_password、db_name
+// 返回 database/sql 的驱动名和连接串
+func databaseDSN(c *gin.Context) (string, string, error) {
+	driver := c.PostForm("db_driver")
+	host := c.DefaultPostForm("db_host", "127.0.0.1")
+	port := c.PostForm("db_port")
+	user := c.PostForm("db_user")
+	password := c.PostForm("db_password")
+	dbName := c.PostForm("db_name")
+
+	if dbName == "" {
+		return "", "", errors.New("数据库名不能为空")
+	}
+
+	switch driver {
+	case "mysql":
+		if port == "" {
+			port = "3306"
+		}
+		cfg := mysql.NewConfig()
+		cfg.User = user
+		cfg.Passwd = password
+		cfg.Net = "tcp"
+		cfg.Addr = host + ":" + port
+		cfg.DBName = dbName
+		return "mysql", cfg.FormatDSN(), nil
+	case "postgres":
+		if port == "" {
+			port = "5432"
+		}
+		dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+			quoteDSNValue(host), quoteDSNValue(port), quoteDSNValue(user), quoteDSNValue(password), quoteDSNValue(dbName))
+		return "postgres", dsn, nil
+	}
+	return "", "", fmt.Errorf("不支持的数据库类型: %s，可选: mysql, postgres", driver)
+}
+
+// 读取 MySQL 表结构
+func mysqlColumns(ctx context.Context, db *sql.DB, schema string) ([]columnInfo, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT TABLE_NAME, COLUMN_NAME, DATA_TYPE, COLUMN_TYPE, IS_NULLABLE, COLUMN_KEY
+		FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = ?
+		ORDER BY TABLE_NAME, ORDINAL_POSITION`, schema)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []columnInfo
+	for rows.Next() {
+		var table, name, dataType, columnType, nullable, key string
+		if err := rows.Scan(&table, &name, &dataType, &columnType, &nullable, &key); err != nil {
+			return nil, err
+		}
+		columns = append(columns, columnInfo{
+			Table:   table,
+			Name:    name,
+			GoType:  mysqlGoType(dataType, columnType),
+			NotNull: nullable == "NO",
+			Primary: key == "PRI",
+		})
+	}
+	return columns, rows.Err()
+}
+
+// 读取 PostgreSQL public schema 下的表结构
+func postgresColumns(ctx context.Context, db *sql.DB) ([]columnInfo, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT c.relname, a.attname, t.typname, a.attnotnull,
+			COALESCE(a.attnum = ANY(i.indkey), false)
+		FROM pg_attribute a
+		JOIN pg_class c ON a.attrelid = c.oid
+		JOIN pg_namespace n ON c.relnamespace = n.oid
+		JOIN pg_type t ON a.atttypid = t.oid
+		LEFT JOIN pg_index i ON i.indrelid = c.oid AND i.indisprimary
+		WHERE n.nspname = 'public' AND c.relkind = 'r' AND a.attnum > 0 AND NOT a.attisdropped
+		ORDER BY c.relname, a.attnum`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []columnInfo
+	for rows.Next() {
+		var table, name, typeName string
+		var notNull, primary bool
+		if err := rows.Scan(&table, &name, &typeName, &notNull, &primary); err != nil {
+			return nil, err
+		}
+		columns = append(columns, columnInfo{
+			Table:   table,
+			Name:    name,
+			GoType:  postgresGoType(typeName),
+			NotNull: notNull,
+			Primary: primary,
+		})
+	}
+	return columns, rows.Err()
+}
+
+// MySQL 类型映射为受支持的Go类型，无法识别的按字符串处理
+func mysqlGoType(dataType, columnType string) string {
+	unsigned := strings.Contains(columnType, "unsigned")
+	switch dataType {
+	case "tinyint":
+		if strings.HasPrefix(columnType, "tinyint(1)") {
+			return "bool"
+		}
+		fallthrough
+	case "smallint", "mediumint", "int", "integer":
+		if unsigned {
+			return "uint"
+		}
+		return "int"
+	case "bigint":
+		if unsigned {
+			return "uint64"
+		}
+		return "int64"
+	case "bit":
+		if columnType == "bit(1)" {
+			return "bool"
+		}
+		return "uint64"
+	case "float":
+		return "float32"
+	case "double", "decimal", "real":
+		return "float64"
+	case "date", "datetime", "timestamp":
+		return "time.Time"
+	default:
+		return "string"
+	}
+}
+
+// PostgreSQL 类型（pg_type.typname）映射为受支持的Go类型
+func postgresGoType(typeName string) string {
+	switch typeName {
+	case "int2", "int4":
+		return "int"
+	case "int8":
+		return "int64"
+	case "float4":
+		return "float32"
+	case "float8", "numeric":
+		return "float64"
+	case "bool":
+		return "bool"
+	case "date", "timestamp", "timestamptz":
+		return "time.Time"
+	default:
+		return "string"
+	}
+}
+
+// 按表分组生成模型；模型名取表名的驼峰形式，使 TableName 仍指向原表
+func modelsFromColumns(columns []columnInfo) []Model {
+	models := []Model{}
+	currentTable := ""
+	for _, col := range columns {
+		modelName, fieldName := toCamelCase(col.Table), toCamelCase(col.Name)
+		if modelName == "" || fieldName == "" || builtinModelColumns[col.Name] {
+			continue
+		}
+		if col.Table != currentTable {
+			models = append(models, newModel(modelName))
+			currentTable = col.Table
+		}
+
+		model := &models[len(models)-1]
+		model.Fields = append(model.Fields, ModelField{
+			Name:    fieldName,
+			Type:    col.GoType,
+			JsonTag: col.Name,
+			GormTag: "column:" + col.Name,
+			// 主键由数据库生成；bool 不能标记为必填，见 ValidateModel
+			Required: col.NotNull && !col.Primary && col.GoType != "bool",
+			Order:    len(model.Fields),
+		})
+	}
+	for i := range models {
+		normalizeIDField(&models[i])
+	}
+	return models
+}
+
+// 蛇形命名转为驼峰，id 按Go惯例转为 ID
+func toCamelCase(s string) string {
+	var result strings.Builder
+	for _, part := range strings.Split(s, "_") {
+		if part == "" {
+			continue
+		}
+		if strings.EqualFold(part, "id") {
+			result.WriteString("ID")
+			continue
+		}
+		result.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return result.String()
+}
+
+// libpq 连接串中的值需要用单引号包裹并转义
+func quoteDSNValue(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, `'`, `\'`)
+	return "'" + v + "'"
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 任务完成后保留结果的时长
+const generateJobTTL = 30 * time.Minute
+
+// 异步生成任务，按 job_id 存储
+var generateJobs sync.Map
+
+// 异步生成任务的状态
+type generateJob struct {
+	mu       sync.Mutex
+	step     string
+	progress float64
+	done     bool
+	err      error
+	data     TemplateData
+	files    *MemFileSystem
+	recorded bool // 首次下载时记入生成历史
+	// 每次状态变化时关闭并替换，用于唤醒 SSE 连接
+	updated chan struct{}
+}
+
+func newGenerateJob(data TemplateData) *generateJob {
+	return &generateJob{
+		step:    "queued",
+		data:    data,
+		files:   NewMemFileSystem(),
+		updated: make(chan struct{}),
+	}
+}
+
+func (j *generateJob) setProgress(step string, progress float64) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.step = step
+	j.progress = progress
+	j.notify()
+}
+
+func (j *generateJob) finish(err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.done = true
+	j.err = err
+	j.notify()
+}
+
+// 调用方须持有 j.mu
+func (j *generateJob) notify() {
+	close(j.updated)
+	j.updated = make(chan struct{})
+}
+
+// 当前状态对应的 SSE 事件，以及下一次状态变化的通知
+func (j *generateJob) event(id string) (string, gin.H, <-chan struct{}) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	switch {
+	case j.done && j.err != nil:
+		return "error", gin.H{"step": "error", "error": j.err.Error()}, nil
+	case j.done:
+		return "done", gin.H{"step": "done", "download_url": "/generate/download/" + id}, nil
+	default:
+		return "progress", gin.H{"step": j.step, "progress": j.progress}, j.updated
+	}
+}
+
+func (j *generateJob) run() {
+	err := generateProjectStructureWithProgress(j.files, j.data, j.setProgress)
+	j.finish(err)
+}
+
+// 生成随机 UUID (v4) 作为任务 ID
+func newJobID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+func lookupGenerateJob(c *gin.Context) (*generateJob, bool) {
+	value, ok := generateJobs.Load(c.Param("jobId"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "任务不存在或已过期"})
+		return nil, false
+	}
+	return value.(*generateJob), true
+}
+
+// POST /generate/start：异步开始生成，返回 job_id
+func startGenerateJob(c *gin.Context) {
+	data, ok := bindTemplateData(c)
+	if !ok {
+		return
+	}
+
+	id, err := newJobID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "无法创建任务: " + err.Error()})
+		return
+	}
+
+	job := newGenerateJob(data)
+	generateJobs.Store(id, job)
+	time.AfterFunc(generateJobTTL, func() { generateJobs.Delete(id) })
+	go job.run()
+
+	c.JSON(http.StatusAccepted, gin.H{"job_id": id})
+}
+
+// GET /generate/status/:jobId：以 SSE 推送生成进度
+func streamGenerateStatus(c *gin.Context) {
+	job, ok := lookupGenerateJob(c)
+	if !ok {
+		return
+	}
+
+	id := c.Param("jobId")
+	c.Stream(func(w io.Writer) bool {
+		name, payload, updated := job.event(id)
+		c.SSEvent(name, payload)
+		if updated == nil {
+			return false
+		}
+		select {
+		case <-updated:
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// GET /generate/download/:jobId：下载已完成任务的 ZIP
+func downloadGenerateJob(c *gin.Context) {
+	job, ok := lookupGenerateJob(c)
+	if !ok {
+		return
+	}
+
+	job.mu.Lock()
+	done, jobErr := job.done, job.err
+	record := done && jobErr == nil && !job.recorded
+	if record {
+		job.recorded = true
+	}
+	job.mu.Unlock()
+	if !done {
+		c.JSON(http.StatusConflict, gin.H{"error": "任务尚未完成"})
+		return
+	}
+	if jobErr != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "无法生成项目: " + jobErr.Error()})
+		return
+	}
+
+	if warning := portWarning(job.data.Project.Port); warning != "" {
+		c.Header("X-Generator-Warning", warning)
+	}
+	c.Header("Content-Description", "File Transfer")
+	c.Header("Content-Disposition", "attachment; filename="+job.data.Project.ProjectName+".zip")
+	c.Header("Content-Type", "application/zip")
+	c.Status(http.StatusOK)
+
+	zipHash := sha256.New()
+	if err := job.files.WriteZip(io.MultiWriter(c.Writer, zipHash)); err != nil {
+		log.Printf("无法创建ZIP文件: %v", err)
+		c.Abort()
+		return
+	}
+	if record {
+		recordGeneration(job.data, zipHash)
+	}
+}
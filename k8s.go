@@ -0,0 +1,223 @@
+package main
+
+import "strings"
+
+// Kubernetes 清单和 Skaffold 配置模板，KubernetesEnabled 启用时在 k8s/ 下生成；
+// IstioEnabled 时额外生成 VirtualService 和 DestinationRule，并为命名空间开启 sidecar 注入
+
+// 镜像名和 Kubernetes 资源名，只能包含小写字母、数字和连字符
+func (p ProjectConfig) KubeName() string {
+	return strings.ReplaceAll(strings.ToLower(p.ProjectName), "_", "-")
+}
+
+// Skaffold 模板变量中的镜像标识，如 IMAGE_REPO_<key>，非字母数字字符替换为下划线
+func (p ProjectConfig) SkaffoldImageKey() string {
+	return strings.ReplaceAll(p.KubeName(), "-", "_")
+}
+
+const k8sNamespaceTemplate = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: {{.Project.KubeName}}
+{{- if .Project.IstioEnabled}}
+  labels:
+    istio-injection: enabled
+{{- end}}
+`
+
+const k8sDeploymentTemplate = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{.Project.KubeName}}
+  namespace: {{.Project.KubeName}}
+  labels:
+    app: {{.Project.KubeName}}
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: {{.Project.KubeName}}
+  template:
+    metadata:
+      labels:
+        app: {{.Project.KubeName}}
+    spec:
+      containers:
+        - name: {{.Project.KubeName}}
+          image: {{.Project.KubeName}}
+          ports:
+            - name: http
+              containerPort: {{.Project.Port}}
+{{- if .Project.GRPCEnabled}}
+            - name: grpc
+              containerPort: {{.Project.GRPCPort}}
+{{- end}}
+          # 覆盖镜像中 .env 的同名配置
+          env:
+            - name: DB_HOST
+              value: mysql
+          livenessProbe:
+{{- if .Project.GRPCEnabled}}
+            # gRPC 探针需要 Kubernetes 1.24 及以上
+            grpc:
+              port: {{.Project.GRPCPort}}
+{{- else}}
+            httpGet:
+              path: /health
+              port: http
+{{- end}}
+          readinessProbe:
+            httpGet:
+              path: /health
+              port: http
+`
+
+const k8sServiceTemplate = `apiVersion: v1
+kind: Service
+metadata:
+  name: {{.Project.KubeName}}
+  namespace: {{.Project.KubeName}}
+spec:
+  selector:
+    app: {{.Project.KubeName}}
+  ports:
+    - name: http
+      port: 80
+      targetPort: http
+`
+
+const skaffoldTemplate = `apiVersion: skaffold/v4beta11
+kind: Config
+metadata:
+  name: {{.Project.KubeName}}
+build:
+  # 本地集群（minikube、kind、Docker Desktop）直接使用本地镜像，不推送到仓库
+  local:
+    push: false
+  artifacts:
+    - image: {{.Project.KubeName}}
+      docker:
+        dockerfile: Dockerfile
+{{- if .Project.HelmEnabled}}
+deploy:
+  helm:
+    releases:
+      - name: {{.Project.KubeName}}
+        chartPath: helm/{{.Project.ProjectName}}
+        setValueTemplates:
+          image.repository: "{{"{{"}}.IMAGE_REPO_{{.Project.SkaffoldImageKey}}{{"}}"}}"
+          image.tag: "{{"{{"}}.IMAGE_TAG_{{.Project.SkaffoldImageKey}}{{"}}"}}@{{"{{"}}.IMAGE_DIGEST_{{.Project.SkaffoldImageKey}}{{"}}"}}"
+          image.pullPolicy: IfNotPresent
+{{- else}}
+manifests:
+  rawYaml:
+    - k8s/*.yaml
+deploy:
+  kubectl: {}
+{{- end}}
+`
+
+// 对 Service 的所有请求最多重试 3 次，整个请求（含重试）5 秒超时
+const istioVirtualServiceTemplate = `apiVersion: networking.istio.io/v1beta1
+kind: VirtualService
+metadata:
+  name: {{.Project.KubeName}}
+  namespace: {{.Project.KubeName}}
+spec:
+  hosts:
+    - {{.Project.KubeName}}
+  http:
+    - route:
+        - destination:
+            host: {{.Project.KubeName}}
+            port:
+              number: 80
+      timeout: 5s
+      retries:
+        attempts: 3
+        perTryTimeout: 2s
+        retryOn: 5xx,reset,connect-failure,retriable-4xx
+`
+
+// 连接池的空闲超时与应用的请求超时一致，启用 HTTP/2 时由 sidecar 升级到 h2
+const istioDestinationRuleTemplate = `apiVersion: networking.istio.io/v1beta1
+kind: DestinationRule
+metadata:
+  name: {{.Project.KubeName}}
+  namespace: {{.Project.KubeName}}
+spec:
+  host: {{.Project.KubeName}}
+  trafficPolicy:
+    connectionPool:
+      tcp:
+        maxConnections: 100
+        connectTimeout: 5s
+      http:
+        http1MaxPendingRequests: 100
+        maxRequestsPerConnection: 100
+{{- if gt .Project.RequestTimeoutSec 0}}
+        idleTimeout: {{.Project.RequestTimeoutSec}}s
+{{- end}}
+{{- if .Project.HTTP2Push}}
+        h2UpgradePolicy: UPGRADE
+{{- end}}
+    outlierDetection:
+      consecutive5xxErrors: 5
+      interval: 30s
+      baseEjectionTime: 30s
+`
+
+const clientCertTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package middlewares
+
+import "github.com/gin-gonic/gin"
+
+// ClientCert 把 Istio sidecar 在 mTLS 下添加的 X-Forwarded-Client-Cert 请求头写入响应，
+// 便于调用方确认服务端看到的身份；请求未经过 sidecar 时没有该请求头，响应也不设置
+func ClientCert() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if xfcc := c.GetHeader("X-Forwarded-Client-Cert"); xfcc != "" {
+			c.Header("X-Forwarded-Client-Cert", xfcc)
+		}
+		c.Next()
+	}
+}
+`
+
+// 由模块路径推断的 Git 仓库地址，模块路径不是代码托管地址时返回占位符
+func (p ProjectConfig) GitRepoURL() string {
+	for _, host := range []string{"github.com/", "gitlab.com/", "bitbucket.org/"} {
+		if strings.HasPrefix(p.ModuleName, host) {
+			return "https://" + p.ModuleName + ".git"
+		}
+	}
+	return "https://git.example.com/" + p.KubeName() + ".git"
+}
+
+const argoCDApplicationTemplate = `apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata:
+  name: {{.Project.KubeName}}
+  namespace: argocd
+spec:
+  project: default
+  source:
+    repoURL: {{.Project.GitRepoURL}}
+    targetRevision: HEAD
+{{- if .Project.HelmEnabled}}
+    path: helm/{{.Project.ProjectName}}
+    helm:
+      releaseName: {{.Project.KubeName}}
+{{- else}}
+    path: k8s
+{{- end}}
+  destination:
+    server: https://kubernetes.default.svc
+    namespace: {{.Project.KubeName}}
+  syncPolicy:
+    automated:
+      prune: true
+      selfHeal: true
+    syncOptions:
+      - CreateNamespace=true
+`
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"embed"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 许可证全文，按 SPDX 标识命名
+//
+//go:embed licenses
+var licenseTexts embed.FS
+
+// 生成 LICENSE 文件，替换版权声明行中的年份和版权所有者占位符
+type licenseFileGenerator struct{}
+
+var licenseGenerator = licenseFileGenerator{}
+
+func (licenseFileGenerator) Name() string {
+	return "license"
+}
+
+func (licenseFileGenerator) Generate(data TemplateData, fs FileSystem) error {
+	if data.Project.License == "" || data.Project.License == "none" {
+		return nil
+	}
+	text, err := licenseTexts.ReadFile("licenses/" + data.Project.License + ".txt")
+	if err != nil {
+		return err
+	}
+
+	return fs.WriteFile("LICENSE", []byte(fillCopyrightLine(string(text), data.Project.CopyrightOwner())))
+}
+
+// 只替换顶格的版权声明行（MIT、Proprietary），Apache-2.0 和 GPL 系列附录
+// "How to apply" 中缩进的示例声明须保留占位符
+func fillCopyrightLine(text, owner string) string {
+	replacer := strings.NewReplacer("[year]", strconv.Itoa(time.Now().Year()), "[fullname]", owner)
+	lines := strings.SplitAfter(text, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "Copyright") {
+			lines[i] = replacer.Replace(line)
+			break
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+// 版权所有者，未填写作者时使用项目名
+func (p ProjectConfig) CopyrightOwner() string {
+	if p.AuthorName != "" {
+		return p.AuthorName
+	}
+	return "The " + p.ProjectName + " Authors"
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLicenseFillsOnlyCopyrightLine(t *testing.T) {
+	year := strconv.Itoa(time.Now().Year())
+	tests := []struct {
+		license string
+		want    []string
+	}{
+		{license: "MIT", want: []string{"Copyright (c) " + year + " Jane Doe\n"}},
+		{license: "Proprietary", want: []string{"Copyright (c) " + year + " Jane Doe. All rights reserved."}},
+		// 附录中的示例声明保留占位符
+		{license: "Apache-2.0", want: []string{"Copyright [yyyy] [name of copyright owner]"}},
+		{license: "GPL-3.0", want: []string{"Copyright (C) <year>  <name of author>", "<program>  Copyright (C) <year>  <name of author>"}},
+		{license: "AGPL-3.0", want: []string{"Copyright (C) <year>  <name of author>"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.license, func(t *testing.T) {
+			files := NewMemFileSystem()
+			data := TemplateData{Project: ProjectConfig{ProjectName: "shop", AuthorName: "Jane Doe", License: tt.license}}
+			if err := licenseGenerator.Generate(data, files); err != nil {
+				t.Fatal(err)
+			}
+			content := string(files.Files["LICENSE"])
+			for _, want := range tt.want {
+				if !strings.Contains(content, want) {
+					t.Errorf("LICENSE 缺少 %q", want)
+				}
+			}
+		})
+	}
+}
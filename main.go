@@ -2,13 +2,17 @@ package main
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"fmt"
-	"html/template"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"text/template"
+	"unicode"
 
 	"archive/zip"
 	"io"
@@ -21,6 +25,113 @@ type ProjectConfig struct {
 	ProjectName string
 	ModuleName  string
 	Port        string
+	RBAC        bool
+	GoVersion   string
+	Language    string // 生成项目错误消息的默认语言: "en"、"zh"、"es" 或 "fr"
+	FileHeader  string // 添加到每个生成的 .go 文件开头的注释，为空则不添加
+	// JSON 标签命名风格: "snake_case"（默认）、"camelCase" 或 "PascalCase"
+	JSONTagStyle string
+	// 所有表名的前缀，如 "crm_"，便于多个应用共用一个数据库
+	TablePrefix string
+	DBDriver    string // 数据库驱动: "mysql"（默认）或 "postgres"
+	// MySQL 连接和建表使用的字符集与排序规则，默认 utf8mb4 / utf8mb4_unicode_ci
+	MySQLCharset   string
+	MySQLCollation string
+	AuthMode       string // "none"、"session" 或 "magiclink"
+	TOTPEnabled    bool
+	QuotaEnabled   bool // 在 Redis 中按月统计每个登录用户的请求数，超过 USER_MONTHLY_QUOTA 时返回 429
+
+	DatadogEnabled bool
+	SentryEnabled  bool
+	MetricsEnabled bool
+	TracingBackend string // ""、"otlp"、"jaeger" 或 "zipkin"
+	LokiEnabled    bool
+
+	ConsulEnabled bool   // 启动时注册到 Consul，退出时注销，数据库配置可从 Consul KV 读取
+	ConsulAddress string // Consul agent 地址，写入 .env 的 CONSUL_ADDRESS
+
+	ElasticsearchEnabled bool   // 为 @search 模型生成 Elasticsearch 索引同步和搜索接口
+	ElasticsearchURL     string // 写入 .env 的 ELASTICSEARCH_URL
+	AlgoliaEnabled       bool   // 为 @search 模型生成 Algolia 索引同步和搜索接口，与 Elasticsearch 二选一
+
+	SeedEnabled bool
+	SeedCount   int
+
+	GenerateTests bool // 为每个模型生成处理器基准测试
+
+	// 为每个模型生成 pkg/events 下的命令和事件，创建、更新接口同时把事件写入 event_store 表
+	EventSourcing bool
+	// 写操作放在 pkg/commands 并使用主库，列表和详情接口通过 pkg/queries 的只读模型查询 DB_READ_HOST 只读库
+	CQRS bool
+
+	StripeEnabled bool // 生成 Stripe 结账、webhook 和退款接口，需要有模型声明 float 类型的 price 字段
+	EmailEnabled  bool // 生成通过 SendGrid 发送欢迎、重置密码、验证邮件的 pkg/notifications
+
+	SlackEnabled    bool   // 生成通过 incoming webhook 发送 Slack 消息的 pkg/notifications/slack.go
+	SlackWebhookURL string // 写入 .env 的 SLACK_WEBHOOK_URL，可留空
+	// 生成 FCM 推送、POST /notifications/send 和 Notification 模型，创建 Notification 时发送推送
+	FCMEnabled bool
+	// 生成 Twilio 短信、POST /notifications/sms 和记录发送结果的 SMSLog 模型
+	TwilioEnabled bool
+	// 生成 pkg/chat 和 GET /ws/chat/:roomId 聊天室，消息保存到 Message 模型并通过 Redis Pub/Sub 在多个实例间转发
+	ChatEnabled bool
+	// 添加带已读状态的 Notification 模型、批量已读和未读数接口，以及投递到 webhook、Slack、FCM 的 pkg/notifications/publisher.go
+	NotificationCenter bool
+
+	MaxRequestBodyMB  int // 请求体大小上限（MB），0 表示不限制
+	RequestTimeoutSec int // 请求超时时间（秒），0 表示不限制
+	RateLimitRPS      int // 每个客户端每秒允许的请求数，0 表示不限流
+	// X-API-Key 属于 RATE_LIMIT_API_KEYS 的请求按 API key 限流，其余请求仍按客户端 IP 限流
+	RateLimitByAPIKey bool
+	SecurityHeaders   bool
+	IPWhitelist       string // 逗号分隔的 CIDR，非空时只允许这些地址访问
+	IPBlacklist       string // 逗号分隔的 CIDR，拒绝这些地址访问
+	// 在 /admin 提供 HTTP Basic 认证的管理接口：模型记录数、清理软删除记录、进程内计数器
+	AdminPanelEnabled bool
+
+	TLSEnabled        bool
+	TLSCertFile       string
+	TLSKeyFile        string
+	LetsEncryptDomain string // 非空时通过 autocert 自动申请和续期证书，忽略证书文件
+	LetsEncryptEmail  string
+	HTTP2Push         bool // 启用 HTTP/2 并为相关资源添加预取响应头
+	GRPCEnabled       bool // 在 GRPC_PORT 上启动 gRPC 服务器，提供 grpc.health.v1 健康检查
+	SwaggerUI         bool // 在 /docs/ 提供内嵌的 Swagger UI
+
+	IaCDriver         string // 基础设施即代码: "terraform"、"pulumi" 或 "none"
+	HelmEnabled       bool   // 生成 Kubernetes Helm chart
+	KubernetesEnabled bool   // 生成 k8s/ 下的 Kubernetes 清单
+	ArgoCD            bool   // 生成指向 Helm chart 或 k8s 清单的 ArgoCD Application
+	IstioEnabled      bool   // 生成 Istio VirtualService、DestinationRule，命名空间开启 sidecar 注入
+
+	GithubActionsEnabled bool // 生成 CI 和发布工作流
+	SBOMEnabled          bool // 生成 make sbom，发布时附带 CycloneDX 物料清单
+	VulnScanEnabled      bool // 生成 make vuln，CI 中运行 govulncheck
+	ChangelogEnabled     bool // 生成 git-chglog 配置和 make changelog
+	PreCommitEnabled     bool // 生成 .pre-commit-config.yaml 和 make install-hooks
+	RenovateEnabled      bool // 生成 Renovate 依赖更新配置
+
+	MonorepoEnabled bool // 项目生成到 <ProjectName>/ 子目录，根目录生成 go.work 和工作区 Makefile
+
+	// 生成后在生成器所在环境运行 go mod tidy 补充 go.sum，需要联网，预览时忽略
+	GoSum bool
+
+	GenerateCommunityFiles bool   // 生成 CONTRIBUTING.md 和 CODE_OF_CONDUCT.md
+	BranchingStrategy      string // 贡献指南中的分支策略: "trunk" 或 "gitflow"
+
+	License     string // SPDX 标识，见 supportedLicenses，"none" 表示不生成 LICENSE
+	AuthorName  string
+	AuthorEmail string // 行为准则中的举报联系方式
+}
+
+// 是否生成IP过滤中间件
+func (p ProjectConfig) IPFilterEnabled() bool {
+	return p.IPWhitelist != "" || p.IPBlacklist != ""
+}
+
+// 是否使用 Let's Encrypt 自动证书
+func (p ProjectConfig) AutoCertEnabled() bool {
+	return p.TLSEnabled && p.LetsEncryptDomain != ""
 }
 
 // 模型字段结构
@@ -30,29 +141,105 @@ type ModelField struct {
 	JsonTag  string
 	GormTag  string
 	Required bool
+	Order    int    // 字段在结构体中的位置，解析时按声明顺序编号，见 Model.OrderedFields
+	Default  string // 声明的默认值，如 "draft"、"0"，写入 gorm 标签、迁移脚本和 New<Model> 构造函数
+	// 字符串的最小、最大字符数，0 表示不限制，由模型的 Validate 方法检查
+	MinLength int
+	MaxLength int
 }
 
 // 模型结构
 type Model struct {
-	Name       string
-	Fields     []ModelField
-	SnakeName  string
-	LowerName  string
-	PluralName string
+	Name        string
+	Fields      []ModelField
+	SnakeName   string
+	LowerName   string
+	PluralName  string
+	IDType      string              // 主键类型: "uint"（默认）、"int64"、"string" 或 "uuid"
+	Permissions map[string][]string // HTTP方法 -> 允许的角色
+	Hooks       []HookDef
+	Scopes      []ScopeDef
+	// 在 <snake>_histories 表中记录每次创建、更新、删除后的快照
+	VersionHistory bool
+	// 查询显式过滤 deleted_at IS NULL，并生成可查看、恢复软删除记录的管理接口
+	ParanoidMode bool
+	// 创建记录后在 AfterCreate 钩子中发送 Slack 通知，需要启用 SlackEnabled
+	SlackNotify bool
+	// FCMEnabled 添加的 Notification 模型，BeforeCreate 中发送推送并记录 SentAt
+	PushNotification bool
+	// NotificationCenter 添加的 Notification 模型，AfterCreate 中投递到已注册的通知渠道
+	PublishNotification bool
+	// 写入后在 AfterCreate、AfterUpdate、AfterDelete 钩子中同步到搜索索引，并生成 /search 接口
+	SearchEnabled bool
+	// 在 parseModels 输入中的块序号，从 1 开始，被跳过的块也计数；生成器添加的模型为 0
+	Block int
+}
+
+// 模型上的命名查询条件，生成 GORM scope 函数，列表接口可通过 ?scope=Name 使用
+type ScopeDef struct {
+	Name      string
+	Condition string // 原样传给 db.Where，如 "status = 'active'"
+}
+
+// 模型上的 GORM 钩子方法，Body 为方法体中的 Go 代码，可以使用接收者 m 和参数 tx
+type HookDef struct {
+	Name string // 如 "BeforeCreate"，见 gormHookNames
+	Body string
+}
+
+// 方法体的各行代码，按花括号嵌套层级缩进
+func (h HookDef) Lines() []string {
+	lines := strings.Split(h.Body, "\n")
+	depth := 0
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "}") && depth > 0 {
+			depth--
+		}
+		lines[i] = strings.Repeat("\t", depth) + line
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+		if strings.HasPrefix(line, "}") {
+			depth++
+		}
+		if depth < 0 {
+			depth = 0
+		}
+	}
+	return lines
+}
+
+// 方法体以 return 结尾时模板不再追加 return nil
+func (h HookDef) EndsWithReturn() bool {
+	lines := h.Lines()
+	last := strings.TrimSpace(lines[len(lines)-1])
+	return last == "return" || strings.HasPrefix(last, "return ")
+}
+
+// 认证用户模型，见 findAuthUser
+type AuthUser struct {
+	Model         Model
+	LoginField    ModelField
+	PasswordField ModelField
 }
 
 // 模板数据
 type TemplateData struct {
 	Project   ProjectConfig
 	Models    []Model
+	AuthUser  *AuthUser
 	Timestamp string
+
+	// 非空时只生成这些类别的文件（生成器名称，如 "models"、"handlers"），其余文件不输出
+	RegenerateOnly []string
+	// 自定义模板使用的分隔符，内置模板不受影响
+	Delims TemplateDelims
 }
 
-const dockerfileTemplate = `FROM golang:1.20-alpine AS builder
+const dockerfileTemplate = `FROM golang:{{.Project.GoVersion}}-alpine AS builder
 
 WORKDIR /app
 COPY . .
-RUN go mod download
+RUN go mod tidy
 RUN go build -o main cmd/main.go
 
 FROM alpine:latest
@@ -60,7 +247,7 @@ WORKDIR /app
 COPY --from=builder /app/main .
 COPY --from=builder /app/.env .
 
-EXPOSE {{.Project.Port}}
+EXPOSE {{.Project.Port}}{{if .Project.GRPCEnabled}} {{.Project.GRPCPort}}{{end}}
 CMD ["./main"]
 `
 
@@ -70,20 +257,94 @@ const gitignoreTemplate = `# Binaries
 *.dll
 *.so
 *.dylib
+{{- if .Project.TLSEnabled}}
+
+# TLS 证书和私钥
+certs/
+{{- end}}
+{{- if .Project.SBOMEnabled}}
+
+# 本地生成的软件物料清单
+.sbom/
+{{- end}}
 `
 
-// 创建ZIP文件函数
-func createZip(sourceDir, targetZip string) error {
-	zipFile, err := os.Create(targetZip)
-	if err != nil {
-		return err
-	}
-	defer zipFile.Close()
+const makefileTemplate = `.PHONY: build run test migrate migrate-dry{{if .Project.SeedEnabled}} seed{{end}}{{if .Project.GenerateTests}} bench{{end}}{{if and .Project.TLSEnabled (not .Project.AutoCertEnabled)}} dev-cert{{end}}{{if .Project.SBOMEnabled}} sbom{{end}}{{if .Project.VulnScanEnabled}} vuln{{end}}{{if .Project.ChangelogEnabled}} changelog{{end}}{{if .Project.PreCommitEnabled}} install-hooks{{end}}
+
+# 生成器环境没有 Go 工具链时项目不带 go.sum，首次构建前需要解析依赖
+go.sum: go.mod
+	go mod tidy
+
+build: go.sum
+	go build -o bin/{{.Project.ProjectName}} cmd/main.go
+
+run: go.sum
+	go run cmd/main.go
+
+test: go.sum
+	go test ./...
+
+# 用 GORM AutoMigrate 创建或更新所有模型的表
+migrate: go.sum
+	go run cmd/migrate/main.go --action=up
+
+# 只打印 migrate 将执行的 SQL，不修改数据库
+migrate-dry: go.sum
+	go run cmd/migrate/main.go --action=up --dry-run
+{{- if .Project.SeedEnabled}}
+
+# 生成测试数据，可通过 COUNT 覆盖每个模型的条数
+COUNT ?= {{.Project.SeedCount}}
+seed: go.sum
+	go run cmd/seed/main.go --count=$(COUNT)
+{{- end}}
+{{- if .Project.GenerateTests}}
+
+# 运行处理器基准测试，结果保存到 bench_results.txt 便于对比
+bench: go.sum
+	go test -run='^$$' -bench=. -benchmem ./pkg/handlers/ | tee bench_results.txt
+{{- end}}
+{{- if and .Project.TLSEnabled (not .Project.AutoCertEnabled)}}
+
+# 生成本地开发用的自签名证书
+dev-cert:
+	sh scripts/generate-dev-cert.sh
+{{- end}}
+{{- if .Project.SBOMEnabled}}
+
+# 生成 CycloneDX 软件物料清单，需要先安装:
+# go install github.com/CycloneDX/cyclonedx-gomod/cmd/cyclonedx-gomod@latest
+sbom: go.sum
+	mkdir -p .sbom
+	cyclonedx-gomod mod -licenses -output .sbom/sbom.xml
+{{- end}}
+{{- if .Project.VulnScanEnabled}}
+
+# 检查依赖和代码中的已知漏洞，需要先安装:
+# go install golang.org/x/vuln/cmd/govulncheck@latest
+vuln: go.sum
+	govulncheck ./...
+{{- end}}
+{{- if .Project.ChangelogEnabled}}
+
+# 根据 Conventional Commits 提交记录更新 CHANGELOG.md，需要先安装:
+# go install github.com/git-chglog/git-chglog/cmd/git-chglog@latest
+changelog:
+	git-chglog -o CHANGELOG.md
+{{- end}}
+{{- if .Project.PreCommitEnabled}}
+
+# 安装 git 提交前钩子，需要 pre-commit、goimports 和 golangci-lint
+install-hooks:
+	pre-commit install
+{{- end}}
+`
 
-	zipWriter := zip.NewWriter(zipFile)
-	defer zipWriter.Close()
+// 将目录打包为ZIP并写入 w
+func createZip(sourceDir string, w io.Writer) error {
+	zipWriter := zip.NewWriter(w)
 
-	return filepath.Walk(sourceDir, func(filePath string, info os.FileInfo, err error) error {
+	err := filepath.Walk(sourceDir, func(filePath string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -122,9 +383,34 @@ func createZip(sourceDir, targetZip string) error {
 		_, err = io.Copy(zipEntry, srcFile)
 		return err
 	})
+	if err != nil {
+		return err
+	}
+
+	// Close 会写入中央目录，必须检查错误
+	return zipWriter.Close()
 }
 
 func main() {
+	// 带参数运行时进入命令行模式，见 runCLI
+	if len(os.Args) > 1 {
+		if err := runCLI(os.Args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// 生成历史，数据库无法打开时仍可正常生成
+	if store, err := openHistoryStore(historyDBPath()); err != nil {
+		log.Printf("无法打开生成历史数据库，不记录历史: %v", err)
+	} else {
+		history = store
+	}
+
+	shareSecret = loadShareSecret()
+	goSumAllowed = loadGoSumAllowed()
+
 	router := gin.Default()
 	router.Static("/static", "./static")
 	router.LoadHTMLGlob("templates/*")
@@ -132,26 +418,22 @@ func main() {
 	// 首页
 	router.GET("/", func(c *gin.Context) {
 		c.HTML(http.StatusOK, "index.html", gin.H{
-			"title": "Gin CRUD 代码生成器",
+			"title":        "Gin CRUD 代码生成器",
+			"goSumAllowed": goSumAllowed,
 		})
 	})
 
 	// 生成项目
 	router.POST("/generate", func(c *gin.Context) {
-		// 解析表单数据
-		projectName := c.PostForm("project_name")
-		moduleName := c.PostForm("module_name")
-		port := c.PostForm("port")
-		models := parseModels(c.PostForm("models"))
-
-		// 创建模板数据
-		data := TemplateData{
-			Project: ProjectConfig{
-				ProjectName: projectName,
-				ModuleName:  moduleName,
-				Port:        port,
-			},
-			Models: models,
+		// JSON 请求体一次生成多个项目，见 generateMultipleProjects
+		if c.ContentType() == "application/json" {
+			generateMultipleProjects(c)
+			return
+		}
+
+		data, ok := bindTemplateData(c)
+		if !ok {
+			return
 		}
 
 		// 创建临时目录
@@ -162,40 +444,497 @@ func main() {
 		defer os.RemoveAll(tempDir)
 
 		// 生成项目结构
-		generateProjectStructure(tempDir, data)
-
-		// 创建ZIP文件
-		zipPath := filepath.Join(os.TempDir(), projectName+".zip")
-		if err := createZip(tempDir, zipPath); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "无法创建ZIP文件: " + err.Error()})
+		if err := generateProjectStructure(OSFileSystem{BaseDir: tempDir}, data); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "无法生成项目: " + err.Error()})
 			return
 		}
 
-		// 提供下载
+		// 提供下载：ZIP直接写入响应，大小未知，使用分块传输；同时计算哈希记入生成历史
+		if warning := portWarning(data.Project.Port); warning != "" {
+			c.Header("X-Generator-Warning", warning)
+		}
 		c.Header("Content-Description", "File Transfer")
-		c.Header("Content-Disposition", "attachment; filename="+projectName+".zip")
+		c.Header("Content-Disposition", "attachment; filename="+data.Project.ProjectName+".zip")
 		c.Header("Content-Type", "application/zip")
-		c.File(zipPath)
+		c.Status(http.StatusOK)
+
+		zipHash := sha256.New()
+		if err := createZip(tempDir, io.MultiWriter(c.Writer, zipHash)); err != nil {
+			// 响应头已发送，只能记录错误并中断连接
+			log.Printf("无法创建ZIP文件: %v", err)
+			c.Abort()
+			return
+		}
+		recordGeneration(data, zipHash)
 	})
 
+	// 从现有数据库表结构生成模型定义
+	router.POST("/introspect", introspectDatabase)
+
+	// 生成项目前测试数据库连接参数
+	router.POST("/test-connection", testConnection)
+
+	// 校验模型定义，网页表单输入时调用
+	router.POST("/validate", validateModels)
+
+	// 调整字段顺序，网页表单拖拽排序后调用
+	router.POST("/reorder", reorderModels)
+
+	// 字段类型和校验标签，供模型定义自动补全
+	router.GET("/api/field-types", listFieldTypes)
+	router.GET("/api/validators", listValidators)
+
+	// 分享链接，打开预填配置的表单
+	router.POST("/share", createShareURL)
+	router.GET("/s/:token", openShareURL)
+
+	// 生成历史
+	router.GET("/history", listHistory)
+	router.GET("/history/:id/config", historyConfig)
+	router.GET("/history/:id/download", downloadHistory)
+
+	// 从生成的项目ZIP还原配置，便于修改后重新生成
+	router.POST("/export-config", exportConfig)
+
+	// 从 OpenAPI 文档导入模型定义
+	router.POST("/import-openapi", importOpenAPI)
+
+	// 实体关系图
+	router.GET("/erd", renderERD)
+
+	// 语法高亮的代码预览
+	router.GET("/preview-html", previewHTML)
+
+	// 异步生成，通过 SSE 查询进度
+	router.POST("/generate/start", startGenerateJob)
+	router.GET("/generate/status/:jobId", streamGenerateStatus)
+	router.GET("/generate/download/:jobId", downloadGenerateJob)
+
 	// 启动服务器
 	fmt.Println("Gin CRUD 代码生成器运行在 http://localhost:8080")
 	router.Run(":8080")
 }
 
-// 解析模型定义
-func parseModels(input string) []Model {
+// 解析并校验 /generate 表单，失败时已写入 400 响应
+func bindTemplateData(c *gin.Context) (TemplateData, bool) {
+	var data TemplateData
+
+	// 解析表单数据
+	projectName, err := sanitizeProjectName(c.PostForm("project_name"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return data, false
+	}
+	moduleName := strings.TrimSpace(c.PostForm("module_name"))
+	if err := validateModuleName(moduleName); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return data, false
+	}
+	port := strings.TrimSpace(c.PostForm("port"))
+	if err := validatePort(port); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return data, false
+	}
+	jsonTagStyle := c.DefaultPostForm("json_tag_style", defaultJSONTagStyle)
+	if err := validateJSONTagStyle(jsonTagStyle); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return data, false
+	}
+	models := parseModels(c.PostForm("models"), jsonTagStyle)
+
+	// 校验模型定义
+	if errs := modelErrors(models); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errs})
+		return data, false
+	}
+
+	// 创建模板数据
+	data = TemplateData{
+		Project: ProjectConfig{
+			ProjectName:    projectName,
+			ModuleName:     moduleName,
+			Port:           port,
+			GoVersion:      c.DefaultPostForm("go_version", defaultGoVersion),
+			Language:       c.DefaultPostForm("language", "en"),
+			FileHeader:     normalizeFileHeader(c.PostForm("file_header")),
+			JSONTagStyle:   jsonTagStyle,
+			TablePrefix:    strings.TrimSpace(c.PostForm("table_prefix")),
+			DBDriver:       c.DefaultPostForm("db_driver", "mysql"),
+			MySQLCharset:   c.DefaultPostForm("mysql_charset", defaultMySQLCharset),
+			MySQLCollation: c.DefaultPostForm("mysql_collation", defaultMySQLCollation),
+			RBAC:           c.PostForm("rbac") == "on",
+			AuthMode:       c.DefaultPostForm("auth_mode", "none"),
+			TOTPEnabled:    c.PostForm("totp") == "on",
+			QuotaEnabled:   c.PostForm("quota") == "on",
+
+			DatadogEnabled: c.PostForm("datadog") == "on",
+			SentryEnabled:  c.PostForm("sentry") == "on",
+			MetricsEnabled: c.PostForm("metrics") == "on",
+			TracingBackend: c.PostForm("tracing_backend"),
+			LokiEnabled:    c.PostForm("loki") == "on",
+
+			ConsulEnabled: c.PostForm("consul") == "on",
+			ConsulAddress: strings.TrimSpace(c.DefaultPostForm("consul_address", defaultConsulAddress)),
+
+			ElasticsearchEnabled: c.PostForm("elasticsearch") == "on",
+			ElasticsearchURL:     strings.TrimSpace(c.DefaultPostForm("elasticsearch_url", defaultElasticsearchURL)),
+			AlgoliaEnabled:       c.PostForm("algolia") == "on",
+
+			SeedEnabled: c.PostForm("seed") == "on",
+
+			GenerateTests: c.PostForm("generate_tests") == "on",
+			// 默认启用，表单通过下拉框传入 "off" 关闭
+			SecurityHeaders: c.DefaultPostForm("security_headers", "on") == "on",
+			IPWhitelist:     normalizeCIDRList(c.PostForm("ip_whitelist")),
+			IPBlacklist:     normalizeCIDRList(c.PostForm("ip_blacklist")),
+
+			AdminPanelEnabled: c.PostForm("admin_panel") == "on",
+
+			TLSEnabled:  c.PostForm("tls") == "on",
+			TLSCertFile: strings.TrimSpace(c.DefaultPostForm("tls_cert_file", "certs/server.crt")),
+			TLSKeyFile:  strings.TrimSpace(c.DefaultPostForm("tls_key_file", "certs/server.key")),
+
+			LetsEncryptDomain: strings.ToLower(strings.TrimSpace(c.PostForm("letsencrypt_domain"))),
+			LetsEncryptEmail:  strings.TrimSpace(c.PostForm("letsencrypt_email")),
+			HTTP2Push:         c.PostForm("http2_push") == "on",
+			SwaggerUI:         c.PostForm("swagger_ui") == "on",
+
+			IaCDriver:         c.DefaultPostForm("iac_driver", "none"),
+			HelmEnabled:       c.PostForm("helm") == "on",
+			KubernetesEnabled: c.PostForm("kubernetes") == "on",
+			ArgoCD:            c.PostForm("argocd") == "on",
+			IstioEnabled:      c.PostForm("istio") == "on",
+
+			GithubActionsEnabled: c.PostForm("github_actions") == "on",
+			SBOMEnabled:          c.PostForm("sbom") == "on",
+			VulnScanEnabled:      c.PostForm("vuln_scan") == "on",
+			ChangelogEnabled:     c.PostForm("changelog") == "on",
+			PreCommitEnabled:     c.PostForm("pre_commit") == "on",
+			RenovateEnabled:      c.PostForm("renovate") == "on",
+
+			MonorepoEnabled: c.PostForm("monorepo") == "on",
+			GoSum:           c.PostForm("go_sum") == "on",
+
+			GRPCEnabled: c.PostForm("grpc") == "on",
+
+			StripeEnabled: c.PostForm("stripe") == "on",
+			EmailEnabled:  c.PostForm("email_notifications") == "on",
+
+			SlackEnabled:    c.PostForm("slack") == "on",
+			SlackWebhookURL: strings.TrimSpace(c.PostForm("slack_webhook_url")),
+
+			FCMEnabled:    c.PostForm("fcm") == "on",
+			TwilioEnabled: c.PostForm("twilio") == "on",
+			ChatEnabled:   c.PostForm("chat") == "on",
+
+			NotificationCenter: c.PostForm("notification_center") == "on",
+
+			EventSourcing: c.PostForm("event_sourcing") == "on",
+			CQRS:          c.PostForm("cqrs") == "on",
+
+			GenerateCommunityFiles: c.PostForm("community_files") == "on",
+			BranchingStrategy:      c.DefaultPostForm("branching_strategy", "trunk"),
+
+			License:     c.DefaultPostForm("license", "none"),
+			AuthorName:  strings.TrimSpace(c.PostForm("author_name")),
+			AuthorEmail: strings.TrimSpace(c.PostForm("author_email")),
+		},
+		Models: models,
+	}
+
+	if err := validateAuthMode(data.Project.AuthMode); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return data, false
+	}
+
+	if data.Project.AuthMode != "none" {
+		data.AuthUser = findAuthUser(models, data.Project.AuthMode)
+		if data.AuthUser == nil && data.Project.AuthMode == "magiclink" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Magic Link 认证需要一个包含 email 字段的用户模型"})
+			return data, false
+		}
+		if data.AuthUser == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "认证模式需要一个包含 password 字段的用户模型"})
+			return data, false
+		}
+		// 密码在 BeforeSave 中哈希，Validate 检查到的是哈希值
+		if f := data.AuthUser.PasswordField; data.Project.AuthMode == "session" && (f.MinLength > 0 || f.MaxLength > 0) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "密码字段 " + f.Name + " 保存前会被哈希，不能设置 minlen 或 maxlen"})
+			return data, false
+		}
+	}
+
+	if err := validateGoVersion(data.Project.GoVersion); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return data, false
+	}
+
+	if err := validateLanguage(data.Project.Language); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return data, false
+	}
+
+	if err := validateTablePrefix(data.Project.TablePrefix); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return data, false
+	}
+
+	if err := validateDBDriver(data.Project.DBDriver); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return data, false
+	}
+
+	if err := validateMySQLCharset(data.Project.MySQLCharset, data.Project.MySQLCollation); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return data, false
+	}
+
+	if err := validateDomain(data.Project.LetsEncryptDomain); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return data, false
+	}
+	if err := validateLetsEncryptEmail(data.Project.LetsEncryptEmail); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return data, false
+	}
+	if data.Project.GoSum && !goSumAllowed {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "服务器未开启 go.sum 生成 (ALLOW_GO_SUM=1)，请使用命令行 -go-sum"})
+		return data, false
+	}
+
+	if err := validateLicense(data.Project.License); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return data, false
+	}
+
+	if err := validateBranchingStrategy(data.Project.BranchingStrategy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return data, false
+	}
+
+	if err := validateAuthorEmail(data.Project.AuthorEmail); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return data, false
+	}
+
+	if data.Project.GenerateCommunityFiles && data.Project.AuthorEmail == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "生成行为准则需要填写作者邮箱作为举报联系方式"})
+		return data, false
+	}
+
+	if err := validateIaCDriver(data.Project.IaCDriver); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return data, false
+	}
+
+	if data.Project.ArgoCD && !data.Project.HelmEnabled && !data.Project.KubernetesEnabled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ArgoCD 需要同时启用 Helm chart 或 Kubernetes 清单"})
+		return data, false
+	}
+
+	if data.Project.ConsulEnabled && (data.Project.ConsulAddress == "" || strings.ContainsAny(data.Project.ConsulAddress, " \t\r\n")) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Consul 地址不能为空，也不能包含空白字符"})
+		return data, false
+	}
+
+	if data.Project.ElasticsearchEnabled && data.Project.AlgoliaEnabled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Elasticsearch 和 Algolia 只能启用一个"})
+		return data, false
+	}
+
+	if data.Project.ElasticsearchEnabled && (data.Project.ElasticsearchURL == "" || strings.ContainsAny(data.Project.ElasticsearchURL, " \t\r\n")) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Elasticsearch 地址不能为空，也不能包含空白字符"})
+		return data, false
+	}
+
+	if data.Project.IstioEnabled && !data.Project.KubernetesEnabled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Istio 配置需要同时启用 Kubernetes 清单"})
+		return data, false
+	}
+
+	if data.Project.TLSEnabled && data.Project.LetsEncryptDomain == "" && (data.Project.TLSCertFile == "" || data.Project.TLSKeyFile == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "启用 TLS 时需要证书和私钥路径"})
+		return data, false
+	}
+	if data.Project.TLSEnabled && data.Project.LetsEncryptDomain == "" {
+		for _, file := range []string{data.Project.TLSCertFile, data.Project.TLSKeyFile} {
+			if err := validateTLSFilePath(file); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return data, false
+			}
+		}
+	}
+
+	if err := validateCIDRList(data.Project.IPWhitelist); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "IP白名单无效: " + err.Error()})
+		return data, false
+	}
+	if err := validateCIDRList(data.Project.IPBlacklist); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "IP黑名单无效: " + err.Error()})
+		return data, false
+	}
+
+	seedCount, err := strconv.Atoi(c.DefaultPostForm("seed_count", "50"))
+	if err != nil || seedCount <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "种子数据条数必须是正整数"})
+		return data, false
+	}
+	data.Project.SeedCount = seedCount
+
+	maxBodyMB, err := strconv.Atoi(c.DefaultPostForm("max_request_body_mb", "1"))
+	if err != nil || maxBodyMB < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求体大小上限必须是非负整数"})
+		return data, false
+	}
+	data.Project.MaxRequestBodyMB = maxBodyMB
+
+	timeoutSec, err := strconv.Atoi(c.DefaultPostForm("request_timeout_sec", "30"))
+	if err != nil || timeoutSec < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求超时时间必须是非负整数"})
+		return data, false
+	}
+	data.Project.RequestTimeoutSec = timeoutSec
+
+	rateLimitRPS, err := strconv.Atoi(c.DefaultPostForm("rate_limit_rps", "0"))
+	if err != nil || rateLimitRPS < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "限流速率必须是非负整数"})
+		return data, false
+	}
+	data.Project.RateLimitRPS = rateLimitRPS
+	data.Project.RateLimitByAPIKey = c.PostForm("rate_limit_by_api_key") == "on"
+	if data.Project.RateLimitByAPIKey && rateLimitRPS == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "按 API key 限流需要设置限流速率"})
+		return data, false
+	}
+
+	switch data.Project.TracingBackend {
+	case "", "otlp", "jaeger", "zipkin":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "不支持的追踪后端: " + data.Project.TracingBackend})
+		return data, false
+	}
+
+	if data.Project.TOTPEnabled {
+		if data.Project.AuthMode != "session" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "两步验证需要使用 Session 认证方式"})
+			return data, false
+		}
+		if err := addTOTPFields(&data); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return data, false
+		}
+	}
+
+	if data.Project.RBAC {
+		if data.Project.AuthMode == "none" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "RBAC 需要 Session 或 Magic Link 认证，角色从登录用户的 role 列读取"})
+			return data, false
+		}
+		if err := addRoleField(&data); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return data, false
+		}
+	}
+
+	if data.Project.FCMEnabled {
+		if err := addNotificationModel(&data); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return data, false
+		}
+	}
+
+	if data.Project.TwilioEnabled {
+		if err := addSMSLogModel(&data); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return data, false
+		}
+	}
+
+	// 在 FCM 之后添加，同时启用时把 Notification 的设备 token 改为可选
+	if data.Project.NotificationCenter {
+		if err := addNotificationCenterModel(&data); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return data, false
+		}
+	}
+
+	if data.Project.ChatEnabled {
+		if err := addChatMessageModel(&data); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return data, false
+		}
+	}
+
+	if data.Project.SlackEnabled {
+		if err := validateSlackWebhookURL(data.Project.SlackWebhookURL); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return data, false
+		}
+	}
+	for _, m := range data.Models {
+		if m.SlackNotify && !data.Project.SlackEnabled {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "模型 " + m.Name + " 使用了 @slack，需要启用 Slack 通知"})
+			return data, false
+		}
+		if m.ParanoidMode && !data.Project.RBAC && !data.Project.AdminPanelEnabled && data.Project.AuthMode == "none" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "模型 " + m.Name + " 使用了 @paranoid，查看和恢复已删除记录的接口需要启用 RBAC、管理接口或认证"})
+			return data, false
+		}
+		if m.SearchEnabled && !data.Project.SearchBackendEnabled() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "模型 " + m.Name + " 使用了 @search，需要启用 Elasticsearch 或 Algolia"})
+			return data, false
+		}
+	}
+
+	if data.Project.StripeEnabled && data.PaymentModel() == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Stripe 支付需要一个包含 float32 或 float64 类型 price 字段的模型"})
+		return data, false
+	}
+
+	if data.Project.QuotaEnabled && data.Project.AuthMode == "none" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "月度配额需要 Session 或 Magic Link 认证"})
+		return data, false
+	}
+
+	delims, err := parseTemplateDelims(c.Query("template_delims"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return data, false
+	}
+	data.Delims = delims
+
+	// 部分重新生成：可多次提交 regenerate_only，也可用逗号分隔
+	for _, value := range c.PostFormArray("regenerate_only") {
+		for _, category := range strings.Split(value, ",") {
+			category = strings.TrimSpace(category)
+			if category == "" {
+				continue
+			}
+			if !isGeneratorCategory(category) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "未知的文件类别: " + category})
+				return data, false
+			}
+			data.RegenerateOnly = append(data.RegenerateOnly, category)
+		}
+	}
+	return data, true
+}
+
+// 解析模型定义，jsonTagStyle 决定字段的 JSON 标签命名风格
+func parseModels(input string, jsonTagStyle string) []Model {
 	var models []Model
+	// Windows 浏览器提交的表单使用 \r\n 换行
+	input = strings.ReplaceAll(input, "\r\n", "\n")
 	blocks := strings.Split(input, "\n\n")
 
-	for _, block := range blocks {
+	for i, block := range blocks {
 		lines := strings.Split(block, "\n")
 		if len(lines) < 2 {
 			continue
 		}
 
-		modelName := strings.TrimSpace(lines[0])
-		var fields []ModelField
+		model := newModel(strings.TrimSpace(lines[0]))
+		model.Block = i + 1
 
 		for _, line := range lines[1:] {
 			line = strings.TrimSpace(line)
@@ -203,6 +942,12 @@ func parseModels(input string) []Model {
 				continue
 			}
 
+			// 模型级选项以 @ 开头
+			if strings.HasPrefix(line, "@") {
+				parseModelOption(&model, strings.TrimPrefix(line, "@"))
+				continue
+			}
+
 			parts := strings.Fields(line)
 			if len(parts) < 2 {
 				continue
@@ -210,8 +955,12 @@ func parseModels(input string) []Model {
 
 			fieldName := parts[0]
 			fieldType := parts[1]
-			jsonTag := strings.ToLower(fieldName)
+			// 字段需要导出才能被 JSON 绑定和 GORM 读取
+			fieldName = strings.ToUpper(fieldName[:1]) + fieldName[1:]
+			jsonTag := toJSONTag(fieldName, jsonTagStyle)
 			gormTag := ""
+			defaultValue := ""
+			minLength, maxLength := 0, 0
 
 			// 处理字段标签
 			if len(parts) > 2 {
@@ -219,37 +968,330 @@ func parseModels(input string) []Model {
 					if strings.HasPrefix(tag, "gorm:") {
 						gormTag = strings.Trim(tag, "gorm:\"")
 					}
+					if strings.HasPrefix(tag, "default:") {
+						defaultValue = strings.TrimPrefix(tag, "default:")
+					}
+					if strings.HasPrefix(tag, "minlen:") {
+						minLength = parseLengthLimit(strings.TrimPrefix(tag, "minlen:"))
+					}
+					if strings.HasPrefix(tag, "maxlen:") {
+						maxLength = parseLengthLimit(strings.TrimPrefix(tag, "maxlen:"))
+					}
 				}
 			}
 
 			// 默认gorm标签
 			if gormTag == "" {
-				gormTag = "column:" + toSnakeCase(fieldName)
+				gormTag = defaultGormTag(toSnakeCase(fieldName), fieldType)
 			}
 
-			fields = append(fields, ModelField{
-				Name:     fieldName,
-				Type:     fieldType,
-				JsonTag:  jsonTag,
-				GormTag:  gormTag,
-				Required: strings.Contains(line, "required"),
+			model.Fields = append(model.Fields, ModelField{
+				Name:      fieldName,
+				Type:      fieldType,
+				JsonTag:   jsonTag,
+				GormTag:   gormTag,
+				Required:  strings.Contains(line, "required"),
+				Order:     len(model.Fields),
+				Default:   defaultValue,
+				MinLength: minLength,
+				MaxLength: maxLength,
 			})
 		}
 
-		models = append(models, Model{
-			Name:       modelName,
-			Fields:     fields,
-			SnakeName:  toSnakeCase(modelName),
-			LowerName:  strings.ToLower(modelName[:1]) + modelName[1:],
-			PluralName: pluralize(modelName),
-		})
+		normalizeIDField(&model)
+		models = append(models, model)
 	}
 
 	return models
 }
 
+// 未指定 gorm 标签时的默认值
+func defaultGormTag(column, fieldType string) string {
+	tag := "column:" + column
+	// uuid.UUID 是 [16]byte，GORM 无法推断列类型，按字符串存储
+	if fieldType == "uuid.UUID" {
+		tag += ";type:char(36)"
+	}
+	return tag
+}
+
+// 模型是否包含指定类型的字段，主键也计算在内
+func (m Model) UsesFieldType(fieldType string) bool {
+	if m.IDGoType() == fieldType {
+		return true
+	}
+	for _, f := range m.Fields {
+		if f.Type == fieldType {
+			return true
+		}
+	}
+	return false
+}
+
+// 按 Order 排序的字段，Order 相同时保持原有顺序
+func (m Model) OrderedFields() []ModelField {
+	fields := append([]ModelField(nil), m.Fields...)
+	sort.SliceStable(fields, func(i, j int) bool {
+		return fields[i].Order < fields[j].Order
+	})
+	return fields
+}
+
+// 追加字段时使用的 Order，排在所有现有字段之后
+func (m Model) nextFieldOrder() int {
+	next := 0
+	for _, f := range m.Fields {
+		if f.Order >= next {
+			next = f.Order + 1
+		}
+	}
+	return next
+}
+
+// 任一模型是否包含指定类型的字段
+func (d TemplateData) UsesFieldType(fieldType string) bool {
+	for _, m := range d.Models {
+		if m.UsesFieldType(fieldType) {
+			return true
+		}
+	}
+	return false
+}
+
+// 创建模型并填充派生名称，名称为空时由 ValidateModel 报错
+func newModel(name string) Model {
+	return Model{
+		Name:       name,
+		SnakeName:  toSnakeCase(name),
+		LowerName:  lowerFirst(name),
+		PluralName: pluralize(name),
+		IDType:     "uint",
+	}
+}
+
+// 可以声明为主键的字段类型及对应的 IDType
+var idTypesByFieldType = map[string]string{
+	"uint":      "uint",
+	"uint32":    "uint",
+	"uint64":    "uint",
+	"int":       "int64",
+	"int32":     "int64",
+	"int64":     "int64",
+	"string":    "string",
+	"uuid.UUID": "uuid",
+}
+
+// 名为 ID 的字段视为主键声明：移除该字段并据其类型设置 IDType，
+// 类型无法作为主键时保留字段，由 ValidateModel 报告
+func normalizeIDField(m *Model) {
+	for i, f := range m.Fields {
+		if !strings.EqualFold(f.Name, "ID") {
+			continue
+		}
+		if idType, ok := idTypesByFieldType[f.Type]; ok {
+			m.IDType = idType
+			m.Fields = append(m.Fields[:i:i], m.Fields[i+1:]...)
+		}
+		return
+	}
+}
+
+// 主键的Go类型
+func (m Model) IDGoType() string {
+	if m.IDType == "uuid" {
+		return "uuid.UUID"
+	}
+	return m.IDType
+}
+
+// 主键是否为整数，决定路由参数的解析方式
+func (m Model) NumericID() bool {
+	return m.IDType == "uint" || m.IDType == "int64"
+}
+
+// 解析模型级选项，例如:
+//
+//	@perm DELETE admin,editor
+func parseModelOption(m *Model, option string) {
+	parts := strings.Fields(option)
+	if len(parts) == 0 {
+		return
+	}
+
+	switch parts[0] {
+	case "id":
+		if len(parts) < 2 {
+			return
+		}
+		m.IDType = parts[1]
+	case "hook":
+		// @hook 名称 代码，同名钩子的多行代码依次追加到方法体
+		if len(parts) < 3 {
+			return
+		}
+		_, rest, _ := strings.Cut(strings.TrimSpace(option), " ")
+		name, body, _ := strings.Cut(strings.TrimSpace(rest), " ")
+		m.addHookLine(name, strings.TrimSpace(body))
+	case "history":
+		m.VersionHistory = true
+	case "paranoid":
+		m.ParanoidMode = true
+	case "slack":
+		m.SlackNotify = true
+	case "search":
+		m.SearchEnabled = true
+	case "scope":
+		// @scope 名称 条件
+		if len(parts) < 3 {
+			return
+		}
+		_, rest, _ := strings.Cut(strings.TrimSpace(option), " ")
+		name, condition, _ := strings.Cut(strings.TrimSpace(rest), " ")
+		m.Scopes = append(m.Scopes, ScopeDef{Name: name, Condition: strings.TrimSpace(condition)})
+	case "perm":
+		if len(parts) < 3 {
+			return
+		}
+		if m.Permissions == nil {
+			m.Permissions = make(map[string][]string)
+		}
+		m.Permissions[strings.ToUpper(parts[1])] = strings.Split(parts[2], ",")
+	}
+}
+
+func (m *Model) addHookLine(name, line string) {
+	for i := range m.Hooks {
+		if m.Hooks[i].Name == name {
+			m.Hooks[i].Body += "\n" + line
+			return
+		}
+	}
+	m.Hooks = append(m.Hooks, HookDef{Name: name, Body: line})
+}
+
+// 按名称查找钩子，不存在时返回 nil
+func (m Model) Hook(name string) *HookDef {
+	for i := range m.Hooks {
+		if m.Hooks[i].Name == name {
+			return &m.Hooks[i]
+		}
+	}
+	return nil
+}
+
+// 查找认证用户模型
+// session 模式: 第一个包含 password 字段的模型，登录字段优先使用 email，其次 username
+// magiclink 模式: 第一个包含 email 字段的模型
+func findAuthUser(models []Model, mode string) *AuthUser {
+	for _, m := range models {
+		user := AuthUser{Model: m}
+		for _, f := range m.Fields {
+			switch {
+			case strings.EqualFold(f.Name, "password"):
+				user.PasswordField = f
+			case strings.EqualFold(f.Name, "email"):
+				user.LoginField = f
+			}
+		}
+
+		if mode == "magiclink" {
+			if user.LoginField.Name != "" {
+				return &user
+			}
+			continue
+		}
+
+		if user.PasswordField.Name == "" {
+			continue
+		}
+		if user.LoginField.Name == "" {
+			for _, f := range m.Fields {
+				if strings.EqualFold(f.Name, "username") {
+					user.LoginField = f
+				}
+			}
+		}
+		if user.LoginField.Name == "" {
+			for _, f := range m.Fields {
+				if f.Type == "string" && f.Name != user.PasswordField.Name {
+					user.LoginField = f
+					break
+				}
+			}
+		}
+		return &user
+	}
+	return nil
+}
+
+// 为认证用户模型添加两步验证字段，标签与 parseModels 的规则一致；
+// 用户已定义同名字段（忽略大小写）时返回错误
+func addTOTPFields(data *TemplateData) error {
+	for i := range data.Models {
+		m := &data.Models[i]
+		if m.Name != data.AuthUser.Model.Name {
+			continue
+		}
+		for _, f := range m.Fields {
+			for name := range totpModelFields {
+				if strings.EqualFold(f.Name, name) {
+					return fmt.Errorf("模型 %s: 字段 %s 与两步验证字段 %s 冲突", m.Name, f.Name, name)
+				}
+			}
+		}
+		order := m.nextFieldOrder()
+		m.Fields = append(m.Fields,
+			ModelField{Name: "TOTPSecret", Type: "string", JsonTag: "-", GormTag: defaultGormTag(toSnakeCase("TOTPSecret"), "string"), Order: order},
+			ModelField{Name: "TOTPEnabled", Type: "bool", JsonTag: toJSONTag("TOTPEnabled", data.Project.JSONTagStyle), GormTag: defaultGormTag(toSnakeCase("TOTPEnabled"), "bool"), Order: order + 1},
+		)
+		data.AuthUser.Model = *m
+	}
+	return nil
+}
+
+// 为认证用户模型添加 RBAC 使用的 Role 字段，新用户默认为 user 角色。
+// 字段不出现在 JSON 中，用户不能通过 CRUD 接口修改自己的角色，需要直接更新数据库的 role 列
+func addRoleField(data *TemplateData) error {
+	for i := range data.Models {
+		m := &data.Models[i]
+		if m.Name != data.AuthUser.Model.Name {
+			continue
+		}
+		for _, f := range m.Fields {
+			if strings.EqualFold(f.Name, roleFieldName) {
+				return fmt.Errorf("模型 %s: 字段 %s 与 RBAC 角色字段 %s 冲突", m.Name, f.Name, roleFieldName)
+			}
+		}
+		m.Fields = append(m.Fields, ModelField{Name: roleFieldName, Type: "string", JsonTag: "-", GormTag: defaultGormTag(toSnakeCase(roleFieldName), "string"), Default: "user", Order: m.nextFieldOrder()})
+		data.AuthUser.Model = *m
+	}
+	return nil
+}
+
+// 生成进度回调，step 为刚完成的生成器名称，progress 取值 0-1
+type ProgressFunc func(step string, progress float64)
+
 // 生成项目结构
-func generateProjectStructure(baseDir string, data TemplateData) {
+func generateProjectStructure(fs FileSystem, data TemplateData) error {
+	return generateProjectStructureWithProgress(fs, data, nil)
+}
+
+// 生成项目结构，每个生成器完成后回调一次 onProgress
+func generateProjectStructureWithProgress(fs FileSystem, data TemplateData, onProgress ProgressFunc) error {
+	// 复制模型列表，钩子修改模型时不影响调用方
+	data.Models = append([]Model(nil), data.Models...)
+	if err := Hooks.RunPreGenerate(&data); err != nil {
+		return err
+	}
+
+	// 单仓库模式下项目位于 <ProjectName>/ 子目录，根目录是 Go 工作区
+	if data.Project.MonorepoEnabled {
+		if err := generateWorkspace(fs, []ProjectConfig{data.Project}, data.Delims); err != nil {
+			return err
+		}
+		fs = subdirFileSystem{fs: fs, dir: data.Project.ProjectName}
+	}
+
 	// 创建目录结构
 	dirs := []string{
 		"cmd",
@@ -265,70 +1307,139 @@ func generateProjectStructure(baseDir string, data TemplateData) {
 	}
 
 	for _, dir := range dirs {
-		os.MkdirAll(filepath.Join(baseDir, dir), 0755)
+		if err := fs.MkdirAll(dir); err != nil {
+			return err
+		}
 	}
 
-	// 定义要生成的文件模板
-	files := map[string]string{
-		"cmd/main.go":               mainTemplate,
-		"pkg/config/config.go":      configTemplate,
-		"pkg/database/database.go":  databaseTemplate,
-		"pkg/api/server.go":         serverTemplate,
-		"pkg/middlewares/logger.go": loggerMiddlewareTemplate,
-		".env":                      envTemplate,
-		"go.mod":                    goModTemplate,
-		"README.md":                 readmeTemplate,
-		"Dockerfile":                dockerfileTemplate,
-		".gitignore":                gitignoreTemplate,
+	// 先渲染到内存，生成后钩子处理完再统一写入
+	rendered := NewMemFileSystem()
+	active := selectGenerators(data.RegenerateOnly)
+	for i, g := range active {
+		if err := g.Generate(data, rendered); err != nil {
+			return err
+		}
+		if onProgress != nil {
+			onProgress(generatorName(g), float64(i+1)/float64(len(active)))
+		}
 	}
 
-	// 为每个模型生成文件
-	for _, model := range data.Models {
-		modelFiles := map[string]string{
-			"pkg/models/" + model.SnakeName + ".go":   modelTemplate,
-			"pkg/handlers/" + model.SnakeName + ".go": handlerTemplate,
-			"api/" + model.SnakeName + ".yaml":        apiSpecTemplate,
+	if err := Hooks.RunPostGenerate(rendered.Files); err != nil {
+		return err
+	}
+	if data.Project.GoSum && goSumAllowed {
+		if err := GenerateGoSum(rendered.Files); err != nil {
+			return err
 		}
+	}
 
-		for path, tmpl := range modelFiles {
-			generateFile(baseDir, path, tmpl, struct {
-				Project ProjectConfig
-				Model   Model
-			}{data.Project, model})
+	for _, path := range rendered.Paths() {
+		if err := fs.WriteFile(path, rendered.Files[path]); err != nil {
+			return fmt.Errorf("无法写入文件 %s: %w", path, err)
 		}
 	}
+	return nil
+}
 
-	// 生成其他文件
-	for path, tmpl := range files {
-		generateFile(baseDir, path, tmpl, data)
+// 生成单个文件，供自定义生成器使用，delims 通常传入 TemplateData.Delims
+func generateFile(fs FileSystem, filePath, tmplContent string, delims TemplateDelims, data interface{}) error {
+	content, err := renderTemplate(filePath, tmplContent, delims, data)
+	if err != nil {
+		return err
 	}
-}
 
-// 生成单个文件
-func generateFile(baseDir, filePath, tmplContent string, data interface{}) {
-	path := filepath.Join(baseDir, filePath)
-	os.MkdirAll(filepath.Dir(path), 0755)
+	if err := fs.WriteFile(filePath, content); err != nil {
+		return fmt.Errorf("无法写入文件 %s: %w", filePath, err)
+	}
+	return nil
+}
 
-	tmpl, err := template.New(filePath).Parse(tmplContent)
+// 渲染模板，不涉及文件读写
+func renderTemplate(name, tmplContent string, delims TemplateDelims, data interface{}) ([]byte, error) {
+	tmpl, err := template.New(name).Delims(delims.Left, delims.Right).Funcs(templateFuncs).Parse(tmplContent)
 	if err != nil {
-		log.Fatalf("无法解析模板 %s: %v", filePath, err)
+		return nil, fmt.Errorf("无法解析模板 %s: %w", name, err)
 	}
+	return executeTemplate(name, tmpl, data)
+}
 
+// 执行已解析的模板
+func executeTemplate(name string, tmpl *template.Template, data interface{}) ([]byte, error) {
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, data); err != nil {
-		log.Fatalf("无法执行模板 %s: %v", filePath, err)
+		return nil, fmt.Errorf("无法执行模板 %s: %w", name, err)
 	}
+	return buf.Bytes(), nil
+}
 
-	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
-		log.Fatalf("无法写入文件 %s: %v", path, err)
+// 字段对应的数据库列名
+func (f ModelField) ColumnName() string {
+	for _, part := range strings.Split(f.GormTag, ";") {
+		if strings.HasPrefix(part, "column:") {
+			return strings.TrimPrefix(part, "column:")
+		}
 	}
+	return toSnakeCase(f.Name)
+}
+
+// 模板辅助函数
+var templateFuncs = template.FuncMap{
+	"quoteList":              quoteList,
+	"pluralize":              pluralize,
+	"fakeValue":              fakeValue,
+	"mermaidType":            mermaidType,
+	"seedImports":            seedImports,
+	"sqlColumnType":          sqlColumnType,
+	"migrationColumnType":    migrationColumnType,
+	"migrationColumnOptions": migrationColumnOptions,
+	"migrationIndexes":       migrationIndexes,
+	"sanitizeIndexName":      sanitizeIndexName,
+	"indexTag":               indexTag,
+	"goDefault":              goDefault,
+	"benchValue":             benchValue,
+	"benchUsesType":          benchUsesType,
+	"shellQuote":             shellQuote,
+}
+
+// 辅助函数：将字符串列表渲染为Go字面量，如 "a", "b"
+func quoteList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = fmt.Sprintf("%q", item)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// 辅助函数：渲染为 POSIX shell 单引号字符串，值中的单引号先结束引号再转义
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// 辅助函数：规范化文件头注释，非注释行补上 "// "，并以换行结尾
+func normalizeFileHeader(header string) string {
+	header = strings.TrimSpace(strings.ReplaceAll(header, "\r\n", "\n"))
+	if header == "" {
+		return ""
+	}
+
+	lines := strings.Split(header, "\n")
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		if !strings.HasPrefix(line, "//") {
+			line = strings.TrimRight("// "+line, " ")
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n") + "\n"
 }
 
 // 辅助函数：转换为蛇形命名
 func toSnakeCase(s string) string {
 	var result strings.Builder
-	for i, c := range s {
-		if i > 0 && 'A' <= c && c <= 'Z' {
+	runes := []rune(s)
+	for i, c := range runes {
+		// 连续大写的缩写词 (SMSLog、UserID) 作为一个单词，在缩写后接小写字母的单词前分隔
+		if i > 0 && unicode.IsUpper(c) && (!unicode.IsUpper(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
 			result.WriteByte('_')
 		}
 		result.WriteRune(c)
@@ -336,6 +1447,52 @@ func toSnakeCase(s string) string {
 	return strings.ToLower(result.String())
 }
 
+const defaultJSONTagStyle = "snake_case"
+
+const (
+	defaultMySQLCharset   = "utf8mb4"
+	defaultMySQLCollation = "utf8mb4_unicode_ci"
+)
+
+// 辅助函数：按命名风格生成 JSON 标签，如 FirstName 对应 first_name、firstName 或 FirstName
+func toJSONTag(name, style string) string {
+	switch style {
+	case "camelCase":
+		return toLowerCamelCase(name)
+	case "PascalCase":
+		return name
+	default:
+		return toSnakeCase(name)
+	}
+}
+
+// 项目命名风格下的 JSON 标签，供模板中的内置字段使用
+func (p ProjectConfig) JSONTag(name string) string {
+	return toJSONTag(name, p.JSONTagStyle)
+}
+
+// 辅助函数：首字母小写，空字符串原样返回
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// 辅助函数：转为小驼峰，开头的缩写词整体小写 (URL 对应 url、APIKey 对应 apiKey)
+func toLowerCamelCase(s string) string {
+	runes := []rune(s)
+	n := 0
+	for n < len(runes) && unicode.IsUpper(runes[n]) {
+		n++
+	}
+	// 缩写词后接小写字母时，最后一个大写字母属于下一个单词
+	if n > 1 && n < len(runes) && unicode.IsLower(runes[n]) {
+		n--
+	}
+	return strings.ToLower(string(runes[:n])) + string(runes[n:])
+}
+
 // 辅助函数：复数化
 func pluralize(s string) string {
 	if strings.HasSuffix(s, "y") {
@@ -345,13 +1502,48 @@ func pluralize(s string) string {
 }
 
 // 模板定义
-const mainTemplate = `package main
+const mainTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package main
 
 import (
+{{- if or .Project.MetricsEnabled .Project.TracingBackend .Project.FCMEnabled}}
+	"context"
+{{- end}}
 	"log"
+{{- if .Project.ConsulEnabled}}
+	"os"
+	"os/signal"
+	"syscall"
+{{- end}}
+{{- if .Project.SentryEnabled}}
+	"time"
+
+	"github.com/getsentry/sentry-go"
+{{- end}}
 	"{{.Project.ModuleName}}/pkg/api"
 	"{{.Project.ModuleName}}/pkg/config"
 	"{{.Project.ModuleName}}/pkg/database"
+{{- if .Project.ConsulEnabled}}
+	"{{.Project.ModuleName}}/pkg/discovery"
+{{- end}}
+{{- if .Project.GRPCEnabled}}
+	"{{.Project.ModuleName}}/pkg/grpc"
+{{- end}}
+{{- if .Project.LokiEnabled}}
+	"{{.Project.ModuleName}}/pkg/logger"
+{{- end}}
+{{- if .Project.SentryEnabled}}
+	"{{.Project.ModuleName}}/pkg/monitoring"
+{{- end}}
+{{- if or .Project.EmailEnabled .Project.SlackEnabled .Project.FCMEnabled .Project.TwilioEnabled .Project.NotificationCenter}}
+	"{{.Project.ModuleName}}/pkg/notifications"
+{{- end}}
+{{- if .Project.SearchBackendEnabled}}
+	"{{.Project.ModuleName}}/pkg/search"
+{{- end}}
+{{- if or .Project.DatadogEnabled .Project.MetricsEnabled .Project.TracingBackend}}
+	"{{.Project.ModuleName}}/pkg/tracing"
+{{- end}}
 )
 
 func main() {
@@ -360,37 +1552,298 @@ func main() {
 	if err != nil {
 		log.Fatalf("Error loading config: %v", err)
 	}
+{{- if .Project.LokiEnabled}}
+
+	// 初始化日志并推送到 Loki
+	stopLogger, err := logger.Init(cfg)
+	if err != nil {
+		log.Fatalf("Error initializing logger: %v", err)
+	}
+	defer stopLogger()
+{{- end}}
+{{- if or .Project.EmailEnabled .Project.SlackEnabled .Project.FCMEnabled .Project.TwilioEnabled .Project.NotificationCenter}}
+
+	// 通知使用的 SendGrid 账号、Slack webhook、Firebase 服务账号和 Twilio 账号
+{{- if .Project.EmailEnabled}}
+	notifications.Configure(cfg.SendGridAPIKey, cfg.FromEmail)
+{{- end}}
+{{- if .Project.SlackEnabled}}
+	notifications.ConfigureSlack(cfg.SlackWebhookURL)
+{{- end}}
+{{- if .Project.FCMEnabled}}
+	if err := notifications.ConfigureFCM(context.Background(), cfg.FirebaseCredentialsFile); err != nil {
+		log.Fatalf("Error initializing FCM: %v", err)
+	}
+{{- end}}
+{{- if .Project.TwilioEnabled}}
+	notifications.ConfigureTwilio(cfg.TwilioAccountSID, cfg.TwilioAuthToken, cfg.TwilioFromNumber)
+{{- end}}
+{{- if .Project.NotificationCenter}}
+	// 通知中心的投递渠道，需要在 Slack、FCM 配置之后注册
+	notifications.ConfigurePublishers(cfg.NotificationWebhookURL)
+{{- end}}
+{{- end}}
+{{- if .Project.ElasticsearchEnabled}}
+
+	// 连接 Elasticsearch，@search 模型写入后同步索引
+	if err := search.Configure(cfg.ElasticsearchURL); err != nil {
+		log.Fatalf("Error initializing Elasticsearch: %v", err)
+	}
+{{- end}}
+{{- if .Project.AlgoliaEnabled}}
+
+	// 连接 Algolia，@search 模型写入后同步索引
+	search.Configure(cfg.AlgoliaAppID, cfg.AlgoliaAPIKey, cfg.AlgoliaIndexPrefix)
+{{- end}}
+{{- if .Project.DatadogEnabled}}
+
+	// 启动 Datadog APM 追踪
+	stopTracer := tracing.StartDatadog(cfg)
+	defer stopTracer()
+{{- end}}
+{{- if .Project.SentryEnabled}}
+
+	// 初始化 Sentry 错误追踪
+	if err := monitoring.InitSentry(cfg); err != nil {
+		log.Fatalf("Error initializing Sentry: %v", err)
+	}
+	defer sentry.Flush(2 * time.Second)
+{{- end}}
+{{- if .Project.MetricsEnabled}}
+
+	// 初始化 OpenTelemetry 指标
+	shutdownMetrics, err := tracing.InitMetrics(context.Background(), cfg)
+	if err != nil {
+		log.Fatalf("Error initializing metrics: %v", err)
+	}
+	defer shutdownMetrics(context.Background())
+{{- end}}
+{{- if .Project.TracingBackend}}
+
+	// 初始化 OpenTelemetry 追踪 ({{.Project.TracingBackend}})
+	shutdownTracer, err := tracing.InitTracer(context.Background(), cfg)
+	if err != nil {
+		log.Fatalf("Error initializing tracer: %v", err)
+	}
+	defer shutdownTracer(context.Background())
+{{- end}}
 
 	// 初始化数据库
 	db, err := database.InitDB(cfg)
 	if err != nil {
 		log.Fatalf("Error initializing database: %v", err)
 	}
+{{- if .Project.CQRS}}
+	readDB, err := database.InitReadDB(cfg, db)
+	if err != nil {
+		log.Fatalf("Error initializing read database: %v", err)
+	}
+{{- end}}
 
 	// 创建API服务器
-	server := api.NewServer(cfg, db)
+	server := api.NewServer(cfg, db{{if .Project.CQRS}}, readDB{{end}})
+{{- if .Project.GRPCEnabled}}
+
+	// 在独立端口启动 gRPC 健康检查服务
+	go func() {
+		if err := grpc.Serve(cfg.GRPCPort, db); err != nil {
+			log.Fatalf("Error starting gRPC server: %v", err)
+		}
+	}()
+{{- end}}
+{{- if .Project.ConsulEnabled}}
+
+	// 注册到 Consul，收到 SIGINT、SIGTERM 或服务器退出时注销
+	registration, err := discovery.Register(cfg)
+	if err != nil {
+		log.Fatalf("Error registering with Consul: %v", err)
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.Run()
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case err = <-serverErr:
+	case sig := <-quit:
+		log.Printf("Received %s, shutting down", sig)
+	}
+
+	if deregisterErr := registration.Deregister(); deregisterErr != nil {
+		log.Printf("Error deregistering from Consul: %v", deregisterErr)
+	}
+	if err != nil {
+		log.Fatalf("Error starting server: %v", err)
+	}
+{{- else}}
 
 	// 启动服务器
 	if err := server.Run(); err != nil {
 		log.Fatalf("Error starting server: %v", err)
 	}
+{{- end}}
 }
 `
 
-const configTemplate = `package config
+const configTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package config
 
 import (
+{{- if ne .Project.AuthMode "none"}}
+	"fmt"
+
+{{- end}}
 	"github.com/spf13/viper"
 )
 
 type Config struct {
 	AppPort  string ` + "`mapstructure:\"APP_PORT\"`" + `
+	Language string ` + "`mapstructure:\"APP_LANGUAGE\"`" + `
 	DBHost   string ` + "`mapstructure:\"DB_HOST\"`" + `
 	DBPort   string ` + "`mapstructure:\"DB_PORT\"`" + `
 	DBUser   string ` + "`mapstructure:\"DB_USER\"`" + `
 	DBPass   string ` + "`mapstructure:\"DB_PASSWORD\"`" + `
 	DBName   string ` + "`mapstructure:\"DB_NAME\"`" + `
-	DBSSL    string ` + "`mapstructure:\"DB_SSL\"`" + `
+{{- if .Project.CQRS}}
+	// 只读副本的地址，端口、用户名、密码和库名与主库相同；为空时读请求也使用主库
+	DBReadHost string ` + "`mapstructure:\"DB_READ_HOST\"`" + `
+{{- end}}
+{{- if eq .Project.DBDriver "postgres"}}
+	// PostgreSQL 的 sslmode: disable、require、verify-ca 或 verify-full
+	DBSSLMode string ` + "`mapstructure:\"DB_SSL_MODE\"`" + `
+{{- else}}
+	// MySQL 是否使用 TLS 连接，DBSSLCA 为校验服务器证书的 CA 文件，为空时使用系统根证书
+	DBSSL       bool   ` + "`mapstructure:\"DB_SSL\"`" + `
+	DBSSLCA     string ` + "`mapstructure:\"DB_SSL_CA\"`" + `
+	DBCharset   string ` + "`mapstructure:\"DB_CHARSET\"`" + `
+	DBCollation string ` + "`mapstructure:\"DB_COLLATION\"`" + `
+{{- end}}
+	// 请求日志中需要遮盖的字段名，逗号分隔
+	LogSensitiveFields string ` + "`mapstructure:\"LOG_SENSITIVE_FIELDS\"`" + `
+	// 可信的反向代理地址，逗号分隔的 CIDR 或IP
+	TrustedProxies string ` + "`mapstructure:\"TRUSTED_PROXIES\"`" + `
+{{- if .Project.GRPCEnabled}}
+	GRPCPort string ` + "`mapstructure:\"GRPC_PORT\"`" + `
+{{- end}}
+{{- if gt .Project.MaxRequestBodyMB 0}}
+	MaxRequestBodyMB int ` + "`mapstructure:\"MAX_REQUEST_BODY_MB\"`" + `
+{{- end}}
+{{- if gt .Project.RequestTimeoutSec 0}}
+	RequestTimeoutSec int ` + "`mapstructure:\"REQUEST_TIMEOUT_SEC\"`" + `
+{{- end}}
+{{- if gt .Project.RateLimitRPS 0}}
+	RateLimitRPS   float64 ` + "`mapstructure:\"RATE_LIMIT_RPS\"`" + `
+	RateLimitBurst int     ` + "`mapstructure:\"RATE_LIMIT_BURST\"`" + `
+{{- if .Project.RateLimitByAPIKey}}
+	RateLimitAPIKeys string ` + "`mapstructure:\"RATE_LIMIT_API_KEYS\"`" + `
+{{- end}}
+{{- end}}
+{{- if .Project.SecurityHeaders}}
+	ContentSecurityPolicy string ` + "`mapstructure:\"CONTENT_SECURITY_POLICY\"`" + `
+{{- end}}
+{{- if .Project.IPFilterEnabled}}
+	IPWhitelist string ` + "`mapstructure:\"IP_WHITELIST\"`" + `
+	IPBlacklist string ` + "`mapstructure:\"IP_BLACKLIST\"`" + `
+{{- end}}
+{{- if .Project.SlackEnabled}}
+	SlackWebhookURL string ` + "`mapstructure:\"SLACK_WEBHOOK_URL\"`" + `
+{{- end}}
+{{- if .Project.FCMEnabled}}
+	FirebaseCredentialsFile string ` + "`mapstructure:\"FIREBASE_CREDENTIALS_FILE\"`" + `
+{{- end}}
+{{- if .Project.TwilioEnabled}}
+	TwilioAccountSID string ` + "`mapstructure:\"TWILIO_ACCOUNT_SID\"`" + `
+	TwilioAuthToken  string ` + "`mapstructure:\"TWILIO_AUTH_TOKEN\"`" + `
+	TwilioFromNumber string ` + "`mapstructure:\"TWILIO_FROM_NUMBER\"`" + `
+{{- end}}
+{{- if .Project.NotificationCenter}}
+	NotificationWebhookURL string ` + "`mapstructure:\"NOTIFICATION_WEBHOOK_URL\"`" + `
+{{- end}}
+{{- if .Project.StripeEnabled}}
+	StripeSecretKey     string ` + "`mapstructure:\"STRIPE_SECRET_KEY\"`" + `
+	StripeWebhookSecret string ` + "`mapstructure:\"STRIPE_WEBHOOK_SECRET\"`" + `
+{{- end}}
+{{- if .Project.AdminPanelEnabled}}
+	AdminUser string ` + "`mapstructure:\"ADMIN_USER\"`" + `
+	AdminPass string ` + "`mapstructure:\"ADMIN_PASS\"`" + `
+{{- end}}
+{{- if .Project.AutoCertEnabled}}
+	LetsEncryptDomain string ` + "`mapstructure:\"LETSENCRYPT_DOMAIN\"`" + `
+	LetsEncryptEmail  string ` + "`mapstructure:\"LETSENCRYPT_EMAIL\"`" + `
+{{- else if .Project.TLSEnabled}}
+	TLSCertFile      string ` + "`mapstructure:\"TLS_CERT_FILE\"`" + `
+	TLSKeyFile       string ` + "`mapstructure:\"TLS_KEY_FILE\"`" + `
+	HTTPRedirectPort string ` + "`mapstructure:\"HTTP_REDIRECT_PORT\"`" + `
+{{- end}}
+{{- if eq .Project.AuthMode "magiclink"}}
+	JWTSecret string ` + "`mapstructure:\"JWT_SECRET\"`" + `
+{{- end}}
+{{- if eq .Project.AuthMode "magiclink"}}
+	AppBaseURL     string ` + "`mapstructure:\"APP_BASE_URL\"`" + `
+	EmailProvider  string ` + "`mapstructure:\"EMAIL_PROVIDER\"`" + `
+	FromEmail      string ` + "`mapstructure:\"FROM_EMAIL\"`" + `
+	SMTPHost       string ` + "`mapstructure:\"SMTP_HOST\"`" + `
+	SMTPPort       string ` + "`mapstructure:\"SMTP_PORT\"`" + `
+	SMTPUser       string ` + "`mapstructure:\"SMTP_USER\"`" + `
+	SMTPPassword   string ` + "`mapstructure:\"SMTP_PASSWORD\"`" + `
+	SendGridAPIKey string ` + "`mapstructure:\"SENDGRID_API_KEY\"`" + `
+{{- else if .Project.EmailEnabled}}
+	FromEmail      string ` + "`mapstructure:\"FROM_EMAIL\"`" + `
+	SendGridAPIKey string ` + "`mapstructure:\"SENDGRID_API_KEY\"`" + `
+{{- end}}
+{{- if eq .Project.AuthMode "session"}}
+	SessionSecret   string ` + "`mapstructure:\"SESSION_SECRET\"`" + `
+	SessionStore    string ` + "`mapstructure:\"SESSION_STORE\"`" + `
+	SessionTTLHours int    ` + "`mapstructure:\"SESSION_TTL_HOURS\"`" + `
+{{- end}}
+{{- if or (eq .Project.AuthMode "session") .Project.QuotaEnabled .Project.ChatEnabled}}
+	RedisAddr string ` + "`mapstructure:\"REDIS_ADDR\"`" + `
+{{- end}}
+{{- if .Project.QuotaEnabled}}
+	UserMonthlyQuota int ` + "`mapstructure:\"USER_MONTHLY_QUOTA\"`" + `
+{{- end}}
+{{- if .Project.DatadogEnabled}}
+	DDAgentHost   string ` + "`mapstructure:\"DD_AGENT_HOST\"`" + `
+	DDServiceName string ` + "`mapstructure:\"DD_SERVICE_NAME\"`" + `
+	DDEnv         string ` + "`mapstructure:\"DD_ENV\"`" + `
+{{- end}}
+{{- if .Project.SentryEnabled}}
+	SentryDSN         string ` + "`mapstructure:\"SENTRY_DSN\"`" + `
+	SentryEnvironment string ` + "`mapstructure:\"SENTRY_ENVIRONMENT\"`" + `
+{{- end}}
+{{- if or .Project.MetricsEnabled (eq .Project.TracingBackend "otlp")}}
+	OTelEndpoint string ` + "`mapstructure:\"OTEL_EXPORTER_OTLP_ENDPOINT\"`" + `
+{{- end}}
+{{- if .Project.TracingBackend}}
+	OTelServiceName string ` + "`mapstructure:\"OTEL_SERVICE_NAME\"`" + `
+{{- end}}
+{{- if eq .Project.TracingBackend "jaeger"}}
+	JaegerEndpoint string ` + "`mapstructure:\"JAEGER_ENDPOINT\"`" + `
+{{- end}}
+{{- if eq .Project.TracingBackend "zipkin"}}
+	ZipkinEndpoint string ` + "`mapstructure:\"ZIPKIN_ENDPOINT\"`" + `
+{{- end}}
+{{- if .Project.LokiEnabled}}
+	AppEnv       string ` + "`mapstructure:\"APP_ENV\"`" + `
+	LokiURL      string ` + "`mapstructure:\"LOKI_URL\"`" + `
+	LokiTenantID string ` + "`mapstructure:\"LOKI_TENANT_ID\"`" + `
+{{- end}}
+{{- if .Project.ConsulEnabled}}
+	ConsulAddress string ` + "`mapstructure:\"CONSUL_ADDRESS\"`" + `
+	// 注册到 Consul 时附带的标签，逗号分隔
+	ServiceTags string ` + "`mapstructure:\"SERVICE_TAGS\"`" + `
+{{- end}}
+{{- if .Project.ElasticsearchEnabled}}
+	ElasticsearchURL string ` + "`mapstructure:\"ELASTICSEARCH_URL\"`" + `
+{{- end}}
+{{- if .Project.AlgoliaEnabled}}
+	AlgoliaAppID       string ` + "`mapstructure:\"ALGOLIA_APP_ID\"`" + `
+	AlgoliaAPIKey      string ` + "`mapstructure:\"ALGOLIA_API_KEY\"`" + `
+	AlgoliaIndexPrefix string ` + "`mapstructure:\"ALGOLIA_INDEX_PREFIX\"`" + `
+{{- end}}
 }
 
 func LoadConfig() (*Config, error) {
@@ -405,117 +1858,521 @@ func LoadConfig() (*Config, error) {
 	if err := viper.Unmarshal(&cfg); err != nil {
 		return nil, err
 	}
+{{- if .Project.ConsulEnabled}}
+
+	// 未设置的数据库配置从 Consul KV 读取
+	if err := loadDBConfigFromConsul(&cfg); err != nil {
+		return nil, err
+	}
+{{- end}}
+{{- if eq .Project.AuthMode "session"}}
+
+	if IsPlaceholderSecret(cfg.SessionSecret) {
+		return nil, fmt.Errorf("SESSION_SECRET must be set to a random value, e.g. openssl rand -hex 32")
+	}
+{{- else if eq .Project.AuthMode "magiclink"}}
+
+	if IsPlaceholderSecret(cfg.JWTSecret) {
+		return nil, fmt.Errorf("JWT_SECRET must be set to a random value, e.g. openssl rand -hex 32")
+	}
+{{- end}}
 
 	return &cfg, nil
 }
+{{- if or (ne .Project.AuthMode "none") .Project.AdminPanelEnabled}}
+
+// IsPlaceholderSecret 判断密钥是否为空或仍是示例中的 change_me，此类密钥不能用于签名或认证
+func IsPlaceholderSecret(secret string) bool {
+	return secret == "" || secret == "change_me"
+}
+{{- end}}
 `
 
-const databaseTemplate = `package database
+const databaseTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package database
 
 import (
+{{- if ne .Project.DBDriver "postgres"}}
+	"crypto/tls"
+	"crypto/x509"
+{{- end}}
 	"fmt"
 	"log"
-
+{{- if ne .Project.DBDriver "postgres"}}
+	"os"
+{{- end}}
+
+{{- if ne .Project.DBDriver "postgres"}}
+	mysqldriver "github.com/go-sql-driver/mysql"
+{{- end}}
+{{- if .Project.DatadogEnabled}}
+	gormtrace "gopkg.in/DataDog/dd-trace-go.v1/contrib/gorm.io/gorm.v1"
+{{- end}}
+{{- if eq .Project.DBDriver "postgres"}}
+	"gorm.io/driver/postgres"
+{{- else}}
 	"gorm.io/driver/mysql"
+{{- end}}
 	"gorm.io/gorm"
 	"{{.Project.ModuleName}}/pkg/config"
 )
 
 func InitDB(cfg *config.Config) (*gorm.DB, error) {
+{{- if eq .Project.DBDriver "postgres"}}
+	// PostgreSQL 连接字符串格式: host=... port=... user=... password=... dbname=... sslmode=...
+	sslMode := cfg.DBSSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		cfg.DBHost,
+		cfg.DBPort,
+		cfg.DBUser,
+		cfg.DBPass,
+		cfg.DBName,
+		sslMode,
+	)
+	dialector := postgres.Open(dsn)
+{{- else}}
 	// MySQL 连接字符串格式:
 	// [username[:password]@][protocol[(address)]]/dbname[?param1=value1&...&paramN=valueN]
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=%s&collation=%s&parseTime=True&loc=Local",
 		cfg.DBUser,
 		cfg.DBPass,
 		cfg.DBHost,
 		cfg.DBPort,
 		cfg.DBName,
+		cfg.DBCharset,
+		cfg.DBCollation,
 	)
-
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	if cfg.DBSSL {
+		if err := registerTLSConfig(cfg); err != nil {
+			return nil, err
+		}
+		dsn += "&tls=custom"
+	}
+	dialector := mysql.Open(dsn)
+{{- end}}
+
+{{- if .Project.DatadogEnabled}}
+	db, err := gormtrace.Open(dialector, &gorm.Config{}, gormtrace.WithServiceName(cfg.DDServiceName+"-{{.Project.DBDriver}}"))
+{{- else}}
+	db, err := gorm.Open(dialector, &gorm.Config{})
+{{- end}}
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	log.Println("MySQL database connection established")
+	log.Println("{{if eq .Project.DBDriver "postgres"}}PostgreSQL{{else}}MySQL{{end}} database connection established")
 	return db, nil
 }
+{{- if .Project.CQRS}}
+
+// InitReadDB 连接 DB_READ_HOST 上的只读副本，未设置时返回主库 primary
+func InitReadDB(cfg *config.Config, primary *gorm.DB) (*gorm.DB, error) {
+	if cfg.DBReadHost == "" {
+		return primary, nil
+	}
+	replica := *cfg
+	replica.DBHost = cfg.DBReadHost
+	return InitDB(&replica)
+}
+{{- end}}
+{{- if ne .Project.DBDriver "postgres"}}
+
+// 注册 DSN 中 tls=custom 使用的 TLS 配置，按 DB_HOST 校验服务器证书
+func registerTLSConfig(cfg *config.Config) error {
+	tlsConfig := &tls.Config{
+		ServerName: cfg.DBHost,
+		MinVersion: tls.VersionTLS12,
+	}
+	if cfg.DBSSLCA != "" {
+		pem, err := os.ReadFile(cfg.DBSSLCA)
+		if err != nil {
+			return fmt.Errorf("failed to read DB_SSL_CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in %s", cfg.DBSSLCA)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return mysqldriver.RegisterTLSConfig("custom", tlsConfig)
+}
+{{- end}}
 `
 
-const serverTemplate = `package api
+const serverTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package api
 
 import (
+	"log"
+{{- if and .Project.TLSEnabled (not .Project.AutoCertEnabled)}}
+	"net"
+{{- end}}
+{{- if or .Project.TLSEnabled .Project.HTTP2Push}}
+	"net/http"
+{{- end}}
+	"strings"
+{{- if gt .Project.RequestTimeoutSec 0}}
+	"time"
+{{- end}}
 	"github.com/gin-gonic/gin"
+{{- if .Project.AutoCertEnabled}}
+	"golang.org/x/crypto/acme/autocert"
+{{- end}}
+{{- if .Project.HTTP2Push}}
+	"golang.org/x/net/http2"
+{{- if not .Project.TLSEnabled}}
+	"golang.org/x/net/http2/h2c"
+{{- end}}
+{{- end}}
 	"gorm.io/gorm"
 
+{{- if eq .Project.AuthMode "magiclink"}}
+	"{{.Project.ModuleName}}/pkg/auth"
+{{- end}}
+{{- if .Project.ChatEnabled}}
+	"{{.Project.ModuleName}}/pkg/chat"
+{{- end}}
 	"{{.Project.ModuleName}}/pkg/config"
+{{- if .Project.SwaggerUI}}
+	"{{.Project.ModuleName}}/docs"
+{{- end}}
+{{- if eq .Project.AuthMode "magiclink"}}
+	"{{.Project.ModuleName}}/pkg/email"
+{{- end}}
 	"{{.Project.ModuleName}}/pkg/handlers"
 	"{{.Project.ModuleName}}/pkg/middlewares"
+{{- if .Project.SentryEnabled}}
+	"{{.Project.ModuleName}}/pkg/monitoring"
+{{- end}}
+{{- if .Project.StripeEnabled}}
+	"{{.Project.ModuleName}}/pkg/payments"
+{{- end}}
+{{- if .Project.QuotaEnabled}}
+	"{{.Project.ModuleName}}/pkg/quota"
+{{- end}}
+{{- if or .Project.DatadogEnabled .Project.TracingBackend}}
+	"{{.Project.ModuleName}}/pkg/tracing"
+{{- end}}
 )
 
 type Server struct {
 	router *gin.Engine
 	cfg    *config.Config
 	db     *gorm.DB
+{{- if .Project.CQRS}}
+	// 只读库，未配置 DB_READ_HOST 时与 db 相同
+	readDB *gorm.DB
+{{- end}}
 }
 
-func NewServer(cfg *config.Config, db *gorm.DB) *Server {
+{{if .Project.CQRS}}func NewServer(cfg *config.Config, db, readDB *gorm.DB) *Server {
+	server := &Server{
+		cfg:    cfg,
+		db:     db,
+		readDB: readDB,
+	}{{else}}func NewServer(cfg *config.Config, db *gorm.DB) *Server {
 	server := &Server{
 		cfg: cfg,
 		db:  db,
-	}
+	}{{end}}
 	server.setupRouter()
 	return server
 }
 
 func (s *Server) setupRouter() {
 	r := gin.Default()
+	// gin 默认信任任意代理转发的 X-Forwarded-For，客户端可以伪造 ClientIP 绕过IP过滤和限流；
+	// 只信任 TRUSTED_PROXIES 中的代理，为空时 ClientIP 是连接的对端地址
+	if err := r.SetTrustedProxies(strings.Fields(strings.ReplaceAll(s.cfg.TrustedProxies, ",", " "))); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
 
 	// 中间件
-	r.Use(middlewares.LoggerMiddleware())
+{{- if .Project.DatadogEnabled}}
+	r.Use(tracing.DatadogMiddleware(s.cfg.DDServiceName))
+{{- end}}
+{{- if .Project.TracingBackend}}
+	r.Use(tracing.Middleware(s.cfg.OTelServiceName))
+{{- end}}
+{{- if .Project.SentryEnabled}}
+	r.Use(monitoring.SentryMiddleware()...)
+{{- end}}
+	r.Use(middlewares.LoggerMiddleware(s.cfg.LogSensitiveFields))
+{{- if .Project.AdminPanelEnabled}}
+	r.Use(handlers.CountRequests())
+{{- end}}
+{{- if .Project.IPFilterEnabled}}
+	ipFilter, err := middlewares.IPFilter(s.cfg.IPWhitelist, s.cfg.IPBlacklist)
+	if err != nil {
+		log.Fatalf("Error creating IP filter: %v", err)
+	}
+	r.Use(ipFilter)
+{{- end}}
+{{- if gt .Project.RateLimitRPS 0}}
+	r.Use(middlewares.RateLimit(s.cfg.RateLimitRPS, s.cfg.RateLimitBurst{{if .Project.RateLimitByAPIKey}}, s.cfg.RateLimitAPIKeys{{end}}))
+{{- end}}
+{{- if gt .Project.MaxRequestBodyMB 0}}
+	r.Use(middlewares.BodyLimit(int64(s.cfg.MaxRequestBodyMB) << 20))
+{{- end}}
+{{- if gt .Project.RequestTimeoutSec 0}}
+	r.Use(middlewares.Timeout(time.Duration(s.cfg.RequestTimeoutSec) * time.Second))
+{{- end}}
+{{- if .Project.SecurityHeaders}}
+	r.Use(middlewares.SecurityHeaders(s.cfg.ContentSecurityPolicy))
+{{- end}}
+{{- if .Project.IstioEnabled}}
+	r.Use(middlewares.ClientCert())
+{{- end}}
+{{- if .Project.HTTP2Push}}
+	r.Use(middlewares.Prefetch(map[string][]string{
+{{- range .PrefetchLinks}}
+		"/api/v1/{{.Model.PluralName}}": { {{- range $i, $m := .Related}}{{if $i}}, {{end}}"/api/v1/{{$m.PluralName}}"{{end -}} },
+{{- end}}
+	}))
+{{- end}}
+	handlers.SetLanguage(s.cfg.Language)
+{{- if eq .Project.AuthMode "session"}}
+	sessionMiddleware, err := middlewares.SessionMiddleware(s.cfg)
+	if err != nil {
+		log.Fatalf("Error creating session store: %v", err)
+	}
+	r.Use(sessionMiddleware)
+{{- end}}
 
 	// 健康检查
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
+{{- if .Project.SwaggerUI}}
+
+	// API 文档
+	r.StaticFS("/docs", docs.SwaggerUI())
+{{- end}}
+{{- if .Project.AdminPanelEnabled}}
+
+	// 管理接口
+	if s.cfg.AdminUser == "" {
+		log.Println("ADMIN_USER is empty, admin routes are disabled")
+	} else if config.IsPlaceholderSecret(s.cfg.AdminPass) {
+		log.Println("ADMIN_PASS is empty or change_me, admin routes are disabled")
+	} else {
+		admin := r.Group("/admin", gin.BasicAuth(gin.Accounts{s.cfg.AdminUser: s.cfg.AdminPass}))
+		handlers.RegisterAdminRoutes(admin, s.db)
+{{- if not .Project.RBAC}}
+{{- range .Models}}{{if .ParanoidMode}}
+		handlers.Register{{.Name}}AdminRoutes(admin, s.db)
+{{- end}}{{end}}
+{{- end}}
+	}
+{{- end}}
+
+{{- if eq .Project.AuthMode "session"}}
+
+	// 认证路由
+	handlers.RegisterAuthRoutes(r.Group("/auth"), s.db)
+{{- else if eq .Project.AuthMode "magiclink"}}
+
+	// 认证路由
+	magicLink := auth.NewMagicLink(s.cfg.JWTSecret, s.cfg.AppBaseURL)
+	handlers.RegisterAuthRoutes(r.Group("/auth"), s.db, magicLink, email.NewSender(s.cfg))
+{{- end}}
 
 	// API路由
 	api := r.Group("/api/v1")
+{{- if eq .Project.AuthMode "session"}}
+	api.Use(middlewares.RequireSession())
+{{- else if eq .Project.AuthMode "magiclink"}}
+	api.Use(middlewares.RequireAccessToken(magicLink))
+{{- end}}
+{{- if .Project.QuotaEnabled}}
+	api.Use(quota.New(s.cfg.RedisAddr, s.cfg.UserMonthlyQuota).Middleware())
+{{- end}}
+{{- if .Project.RBAC}}
+	userRole := middlewares.UserRole(s.db)
+{{- end}}
 	{{range .Models}}
-	handlers.Register{{.Name}}Routes(api, s.db)
+	{{- if and $.Project.RBAC .Permissions}}
+	handlers.Register{{.Name}}Routes(api.Group("", middlewares.RequireRoleByMethod(userRole, map[string][]string{
+		{{- range $method, $roles := .Permissions}}
+		"{{$method}}": { {{- quoteList $roles -}} },
+		{{- end}}
+	})), s.db{{if $.Project.CQRS}}, s.readDB{{end}})
+	{{- else}}
+	handlers.Register{{.Name}}Routes(api, s.db{{if $.Project.CQRS}}, s.readDB{{end}})
+	{{- end}}
+	{{- /* 没有 RBAC 时，启用了管理接口则挂在 Basic 认证的 /admin 下，否则只经过 api 组的登录认证 */}}
+	{{- if and .ParanoidMode (or $.Project.RBAC (not $.Project.AdminPanelEnabled))}}
+	handlers.Register{{.Name}}AdminRoutes(api.Group("/admin"{{if $.Project.RBAC}}, middlewares.RequireRole(userRole, "admin"){{end}}), s.db)
+	{{- end}}
 	{{end}}
+{{- if .Project.StripeEnabled}}
+	// 支付路由，webhook 不经过认证
+	stripe := payments.NewStripe(s.cfg.StripeSecretKey, s.cfg.StripeWebhookSecret)
+{{- if eq .Project.AuthMode "session"}}
+	handlers.RegisterPaymentRoutes(r.Group("/payments"), s.db, stripe, middlewares.RequireSession())
+{{- else if eq .Project.AuthMode "magiclink"}}
+	handlers.RegisterPaymentRoutes(r.Group("/payments"), s.db, stripe, middlewares.RequireAccessToken(magicLink))
+{{- else}}
+	handlers.RegisterPaymentRoutes(r.Group("/payments"), s.db, stripe)
+{{- end}}
+{{- end}}
+{{- if .Project.FCMEnabled}}
+
+	// 推送通知路由
+	handlers.RegisterNotificationSendRoutes(r.Group("/notifications"{{if eq .Project.AuthMode "session"}}, middlewares.RequireSession(){{else if eq .Project.AuthMode "magiclink"}}, middlewares.RequireAccessToken(magicLink){{end}}), s.db)
+{{- end}}
+{{- if .Project.TwilioEnabled}}
+
+	// 短信路由
+	handlers.RegisterSMSRoutes(r.Group("/notifications"{{if eq .Project.AuthMode "session"}}, middlewares.RequireSession(){{else if eq .Project.AuthMode "magiclink"}}, middlewares.RequireAccessToken(magicLink){{end}}), s.db)
+{{- end}}
+{{- if .Project.NotificationCenter}}
+
+	// 通知中心路由
+	handlers.RegisterNotificationCenterRoutes(r.Group("/notifications"{{if eq .Project.AuthMode "session"}}, middlewares.RequireSession(){{else if eq .Project.AuthMode "magiclink"}}, middlewares.RequireAccessToken(magicLink){{end}}), s.db)
+{{- end}}
+{{- if .Project.ChatEnabled}}
+
+	// 聊天室路由
+	chatHub := chat.NewHub(s.cfg.RedisAddr, s.db)
+	go chatHub.Run()
+	handlers.RegisterChatRoutes(r.Group("/ws/chat"{{if eq .Project.AuthMode "session"}}, middlewares.RequireSession(){{else if eq .Project.AuthMode "magiclink"}}, middlewares.RequireAccessToken(magicLink){{end}}), chatHub)
+{{- end}}
 
 	s.router = r
 }
 
 func (s *Server) Run() error {
+{{- if .Project.AutoCertEnabled}}
+	manager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(s.cfg.LetsEncryptDomain),
+		Cache:      autocert.DirCache("./certs"),
+		Email:      s.cfg.LetsEncryptEmail,
+	}
+
+	// 80 端口处理 HTTP-01 验证，其余请求重定向到 HTTPS
+	go func() {
+		if err := http.ListenAndServe(":80", manager.HTTPHandler(nil)); err != nil {
+			log.Printf("ACME challenge server stopped: %v", err)
+		}
+	}()
+
+	srv := &http.Server{
+		Addr:      ":443",
+		Handler:   s.router,
+		TLSConfig: manager.TLSConfig(),
+	}
+{{- template "configureHTTP2" .}}
+	return srv.ListenAndServeTLS("", "")
+{{- else if .Project.TLSEnabled}}
+	if s.cfg.HTTPRedirectPort != "" {
+		go func() {
+			if err := http.ListenAndServe(":"+s.cfg.HTTPRedirectPort, redirectToHTTPS(s.cfg.AppPort)); err != nil {
+				log.Printf("HTTP redirect server stopped: %v", err)
+			}
+		}()
+	}
+
+	srv := &http.Server{
+		Addr:    ":" + s.cfg.AppPort,
+		Handler: s.router,
+	}
+{{- template "configureHTTP2" .}}
+	return srv.ListenAndServeTLS(s.cfg.TLSCertFile, s.cfg.TLSKeyFile)
+{{- else if .Project.HTTP2Push}}
+	// 未启用 TLS 时使用明文 HTTP/2 (h2c)
+	srv := &http.Server{
+		Addr:    ":" + s.cfg.AppPort,
+		Handler: h2c.NewHandler(s.router, &http2.Server{}),
+	}
+	return srv.ListenAndServe()
+{{- else}}
 	return s.router.Run(":" + s.cfg.AppPort)
+{{- end}}
+}
+{{- if and .Project.TLSEnabled (not .Project.AutoCertEnabled)}}
+
+// redirectToHTTPS 将明文 HTTP 请求（包括健康检查）永久重定向到 HTTPS 端口
+func redirectToHTTPS(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(r.Host); err == nil {
+			host = h
+		}
+		if httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
 }
+{{- end}}
+{{- define "configureHTTP2"}}
+{{- if .Project.HTTP2Push}}
+	if err := http2.ConfigureServer(srv, &http2.Server{}); err != nil {
+		return err
+	}
+{{- end}}
+{{- end}}
 `
 
-const loggerMiddlewareTemplate = `package middlewares
+const loggerMiddlewareTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package middlewares
 
 import (
+	"bytes"
+{{- if .Project.LokiEnabled}}
+	"crypto/rand"
+	"encoding/hex"
+{{- end}}
+	"io"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+{{- if .Project.LokiEnabled}}
+
+	"{{.Project.ModuleName}}/pkg/logger"
+{{- end}}
 )
 
-func LoggerMiddleware() gin.HandlerFunc {
+// 日志中请求体和响应体的最大长度
+const maxLoggedBody = 1024
+
+// 记录请求日志。请求体截断为 1KB，非 2xx 响应同时记录响应体；
+// sensitiveFields 为逗号分隔的字段名，名称包含其中任一项的 JSON 或表单字段的值会被替换为 ***
+func LoggerMiddleware(sensitiveFields string) gin.HandlerFunc {
+{{- if not .Project.LokiEnabled}}
+	log, _ := zap.NewProduction()
+{{- end}}
+	mask := newBodyMasker(sensitiveFields)
+
 	return func(c *gin.Context) {
 		start := time.Now()
 		path := c.Request.URL.Path
 		query := c.Request.URL.RawQuery
+{{- if .Project.LokiEnabled}}
 
-		c.Next()
+		requestID := c.GetHeader("X-Request-ID")
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		c.Header("X-Request-ID", requestID)
+{{- end}}
 
-		duration := time.Since(start)
+		requestBody := peekRequestBody(c)
+		writer := &bodyLogWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
 
-		logger, _ := zap.NewProduction()
-		defer logger.Sync()
+		c.Next()
 
-		logger.Info("Request",
+		duration := time.Since(start)
+		fields := []zap.Field{
 			zap.Int("status", c.Writer.Status()),
 			zap.String("method", c.Request.Method),
 			zap.String("path", path),
@@ -523,144 +2380,691 @@ func LoggerMiddleware() gin.HandlerFunc {
 			zap.String("ip", c.ClientIP()),
 			zap.String("user-agent", c.Request.UserAgent()),
 			zap.Duration("duration", duration),
-		)
+			zap.Float64("latency_ms", float64(duration.Microseconds())/1000),
+{{- if .Project.LokiEnabled}}
+			zap.String("model", modelFromRoute(c.FullPath())),
+			zap.String("request_id", requestID),
+{{- end}}
+		}
+		if len(requestBody) > 0 {
+			fields = append(fields, zap.String("request_body", mask(requestBody)))
+		}
+		if status := c.Writer.Status(); status < 200 || status >= 300 {
+			fields = append(fields, zap.String("response_body", mask(writer.body.Bytes())))
+		}
+{{- if .Project.LokiEnabled}}
+
+		logger.L().Info("Request", fields...)
+{{- else}}
+
+		log.Info("Request", fields...)
+{{- end}}
+	}
+}
+
+// 读取请求体的前 1KB 用于日志，并把读出的部分放回请求体，处理器仍能读到完整内容
+func peekRequestBody(c *gin.Context) []byte {
+	if c.Request.Body == nil {
+		return nil
+	}
+	head := make([]byte, maxLoggedBody)
+	n, _ := io.ReadFull(c.Request.Body, head)
+	head = head[:n]
+	c.Request.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), c.Request.Body), c.Request.Body}
+	return head
+}
+
+// 在写入响应的同时保留前 1KB 响应体
+type bodyLogWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyLogWriter) Write(b []byte) (int, error) {
+	if remaining := maxLoggedBody - w.body.Len(); remaining > 0 {
+		if len(b) < remaining {
+			remaining = len(b)
+		}
+		w.body.Write(b[:remaining])
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyLogWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// 返回遮盖敏感字段值的函数。按文本匹配而不解析 JSON，截断后的请求体也能遮盖
+func newBodyMasker(sensitiveFields string) func([]byte) string {
+	var names []string
+	for _, name := range strings.Split(sensitiveFields, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, regexp.QuoteMeta(name))
+		}
+	}
+	if len(names) == 0 {
+		return func(body []byte) string { return string(body) }
+	}
+	name := "(?:" + strings.Join(names, "|") + ")"
+	// JSON: "password": "..."，值可能因截断缺少结尾引号
+	jsonField := regexp.MustCompile("(?i)(\"[^\"]*" + name + "[^\"]*\"\\s*:\\s*)(\"(?:[^\"\\\\]|\\\\.)*\"?|[^,}\\s]+)")
+	// 表单: password=...
+	formField := regexp.MustCompile("(?i)((?:^|&)[^=&]*" + name + "[^=&]*=)[^&]*")
+
+	return func(body []byte) string {
+		body = jsonField.ReplaceAll(body, []byte("${1}\"***\""))
+		body = formField.ReplaceAll(body, []byte("${1}***"))
+		return string(body)
 	}
 }
+{{- if .Project.LokiEnabled}}
+
+// 从 /api/v1/<资源>/... 形式的路由中取出资源名
+func modelFromRoute(route string) string {
+	if !strings.HasPrefix(route, "/api/v1/") {
+		return ""
+	}
+	return strings.Split(strings.TrimPrefix(route, "/api/v1/"), "/")[0]
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+{{- end}}
 `
 
-const modelTemplate = `package models
+const modelTemplate = `{{$table := print .Project.TablePrefix .Model.SnakeName}}{{with .Project.FileHeader}}{{.}}
+{{end}}package models
 
 import (
+{{- if .Model.SearchEnabled}}
+{{- if not .Project.AlgoliaEnabled}}
+	"fmt"
+{{- end}}
+	"log"
+{{- end}}
 	"time"
-
+{{- if .Model.HasLengthLimits}}
+	"unicode/utf8"
+{{- end}}
+{{if .Model.UsesFieldType "uuid.UUID"}}
+	"github.com/google/uuid"
+{{- end}}
 	"gorm.io/gorm"
+{{- if or .Model.UsesNotifications .Model.SearchEnabled}}
+{{end}}
+{{- if .Model.UsesNotifications}}
+	"{{.Project.ModuleName}}/pkg/notifications"
+{{- end}}
+{{- if .Model.SearchEnabled}}
+	"{{.Project.ModuleName}}/pkg/search"
+{{- end}}
 )
 
 type {{.Model.Name}} struct {
-	{{range .Model.Fields}}{{.Name}} {{.Type}} ` + "`{{if .GormTag}}gorm:\"{{.GormTag}}\" {{end}}json:\"{{.JsonTag}}{{if .Required}},omitempty{{end}}\"`" + `
-	{{end}}CreatedAt time.Time      ` + "`json:\"created_at\"`" + `
-	UpdatedAt time.Time      ` + "`json:\"updated_at\"`" + `
-	DeletedAt gorm.DeletedAt ` + "`gorm:\"index\" json:\"-\"`" + `
+	ID {{.Model.IDGoType}} ` + "`gorm:\"primaryKey{{if eq .Model.IDType \"uuid\"}};type:char(36){{end}}\" json:\"id\"`" + `
+	{{range .Model.OrderedFields}}{{.Name}} {{.Type}} ` + "`{{with .StructGormTag $table}}gorm:\"{{.}}\" {{end}}json:\"{{.JsonTag}}{{if .Required}},omitempty{{end}}\"`" + `
+	{{end}}CreatedAt time.Time      ` + "`json:\"{{.Project.JSONTag \"CreatedAt\"}}\"`" + `
+	UpdatedAt time.Time      ` + "`json:\"{{.Project.JSONTag \"UpdatedAt\"}}\"`" + `
+	DeletedAt gorm.DeletedAt ` + "`gorm:\"{{indexTag \"index\" $table \"deleted_at\"}}\" json:\"-\"`" + `
 }
 
 func ({{.Model.Name}}) TableName() string {
-	return "{{.Model.SnakeName}}"
+	return "{{.Project.TablePrefix}}{{.Model.SnakeName}}"
+}
+{{- if .Model.SearchEnabled}}
+
+// {{.Model.Name}}SearchIndex 是同步 {{.Model.Name}} 的{{if .Project.AlgoliaEnabled}} Algolia 索引，实际索引名前加 ALGOLIA_INDEX_PREFIX{{else}} Elasticsearch 索引{{end}}
+const {{.Model.Name}}SearchIndex = "{{.Project.TablePrefix}}{{.Model.SnakeName}}"
+{{- end}}
+{{- if .Model.HasDefaults}}
+
+// New{{.Model.Name}} 返回字段已设为声明默认值的 {{.Model.Name}}
+func New{{.Model.Name}}() *{{.Model.Name}} {
+	return &{{.Model.Name}}{
+{{- range .Model.OrderedFields}}{{if .Default}}
+		{{.Name}}: {{goDefault .}},
+{{- end}}{{end}}
+	}
+}
+{{- end}}
+{{- if .Model.HasValidation}}
+
+// Validate 检查必填字段和字符串长度，创建和更新前由 GORM 钩子调用
+func (m *{{.Model.Name}}) Validate() error {
+{{- range .Model.OrderedFields}}
+{{- if and .Required .ZeroCheck}}
+	if {{.ZeroCheck}} {
+		return &ValidationError{Field: "{{.ValidationName}}", Rule: "required"}
+	}
+{{- end}}
+{{- if gt .MinLength 0}}
+	if {{if not .Required}}m.{{.Name}} != "" && {{end}}utf8.RuneCountInString(m.{{.Name}}) < {{.MinLength}} {
+		return &ValidationError{Field: "{{.ValidationName}}", Rule: "min_length", Limit: {{.MinLength}}}
+	}
+{{- end}}
+{{- if gt .MaxLength 0}}
+	if utf8.RuneCountInString(m.{{.Name}}) > {{.MaxLength}} {
+		return &ValidationError{Field: "{{.ValidationName}}", Rule: "max_length", Limit: {{.MaxLength}}}
+	}
+{{- end}}
+{{- end}}
+	return nil
+}
+{{- end}}
+{{- if .Model.GeneratedHook "BeforeCreate"}}
+
+// BeforeCreate {{if eq .Model.IDType "uuid"}}在未指定主键时生成 UUID{{if or .Model.HasValidation .Model.PushNotification}}，并{{end}}{{end}}{{if .Model.HasValidation}}校验字段{{if .Model.PushNotification}}，然后{{end}}{{end}}{{if .Model.PushNotification}}通过 FCM 发送推送{{end}}
+func (m *{{.Model.Name}}) BeforeCreate(tx *gorm.DB) error {
+{{- if eq .Model.IDType "uuid"}}
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+{{- end}}
+{{- if .Model.HasValidation}}
+	if err := m.Validate(); err != nil {
+		return err
+	}
+{{- end}}
+{{- if .Model.PushNotification}}
+	if err := m.sendPush(tx); err != nil {
+		return err
+	}
+{{- end}}
+{{- with .Model.Hook "BeforeCreate"}}{{template "hookBody" .}}{{else}}
+	return nil{{end}}
+}
+{{- end}}
+{{- if .Model.PushNotification}}
+
+// sendPush 通过 FCM 发送推送，成功后记录发送时间
+func (m *{{.Model.Name}}) sendPush(tx *gorm.DB) error {
+{{- if .Project.NotificationCenter}}
+	// 未指定设备的通知由通知中心的 FCM 渠道推送到用户主题
+	if m.DeviceToken == "" {
+		return nil
+	}
+{{- end}}
+	if _, err := notifications.SendPush(tx.Statement.Context, m.DeviceToken, m.Title, m.Body); err != nil {
+		return err
+	}
+	m.SentAt = time.Now()
+	return nil
+}
+{{- end}}
+{{- if .Model.GeneratedHook "BeforeUpdate"}}
+
+// BeforeUpdate 更新前校验字段
+func (m *{{.Model.Name}}) BeforeUpdate(tx *gorm.DB) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+{{- with .Model.Hook "BeforeUpdate"}}{{template "hookBody" .}}{{else}}
+	return nil{{end}}
+}
+{{- end}}
+{{- if .Model.GeneratedHook "AfterCreate"}}
+
+// AfterCreate 创建后{{if .Model.SlackNotify}}发送 Slack 通知{{if .Model.PublishNotification}}，并{{end}}{{end}}{{if .Model.PublishNotification}}投递到已注册的通知渠道{{end}}{{if .Model.SearchEnabled}}{{if or .Model.SlackNotify .Model.PublishNotification}}，并{{end}}写入搜索索引{{end}}
+func (m *{{.Model.Name}}) AfterCreate(tx *gorm.DB) error {
+{{- if .Model.SlackNotify}}
+	notifications.NotifyCreated("{{.Model.Name}}", m.ID)
+{{- end}}
+{{- if .Model.PublishNotification}}
+	notifications.Publish(notifications.Message{UserID: m.UserID, Type: m.Type, Title: m.Title, Body: m.Body})
+{{- end}}
+{{- if .Model.SearchEnabled}}
+	m.indexSearch(tx)
+{{- end}}
+{{- with .Model.Hook "AfterCreate"}}{{template "hookBody" .}}{{else}}
+	return nil{{end}}
+}
+{{- end}}
+{{- if .Model.SearchEnabled}}
+
+// AfterUpdate 更新后{{if .Project.AlgoliaEnabled}}把记录的字段同步到{{else}}覆盖{{end}}搜索索引中的文档
+func (m *{{.Model.Name}}) AfterUpdate(tx *gorm.DB) error {
+{{- if .Project.AlgoliaEnabled}}
+	m.updateSearchIndex(tx)
+{{- else}}
+	m.indexSearch(tx)
+{{- end}}
+{{- with .Model.Hook "AfterUpdate"}}{{template "hookBody" .}}{{else}}
+	return nil{{end}}
+}
+
+// AfterDelete 删除后移除搜索索引中的文档，删除前需要先查询出记录，否则 ID 为空
+func (m *{{.Model.Name}}) AfterDelete(tx *gorm.DB) error {
+	m.removeSearchIndex(tx)
+{{- with .Model.Hook "AfterDelete"}}{{template "hookBody" .}}{{else}}
+	return nil{{end}}
+}
+
+{{- if .Project.AlgoliaEnabled}}
+
+// indexSearch 把记录写入 Algolia，失败只记录日志，不回滚数据库写入
+func (m *{{.Model.Name}}) indexSearch(tx *gorm.DB) {
+	if err := search.IndexRecord({{.Model.Name}}SearchIndex, search.ObjectID(m.ID), m); err != nil {
+		log.Printf("search: indexing {{.Model.Name}} %v: %v", m.ID, err)
+	}
+}
+
+func (m *{{.Model.Name}}) updateSearchIndex(tx *gorm.DB) {
+	if err := search.UpdateRecord({{.Model.Name}}SearchIndex, search.ObjectID(m.ID), m); err != nil {
+		log.Printf("search: updating {{.Model.Name}} %v: %v", m.ID, err)
+	}
+}
+
+func (m *{{.Model.Name}}) removeSearchIndex(tx *gorm.DB) {
+	if err := search.DeleteRecord({{.Model.Name}}SearchIndex, search.ObjectID(m.ID)); err != nil {
+		log.Printf("search: deleting {{.Model.Name}} %v: %v", m.ID, err)
+	}
+}
+{{- else}}
+
+// indexSearch 把记录写入 Elasticsearch，失败只记录日志，不回滚数据库写入
+func (m *{{.Model.Name}}) indexSearch(tx *gorm.DB) {
+	if err := search.Index(tx.Statement.Context, {{.Model.Name}}SearchIndex, fmt.Sprint(m.ID), m); err != nil {
+		log.Printf("search: indexing {{.Model.Name}} %v: %v", m.ID, err)
+	}
+}
+
+func (m *{{.Model.Name}}) removeSearchIndex(tx *gorm.DB) {
+	if err := search.Delete(tx.Statement.Context, {{.Model.Name}}SearchIndex, fmt.Sprint(m.ID)); err != nil {
+		log.Printf("search: deleting {{.Model.Name}} %v: %v", m.ID, err)
+	}
+}
+{{- end}}
+{{- end}}
+{{- range .Model.Hooks}}{{if not ($.Model.GeneratedHook .Name)}}
+
+// {{.Name}} 自定义 GORM 钩子
+func (m *{{$.Model.Name}}) {{.Name}}(tx *gorm.DB) error {
+{{- template "hookBody" .}}
+}
+{{- end}}{{end}}
+{{- range .Model.Scopes}}
+
+// {{$.Model.Name}}{{.Name}} 查询条件: {{.Condition}}
+func {{$.Model.Name}}{{.Name}}(db *gorm.DB) *gorm.DB {
+	return db.Where({{printf "%q" .Condition}})
 }
+{{- end}}
+
+{{- define "hookBody"}}{{range .Lines}}
+	{{.}}{{end}}{{if not .EndsWithReturn}}
+	return nil{{end}}{{end}}
 `
 
-const handlerTemplate = `package handlers
+const handlerTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package handlers
 
 import (
+{{- if or .Model.HasValidation (and .Project.CQRS .Model.DeleteLoadsRecord)}}
+	"errors"
+{{- end}}
+{{- if .Project.EventSourcing}}
+	"fmt"
+{{- end}}
 	"net/http"
+{{- if .Model.NumericID}}
 	"strconv"
+{{- end}}
 
 	"github.com/gin-gonic/gin"
+{{- if eq .Model.IDType "uuid"}}
+	"github.com/google/uuid"
+{{- end}}
 	"gorm.io/gorm"
 
+{{if .Project.CQRS}}	"{{.Project.ModuleName}}/pkg/commands"
+{{end}}{{if .Project.EventSourcing}}	"{{.Project.ModuleName}}/pkg/events"
+{{end}}	"{{.Project.ModuleName}}/pkg/i18n"
 	"{{.Project.ModuleName}}/pkg/models"
+{{- if .Project.CQRS}}
+	"{{.Project.ModuleName}}/pkg/queries"
+{{- end}}
+{{- if .Project.MetricsEnabled}}
+	"{{.Project.ModuleName}}/pkg/tracing"
+{{- end}}
 )
+{{- if .Model.Scopes}}
+
+// 列表接口 ?scope= 参数可用的查询条件
+var {{.Model.LowerName}}Scopes = map[string]func(*gorm.DB) *gorm.DB{
+{{- range .Model.Scopes}}
+	"{{.Name}}": models.{{$.Model.Name}}{{.Name}},
+{{- end}}
+}
+{{- end}}
 
-func Register{{.Model.Name}}Routes(rg *gin.RouterGroup, db *gorm.DB) {
+{{if .Project.CQRS}}// 读接口使用 readDB（只读库），写接口使用 db（主库）
+{{end}}func Register{{.Model.Name}}Routes(rg *gin.RouterGroup, db{{if .Project.CQRS}}, readDB{{end}} *gorm.DB) {
 	{{.Model.LowerName}}Group := rg.Group("/{{.Model.PluralName}}")
+{{- if .Project.MetricsEnabled}}
+	{{.Model.LowerName}}Group.Use(tracing.Instrument("{{pluralize .Model.SnakeName}}"))
+{{- end}}
 	{
-		{{.Model.LowerName}}Group.GET("", list{{.Model.Name}}s(db))
+		{{.Model.LowerName}}Group.GET("", list{{.Model.Name}}s({{if .Project.CQRS}}readDB{{else}}db{{end}}))
+{{- if .Model.SearchEnabled}}
+		{{.Model.LowerName}}Group.GET("/search", search{{.Model.Name}}s({{if .Project.AlgoliaEnabled}}{{if .Project.CQRS}}readDB{{else}}db{{end}}{{end}}))
+{{- end}}
 		{{.Model.LowerName}}Group.POST("", create{{.Model.Name}}(db))
-		{{.Model.LowerName}}Group.GET("/:id", get{{.Model.Name}}(db))
+		{{.Model.LowerName}}Group.GET("/:id", get{{.Model.Name}}({{if .Project.CQRS}}readDB{{else}}db{{end}}))
 		{{.Model.LowerName}}Group.PUT("/:id", update{{.Model.Name}}(db))
 		{{.Model.LowerName}}Group.DELETE("/:id", delete{{.Model.Name}}(db))
+{{- if .Model.VersionHistory}}
+		{{.Model.LowerName}}Group.GET("/:id/history", get{{.Model.Name}}History(db))
+{{- end}}
+{{- if .Project.EventSourcing}}
+		{{.Model.LowerName}}Group.GET("/:id/events", get{{.Model.Name}}Events(db))
+{{- end}}
 	}
 }
 
-func list{{.Model.Name}}s(db *gorm.DB) gin.HandlerFunc {
+{{block "list" .}}{{if .Project.CQRS}}func list{{.Model.Name}}s(readDB *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+{{- if .Model.Scopes}}
+		var scopes []func(*gorm.DB) *gorm.DB
+		for _, name := range c.QueryArray("scope") {
+			scope, ok := {{.Model.LowerName}}Scopes[name]
+			if !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(language, "unknown_scope", name)})
+				return
+			}
+			scopes = append(scopes, scope)
+		}
+		views, err := queries.List{{.Model.Name}}s(readDB, scopes...)
+{{- else}}
+		views, err := queries.List{{.Model.Name}}s(readDB)
+{{- end}}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, views)
+	}
+}{{else}}func list{{.Model.Name}}s(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var {{.Model.PluralName}} []models.{{.Model.Name}}
-		if result := db.Find(&{{.Model.PluralName}}); result.Error != nil {
+{{- if .Model.Scopes}}
+		query := {{template "readDB" .}}
+		for _, name := range c.QueryArray("scope") {
+			scope, ok := {{.Model.LowerName}}Scopes[name]
+			if !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(language, "unknown_scope", name)})
+				return
+			}
+			query = query.Scopes(scope)
+		}
+		if result := query.Find(&{{.Model.PluralName}}); result.Error != nil {
+{{- else}}
+		if result := {{template "readDB" .}}.Find(&{{.Model.PluralName}}); result.Error != nil {
+{{- end}}
 			c.JSON(http.StatusInternalServerError, gin.H{"error": result.Error.Error()})
 			return
 		}
 		c.JSON(http.StatusOK, {{.Model.PluralName}})
 	}
-}
+}{{end}}{{end}}
 
-func create{{.Model.Name}}(db *gorm.DB) gin.HandlerFunc {
+{{block "create" .}}func create{{.Model.Name}}(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
+{{- if .Project.EventSourcing}}
+		var cmd events.Create{{.Model.Name}}Command
+		if err := c.ShouldBindJSON(&cmd); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		var input models.{{.Model.Name}}
+		cmd.Apply(&input)
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&input).Error; err != nil {
+				return err
+			}
+			return events.NewEventStore(tx).Append(events.New{{.Model.Name}}CreatedEvent(&input))
+		}); err != nil {
+{{- template "validationError" .}}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+{{- else}}
 		var input models.{{.Model.Name}}
 		if err := c.ShouldBindJSON(&input); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 
-		if result := db.Create(&input); result.Error != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": result.Error.Error()})
+		if err := {{if .Project.CQRS}}commands.Create{{.Model.Name}}(db, &input){{else}}db.Create(&input){{end}}.Error; err != nil {
+{{- template "validationError" .}}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
+{{- end}}
 
 		c.JSON(http.StatusCreated, input)
 	}
-}
+}{{end}}
 
-func get{{.Model.Name}}(db *gorm.DB) gin.HandlerFunc {
+{{block "get" .}}{{if .Project.CQRS}}func get{{.Model.Name}}(readDB *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		id, err := strconv.Atoi(c.Param("id"))
+{{- template "parseID" .}}
+
+		view, err := queries.Get{{.Model.Name}}(readDB, {{template "idValue" .}})
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+			c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(language, "not_found", "{{.Model.Name}}")})
 			return
 		}
 
+		c.JSON(http.StatusOK, view)
+	}
+}{{else}}func get{{.Model.Name}}(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+{{- template "parseID" .}}
+
 		var {{.Model.LowerName}} models.{{.Model.Name}}
-		if result := db.First(&{{.Model.LowerName}}, id); result.Error != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "{{.Model.Name}} not found"})
+		if result := {{template "readDB" .}}.First(&{{.Model.LowerName}}, {{template "idArgs" .}}); result.Error != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(language, "not_found", "{{.Model.Name}}")})
 			return
 		}
 
 		c.JSON(http.StatusOK, {{.Model.LowerName}})
 	}
-}
+}{{end}}{{end}}
 
-func update{{.Model.Name}}(db *gorm.DB) gin.HandlerFunc {
+{{block "update" .}}func update{{.Model.Name}}(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		id, err := strconv.Atoi(c.Param("id"))
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+{{- template "parseID" .}}
+
+		var {{.Model.LowerName}} models.{{.Model.Name}}
+		if result := {{template "readDB" .}}.First(&{{.Model.LowerName}}, {{template "idArgs" .}}); result.Error != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(language, "not_found", "{{.Model.Name}}")})
 			return
 		}
 
-		var {{.Model.LowerName}} models.{{.Model.Name}}
-		if result := db.First(&{{.Model.LowerName}}, id); result.Error != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "{{.Model.Name}} not found"})
+{{- if .Project.EventSourcing}}
+		var cmd events.Update{{.Model.Name}}Command
+		if err := c.ShouldBindJSON(&cmd); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 
+		cmd.Apply(&{{.Model.LowerName}})
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Save(&{{.Model.LowerName}}).Error; err != nil {
+				return err
+			}
+			return events.NewEventStore(tx).Append(events.New{{.Model.Name}}UpdatedEvent(&{{.Model.LowerName}}))
+		}); err != nil {
+{{- template "validationError" .}}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+{{- else}}
 		if err := c.ShouldBindJSON(&{{.Model.LowerName}}); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 
-		if result := db.Save(&{{.Model.LowerName}}); result.Error != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": result.Error.Error()})
+		if err := {{if .Project.CQRS}}commands.Update{{.Model.Name}}(db, &{{.Model.LowerName}}){{else}}db.Save(&{{.Model.LowerName}}){{end}}.Error; err != nil {
+{{- template "validationError" .}}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
+{{- end}}
 
 		c.JSON(http.StatusOK, {{.Model.LowerName}})
 	}
-}
+}{{end}}
 
-func delete{{.Model.Name}}(db *gorm.DB) gin.HandlerFunc {
+{{block "delete" .}}func delete{{.Model.Name}}(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		id, err := strconv.Atoi(c.Param("id"))
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+{{- template "parseID" .}}
+{{- if .Project.CQRS}}
+
+		if result := commands.Delete{{.Model.Name}}(db, {{template "idValue" .}}); result.Error != nil {
+{{- if .Model.DeleteLoadsRecord}}
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(language, "not_found", "{{.Model.Name}}")})
+				return
+			}
+{{- end}}
+{{- else if .Model.DeleteLoadsRecord}}
+
+		// 先查询出记录，AfterDelete 钩子才能{{if .Model.VersionHistory}}写入完整的历史快照{{else}}删除搜索索引中的文档{{end}}
+		var {{.Model.LowerName}} models.{{.Model.Name}}
+		if result := {{template "readDB" .}}.First(&{{.Model.LowerName}}, {{template "idArgs" .}}); result.Error != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(language, "not_found", "{{.Model.Name}}")})
 			return
 		}
 
-		if result := db.Delete(&models.{{.Model.Name}}{}, id); result.Error != nil {
+		if result := db.Delete(&{{.Model.LowerName}}); result.Error != nil {
+{{- else}}
+
+		if result := db.Delete(&models.{{.Model.Name}}{}, {{template "idArgs" .}}); result.Error != nil {
+{{- end}}
 			c.JSON(http.StatusInternalServerError, gin.H{"error": result.Error.Error()})
 			return
 		}
 
 		c.JSON(http.StatusNoContent, nil)
 	}
+}{{end}}
+{{- if .Model.VersionHistory}}
+
+{{block "history" .}}func get{{.Model.Name}}History(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+{{- template "parseID" .}}
+
+		var histories []models.{{.Model.Name}}History
+		if result := db.Where("record_id = ?", id).Order("changed_at").Find(&histories); result.Error != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": result.Error.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, histories)
+	}
+}{{end}}
+{{- end}}
+{{- if .Project.EventSourcing}}
+
+{{block "events" .}}// 按写入顺序返回 {{.Model.Name}} 的事件
+func get{{.Model.Name}}Events(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+{{- template "parseID" .}}
+
+		stored, err := events.NewEventStore(db).Load("{{.Model.Name}}", fmt.Sprint(id))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, stored)
+	}
+}{{end}}
+{{- end}}
+{{- if .Model.ParanoidMode}}
+
+// Register{{.Model.Name}}AdminRoutes 注册可以查看和恢复软删除记录的管理接口，rg 应已经过认证
+func Register{{.Model.Name}}AdminRoutes(rg *gin.RouterGroup, db *gorm.DB) {
+	{{.Model.LowerName}}Group := rg.Group("/{{.Model.PluralName}}")
+	{
+		{{.Model.LowerName}}Group.GET("", list{{.Model.Name}}sUnscoped(db))
+		{{.Model.LowerName}}Group.POST("/:id/restore", restore{{.Model.Name}}(db))
+	}
 }
+
+{{block "listUnscoped" .}}func list{{.Model.Name}}sUnscoped(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var {{.Model.PluralName}} []models.{{.Model.Name}}
+		if result := db.Unscoped().Find(&{{.Model.PluralName}}); result.Error != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": result.Error.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, {{.Model.PluralName}})
+	}
+}{{end}}
+
+{{block "restore" .}}func restore{{.Model.Name}}(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+{{- template "parseID" .}}
+
+		var {{.Model.LowerName}} models.{{.Model.Name}}
+		if result := db.Unscoped().Where("deleted_at IS NOT NULL").First(&{{.Model.LowerName}}, {{template "idArgs" .}}); result.Error != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(language, "not_found", "{{.Model.Name}}")})
+			return
+		}
+
+		if result := db.Unscoped().Model(&{{.Model.LowerName}}).Update("deleted_at", nil); result.Error != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": result.Error.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, {{.Model.LowerName}})
+	}
+}{{end}}
+{{- end}}
+{{- define "parseID"}}
+{{- if eq .Model.IDType "string"}}
+		id := c.Param("id")
+{{- else}}
+{{- if eq .Model.IDType "uuid"}}
+		id, err := uuid.Parse(c.Param("id"))
+{{- else if eq .Model.IDType "int64"}}
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+{{- else}}
+		id, err := strconv.Atoi(c.Param("id"))
+{{- end}}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(language, "invalid_id")})
+			return
+		}
+{{- end}}
+{{- end}}
+
+{{- /* @paranoid 模型显式过滤软删除的记录，不依赖 GORM 的默认作用域 */}}
+{{- define "readDB"}}{{if .Model.ParanoidMode}}db.Where("deleted_at IS NULL"){{else}}db{{end}}{{end}}
+
+{{- /* 模型 Validate 方法返回的错误转换为 422，调用处的写入错误须命名为 err */}}
+{{- define "validationError"}}{{if .Model.HasValidation}}
+			var validationErr *models.ValidationError
+			if errors.As(err, &validationErr) {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": validationMessage(validationErr), "field": validationErr.Field})
+				return
+			}
+{{- end}}{{end}}
+
+{{- /* 字符串主键不能作为内联条件直接传给 GORM，否则会被当作SQL */}}
+{{- define "idArgs"}}{{if .Model.NumericID}}id{{else}}"id = ?", id{{end}}{{end}}
+
+{{- /* 解析出的 id 转换为主键类型，strconv.Atoi 得到的是 int */}}
+{{- define "idValue"}}{{if eq .Model.IDType "uint"}}uint(id){{else}}id{{end}}{{end}}
 `
 
 const apiSpecTemplate = `openapi: 3.0.0
@@ -671,6 +3075,22 @@ paths:
   /api/v1/{{.Model.PluralName}}:
     get:
       summary: 获取所有{{.Model.PluralName}}
+{{- if .Model.Scopes}}
+      parameters:
+        - name: scope
+          in: query
+          description: 应用的查询条件，可重复
+          schema:
+            type: array
+            items:
+              type: string
+              enum:
+{{- range .Model.Scopes}}
+                - {{.Name}}
+{{- end}}
+          style: form
+          explode: true
+{{- end}}
       responses:
         '200':
           description: 成功
@@ -693,7 +3113,7 @@ paths:
           in: path
           required: true
           schema:
-            type: integer
+            type: {{if .Model.NumericID}}integer{{else}}string{{end}}
       responses:
         '200':
           description: 成功
@@ -704,7 +3124,7 @@ paths:
           in: path
           required: true
           schema:
-            type: integer
+            type: {{if .Model.NumericID}}integer{{else}}string{{end}}
       requestBody:
         required: true
         content:
@@ -721,10 +3141,24 @@ paths:
           in: path
           required: true
           schema:
-            type: integer
+            type: {{if .Model.NumericID}}integer{{else}}string{{end}}
       responses:
         '204':
           description: 删除成功
+{{- if .Model.VersionHistory}}
+  /api/v1/{{.Model.PluralName}}/{id}/history:
+    get:
+      summary: 获取{{.Model.Name}}的变更历史
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: {{if .Model.NumericID}}integer{{else}}string{{end}}
+      responses:
+        '200':
+          description: 成功
+{{- end}}
 
 components:
   schemas:
@@ -732,37 +3166,335 @@ components:
       type: object
       properties:
         id:
-          type: integer
-        {{range .Model.Fields}}
+          type: {{if .Model.NumericID}}integer{{else}}string{{end}}
+{{- if eq .Model.IDType "uuid"}}
+          format: uuid
+{{- end}}
+        {{range .Model.Fields}}{{if ne .JsonTag "-"}}
         {{.JsonTag}}:
           type: {{if eq .Type "string"}}string{{else if eq .Type "int"}}integer{{else if eq .Type "bool"}}boolean{{else}}string{{end}}
-        {{end}}
-        created_at:
+{{- if gt .MinLength 0}}
+          minLength: {{.MinLength}}
+{{- end}}
+{{- if gt .MaxLength 0}}
+          maxLength: {{.MaxLength}}
+{{- end}}
+        {{end}}{{end}}
+        {{.Project.JSONTag "CreatedAt"}}:
           type: string
           format: date-time
-        updated_at:
+        {{.Project.JSONTag "UpdatedAt"}}:
           type: string
           format: date-time
 `
 
 const envTemplate = `APP_PORT={{.Project.Port}}
+APP_LANGUAGE={{.Project.Language}}
 DB_HOST=127.0.0.1
+{{- if eq .Project.DBDriver "postgres"}}
+DB_PORT=5432  # PostgreSQL 默认端口
+DB_USER=postgres
+DB_PASSWORD=your_postgres_password
+DB_NAME=book
+# sslmode: disable、require（加密但不校验证书）、verify-ca（校验 CA）或 verify-full（同时校验主机名）
+DB_SSL_MODE=disable
+{{- else}}
 DB_PORT=3306  # MySQL 默认端口
 DB_USER=root
 DB_PASSWORD=your_mysql_password
 DB_NAME=book
-# 移除 DB_SSL 配置，因为 MySQL 不使用 sslmode
+DB_CHARSET={{.Project.MySQLCharset}}
+DB_COLLATION={{.Project.MySQLCollation}}
+# 设为 true 时使用 TLS 连接并校验服务器证书，DB_SSL_CA 为 CA 证书路径，为空时使用系统根证书
+DB_SSL=false
+DB_SSL_CA=
+{{- end}}
+{{- if .Project.CQRS}}
+# 列表和详情接口使用的只读副本地址，其余连接参数与主库相同，为空时使用主库
+DB_READ_HOST=
+{{- end}}
+# 请求日志中值会被替换为 *** 的字段名，字段名包含其中任一项即遮盖
+LOG_SENSITIVE_FIELDS=password,token,secret
+# 逗号分隔的反向代理地址 (CIDR 或IP)，只采用这些代理设置的 X-Forwarded-For 作为客户端IP；
+# 为空时不信任任何代理，部署在负载均衡或 Ingress 之后时填写其地址，如 10.0.0.0/8
+TRUSTED_PROXIES=
+{{- if .Project.GRPCEnabled}}
+# gRPC 健康检查 (grpc.health.v1) 的监听端口
+GRPC_PORT={{.Project.GRPCPort}}
+{{- end}}
+{{- if gt .Project.MaxRequestBodyMB 0}}
+MAX_REQUEST_BODY_MB={{.Project.MaxRequestBodyMB}}
+{{- end}}
+{{- if gt .Project.RequestTimeoutSec 0}}
+REQUEST_TIMEOUT_SEC={{.Project.RequestTimeoutSec}}
+{{- end}}
+{{- if gt .Project.RateLimitRPS 0}}
+# 每个客户端{{if .Project.RateLimitByAPIKey}}（X-API-Key 属于 RATE_LIMIT_API_KEYS 时按 API key，否则按 IP）{{end}}每秒补充的令牌数和令牌桶容量，超出时返回 429
+RATE_LIMIT_RPS={{.Project.RateLimitRPS}}
+RATE_LIMIT_BURST={{.Project.RateLimitRPS}}
+{{- if .Project.RateLimitByAPIKey}}
+# 逗号分隔的 API key，未列出的 key 不单独限流
+RATE_LIMIT_API_KEYS=
+{{- end}}
+{{- end}}
+{{- if .Project.SecurityHeaders}}
+CONTENT_SECURITY_POLICY="default-src 'self'"
+{{- end}}
+{{- if .Project.IPFilterEnabled}}
+IP_WHITELIST={{.Project.IPWhitelist}}
+IP_BLACKLIST={{.Project.IPBlacklist}}
+{{- end}}
+{{- if .Project.SlackEnabled}}
+# Slack incoming webhook 地址，为空时消息只输出到日志
+SLACK_WEBHOOK_URL={{.Project.SlackWebhookURL}}
+{{- end}}
+{{- if .Project.FCMEnabled}}
+# Firebase 服务账号的 JSON 密钥文件，为空时推送只输出到日志
+FIREBASE_CREDENTIALS_FILE=
+{{- end}}
+{{- if .Project.TwilioEnabled}}
+# Twilio 控制台中的 Account SID、Auth Token 和发送号码 (E.164 格式)，SID 为空时短信只输出到日志
+TWILIO_ACCOUNT_SID=
+TWILIO_AUTH_TOKEN=
+TWILIO_FROM_NUMBER=
+{{- end}}
+{{- if .Project.NotificationCenter}}
+# 通知中心的 webhook 地址，新建的 Notification 以 JSON POST 到该地址，为空时不发送
+NOTIFICATION_WEBHOOK_URL=
+{{- end}}
+{{- if .Project.StripeEnabled}}
+# Stripe 控制台中的 API 密钥 (sk_...) 和 webhook 端点的签名密钥 (whsec_...)
+STRIPE_SECRET_KEY=
+STRIPE_WEBHOOK_SECRET=
+{{- end}}
+{{- if .Project.AdminPanelEnabled}}
+# /admin 管理接口的 Basic 认证账号，未设置 ADMIN_USER，或 ADMIN_PASS 为空、change_me 时不注册管理接口
+ADMIN_USER=
+ADMIN_PASS=
+{{- end}}
+{{- if .Project.AutoCertEnabled}}
+# 证书缓存在 ./certs，需要开放 80 和 443 端口
+LETSENCRYPT_DOMAIN={{.Project.LetsEncryptDomain}}
+LETSENCRYPT_EMAIL={{.Project.LetsEncryptEmail}}
+{{- else if .Project.TLSEnabled}}
+TLS_CERT_FILE={{.Project.TLSCertFile}}
+TLS_KEY_FILE={{.Project.TLSKeyFile}}
+# 非空时在该端口监听明文 HTTP 并重定向到 HTTPS
+HTTP_REDIRECT_PORT=
+{{- end}}
+{{- if eq .Project.AuthMode "magiclink"}}
+# 签名令牌的随机密钥，例如 openssl rand -hex 32 的输出，为空或 change_me 时拒绝启动
+JWT_SECRET=
+{{- end}}
+{{- if eq .Project.AuthMode "magiclink"}}
+APP_BASE_URL=http://localhost:{{.Project.Port}}
+EMAIL_PROVIDER=log  # log、smtp 或 sendgrid
+FROM_EMAIL=no-reply@example.com
+SMTP_HOST=
+SMTP_PORT=587
+SMTP_USER=
+SMTP_PASSWORD=
+SENDGRID_API_KEY=
+{{- else if .Project.EmailEnabled}}
+# 邮件通知的发件人和 SendGrid API key，API key 为空时邮件只输出到日志
+FROM_EMAIL=no-reply@example.com
+SENDGRID_API_KEY=
+{{- end}}
+{{- if eq .Project.AuthMode "session"}}
+# 签名会话的随机密钥，例如 openssl rand -hex 32 的输出，为空或 change_me 时拒绝启动
+SESSION_SECRET=
+SESSION_STORE=cookie  # cookie、redis 或 memory
+SESSION_TTL_HOURS=24
+{{- end}}
+{{- if or (eq .Project.AuthMode "session") .Project.QuotaEnabled .Project.ChatEnabled}}
+REDIS_ADDR=127.0.0.1:6379
+{{- end}}
+{{- if .Project.QuotaEnabled}}
+# 每个登录用户每月的请求数上限，每月 1 日 0 点 (UTC) 重置，超出时返回 429
+USER_MONTHLY_QUOTA=10000
+{{- end}}
+{{- if .Project.DatadogEnabled}}
+DD_AGENT_HOST=127.0.0.1
+DD_SERVICE_NAME={{.Project.ProjectName}}
+DD_ENV=development
+{{- end}}
+{{- if .Project.SentryEnabled}}
+SENTRY_DSN=
+SENTRY_ENVIRONMENT=development
+{{- end}}
+{{- if or .Project.MetricsEnabled (eq .Project.TracingBackend "otlp")}}
+OTEL_EXPORTER_OTLP_ENDPOINT=127.0.0.1:4317
+{{- end}}
+{{- if .Project.TracingBackend}}
+OTEL_SERVICE_NAME={{.Project.ProjectName}}
+{{- end}}
+{{- if eq .Project.TracingBackend "jaeger"}}
+JAEGER_ENDPOINT=http://127.0.0.1:14268/api/traces
+{{- end}}
+{{- if eq .Project.TracingBackend "zipkin"}}
+ZIPKIN_ENDPOINT=http://127.0.0.1:9411/api/v2/spans
+{{- end}}
+{{- if .Project.LokiEnabled}}
+APP_ENV=development
+LOKI_URL=http://127.0.0.1:3100/loki/api/v1/push
+LOKI_TENANT_ID=
+{{- end}}
+{{- if .Project.ConsulEnabled}}
+# 留空的 DB_HOST、DB_PORT、DB_USER、DB_PASSWORD、DB_NAME 从 Consul KV 的 {{.Project.KubeName}}/config/<键名> 读取
+CONSUL_ADDRESS={{.Project.ConsulAddress}}
+SERVICE_TAGS=api,{{.Project.DBDriver}}
+{{- end}}
+{{- if .Project.ElasticsearchEnabled}}
+# 为空时不同步索引，搜索接口返回 503
+ELASTICSEARCH_URL={{.Project.ElasticsearchURL}}
+{{- end}}
+{{- if .Project.AlgoliaEnabled}}
+# Algolia 控制台中的 Application ID 和 Admin API Key，APP_ID 为空时不同步索引，搜索接口返回 503
+ALGOLIA_APP_ID=
+ALGOLIA_API_KEY=
+# 索引名前缀，多个环境共用一个 Algolia 应用时用于区分
+ALGOLIA_INDEX_PREFIX=dev_
+{{- end}}
 `
 
+// 默认的Go版本
+const defaultGoVersion = "1.20"
+
+// 各Go版本对应的核心依赖版本，未列出的依赖沿用 defaultGoVersion 的版本
+var dependencyVersions = map[string]map[string]string{
+	"1.20": {
+		"github.com/gin-gonic/gin": "v1.9.1",
+		"github.com/spf13/viper":   "v1.16.0",
+		"gorm.io/driver/mysql":     "v1.6.0",
+		"gorm.io/gorm":             "v1.25.4",
+	},
+	"1.21": {
+		"github.com/gin-gonic/gin": "v1.10.0",
+		"github.com/spf13/viper":   "v1.18.2",
+		"gorm.io/gorm":             "v1.25.10",
+	},
+	"1.22": {
+		"github.com/gin-gonic/gin": "v1.10.0",
+		"github.com/spf13/viper":   "v1.19.0",
+		"gorm.io/gorm":             "v1.25.12",
+	},
+	"1.23": {
+		"github.com/gin-gonic/gin": "v1.10.1",
+		"github.com/spf13/viper":   "v1.20.1",
+		"gorm.io/gorm":             "v1.30.0",
+	},
+}
+
+// 返回所选Go版本下依赖的版本号
+func (p ProjectConfig) Dep(module string) string {
+	if v, ok := dependencyVersions[p.GoVersion][module]; ok {
+		return v
+	}
+	return dependencyVersions[defaultGoVersion][module]
+}
+
 const goModTemplate = `module {{.Project.ModuleName}}
 
-go 1.20
+go {{.Project.GoVersion}}
 
 require (
-	github.com/gin-gonic/gin v1.9.1
-	github.com/spf13/viper v1.16.0
-	gorm.io/driver/mysql v1.6.0
-	gorm.io/gorm v1.25.4
+{{- if .Project.FCMEnabled}}
+	firebase.google.com/go/v4 v4.13.0
+{{- end}}
+{{- if eq .Project.AuthMode "session"}}
+	github.com/gin-contrib/sessions v0.0.5
+{{- end}}
+{{- if gt .Project.RequestTimeoutSec 0}}
+	github.com/gin-contrib/timeout v0.0.6
+{{- end}}
+{{- if .Project.SentryEnabled}}
+	github.com/getsentry/sentry-go v0.25.0
+{{- end}}
+{{- if .Project.SeedEnabled}}
+	github.com/bxcodec/faker/v3 v3.8.1
+{{- end}}
+{{- if .Project.AlgoliaEnabled}}
+	github.com/algolia/algoliasearch-client-go/v3 v3.31.0
+{{- end}}
+{{- if .Project.ElasticsearchEnabled}}
+	github.com/elastic/go-elasticsearch/v8 v8.11.1
+{{- end}}
+	github.com/gin-gonic/gin {{.Project.Dep "github.com/gin-gonic/gin"}}
+{{- if .Project.GenerateTests}}
+	github.com/glebarez/sqlite v1.10.0
+{{- end}}
+{{- if .Project.ConsulEnabled}}
+	github.com/hashicorp/consul/api v1.26.1
+{{- end}}
+{{- if .Project.LokiEnabled}}
+	github.com/grafana/loki-client-go v0.0.0-20230116142646-e7494d0ef70c
+	github.com/prometheus/common v0.44.0
+{{- end}}
+{{- if eq .Project.AuthMode "magiclink"}}
+	github.com/golang-jwt/jwt/v5 v5.2.1
+{{- end}}
+{{- if or .Project.QuotaEnabled .Project.ChatEnabled}}
+	github.com/gomodule/redigo v2.0.0+incompatible
+{{- end}}
+{{- if .UsesFieldType "uuid.UUID"}}
+	github.com/google/uuid v1.3.1
+{{- end}}
+{{- if .Project.ChatEnabled}}
+	github.com/gorilla/websocket v1.5.1
+{{- end}}
+{{- if .Project.TOTPEnabled}}
+	github.com/pquerna/otp v1.4.0
+{{- end}}
+{{- if .Project.EmailEnabled}}
+	github.com/sendgrid/sendgrid-go v3.14.0+incompatible
+{{- end}}
+	github.com/spf13/viper {{.Project.Dep "github.com/spf13/viper"}}
+{{- if .Project.StripeEnabled}}
+	github.com/stripe/stripe-go/v76 v76.0.0
+{{- end}}
+{{- if .Project.TwilioEnabled}}
+	github.com/twilio/twilio-go v1.15.0
+{{- end}}
+{{- if gt .Project.RateLimitRPS 0}}
+	golang.org/x/time v0.3.0
+{{- end}}
+{{- if .Project.FCMEnabled}}
+	google.golang.org/api v0.149.0
+{{- end}}
+{{- if .Project.GRPCEnabled}}
+	google.golang.org/grpc v1.59.0
+{{- end}}
+{{- if eq .Project.DBDriver "postgres"}}
+	gorm.io/driver/postgres v1.4.6
+{{- else}}
+	github.com/go-sql-driver/mysql v1.8.1
+	gorm.io/driver/mysql {{.Project.Dep "gorm.io/driver/mysql"}}
+{{- end}}
+	gorm.io/gorm {{.Project.Dep "gorm.io/gorm"}}
+{{- if .Project.DatadogEnabled}}
+	gopkg.in/DataDog/dd-trace-go.v1 v1.58.0
+{{- end}}
+{{- if or .Project.MetricsEnabled .Project.TracingBackend}}
+	go.opentelemetry.io/otel v1.21.0
+{{- end}}
+{{- if .Project.MetricsEnabled}}
+	go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc v0.44.0
+	go.opentelemetry.io/otel/metric v1.21.0
+	go.opentelemetry.io/otel/sdk/metric v1.21.0
+{{- end}}
+{{- if .Project.TracingBackend}}
+	go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin v0.46.1
+	go.opentelemetry.io/otel/sdk v1.21.0
+{{- end}}
+{{- if eq .Project.TracingBackend "otlp"}}
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc v1.21.0
+{{- end}}
+{{- if eq .Project.TracingBackend "jaeger"}}
+	go.opentelemetry.io/otel/exporters/jaeger v1.17.0
+{{- end}}
+{{- if eq .Project.TracingBackend "zipkin"}}
+	go.opentelemetry.io/otel/exporters/zipkin v1.21.0
+{{- end}}
 )
 
 require (
@@ -800,8 +3532,8 @@ require (
 	go.uber.org/multierr v1.10.0 // indirect
 	go.uber.org/zap v1.26.0 // indirect
 	golang.org/x/arch v0.3.0 // indirect
-	golang.org/x/crypto v0.12.0 // indirect
-	golang.org/x/net v0.14.0 // indirect
+	golang.org/x/crypto v0.12.0{{if not .Project.AutoCertEnabled}} // indirect{{end}}
+	golang.org/x/net v0.14.0{{if not .Project.HTTP2Push}} // indirect{{end}}
 	golang.org/x/sys v0.11.0 // indirect
 	golang.org/x/text v0.12.0 // indirect
 	google.golang.org/protobuf v1.30.0 // indirect
@@ -817,18 +3549,106 @@ const readmeTemplate = `# {{.Project.ProjectName}}
 ## 项目结构
 
 - **cmd/main.go**: 应用入口点
+- **cmd/migrate**: 基于 GORM AutoMigrate 的迁移命令，--action up|down|status，--dry-run 只打印 SQL
 - **pkg/api**: API服务器实现
 - **pkg/config**: 配置管理
 - **pkg/database**: 数据库连接
 - **pkg/models**: 数据模型
 - **pkg/handlers**: 请求处理程序
-- **pkg/middlewares**: 中间件
+- **pkg/middlewares**: 中间件{{if .Project.RBAC}}，RBAC 按登录用户的 role 列授权，新用户默认为 user，需要直接修改数据库为用户分配 admin 等角色{{end}}
+{{- if .Project.CQRS}}
+- **pkg/commands**: 写操作（创建、更新、删除），使用完整的 GORM 模型和主库
+- **pkg/queries**: 读操作，列表和详情接口返回只含展示字段的 <模型>View，查询 DB_READ_HOST 只读副本（为空时使用主库）
+{{- end}}
+{{- if .Project.EventSourcing}}
+- **pkg/events**: 事件溯源，每个模型的 Create/Update 命令和 Created/Updated 事件；创建、更新接口在同一事务中把事件追加到 event_store 表，GET /api/v1/<模型复数>/:id/events 按顺序返回
+{{- end}}
+{{- if .Project.GRPCEnabled}}
+- **pkg/grpc**: gRPC 健康检查 (grpc.health.v1)，监听 GRPC_PORT，可用 grpc-health-probe -addr=localhost:{{.Project.GRPCPort}} 检查
+{{- end}}
+{{- if .Project.ConsulEnabled}}
+- **pkg/discovery**: Consul 服务注册，启动时注册并以 /health 作为健康检查，退出时注销
+{{- end}}
+{{- if .Project.StripeEnabled}}
+- **pkg/payments**: Stripe 支付 (STRIPE_SECRET_KEY、STRIPE_WEBHOOK_SECRET)：POST /payments/checkout 按 {{.PaymentModel.Name}} 的 price 创建结账页面，POST /payments/webhook 校验签名后处理事件，POST /payments/refund/:id 退款
+{{- end}}
+{{- if .Project.EmailEnabled}}
+- **pkg/notifications**: 通过 SendGrid 发送邮件 (SENDGRID_API_KEY、FROM_EMAIL)，SendWelcomeEmail、SendPasswordResetEmail、SendVerificationEmail 使用 templates/email 中的 HTML 模板
+{{- end}}
+{{- if .Project.FCMEnabled}}
+- **pkg/notifications/fcm.go**: Firebase Cloud Messaging 推送 (FIREBASE_CREDENTIALS_FILE)，POST /notifications/send 创建 Notification 记录，其 BeforeCreate 钩子发送推送并记录 SentAt
+{{- end}}
+{{- if .Project.TwilioEnabled}}
+- **pkg/notifications/sms.go**: 通过 Twilio 发送短信 (TWILIO_ACCOUNT_SID、TWILIO_AUTH_TOKEN、TWILIO_FROM_NUMBER)，POST /notifications/sms 发送短信并记录到 SMSLog
+{{- end}}
+{{- if .Project.NotificationCenter}}
+- **pkg/notifications/publisher.go**: 通知中心，新建的 Notification 投递到已注册的渠道 (NOTIFICATION_WEBHOOK_URL{{if .Project.SlackEnabled}}、Slack{{end}}{{if .Project.FCMEnabled}}、FCM 主题 user-<UserID>{{end}})；其他模型的钩子可以创建 Notification 记录，或直接调用 notifications.Publish。POST /notifications/bulk-read 批量标记已读，GET /notifications/unread-count?user_id= 返回未读数
+{{- end}}
+{{- if .Project.ElasticsearchEnabled}}
+- **pkg/search**: Elasticsearch 全文搜索 (ELASTICSEARCH_URL)，声明 @search 的模型创建、更新、删除后同步到与表同名的索引，GET /api/v1/<模型复数>/search?q=...&fields=a,b 在索引中搜索
+{{- end}}
+{{- if .Project.AlgoliaEnabled}}
+- **pkg/search**: Algolia 全文搜索 (ALGOLIA_APP_ID、ALGOLIA_API_KEY、ALGOLIA_INDEX_PREFIX)，声明 @search 的模型创建、更新、删除后同步到 <前缀><表名> 索引，GET /api/v1/<模型复数>/search?q=...&fields=a,b 在 Algolia 中搜索后从数据库读取最新的记录
+{{- end}}
+{{- if .Project.SlackEnabled}}
+- **pkg/notifications/slack.go**: 通过 Slack incoming webhook (SLACK_WEBHOOK_URL) 发送消息，模型声明 @slack 时创建记录后自动通知
+{{- end}}
+{{- if .Project.AdminPanelEnabled}}
+- **pkg/handlers/admin.go**: /admin 管理接口 (ADMIN_USER/ADMIN_PASS Basic 认证)：GET /admin/models 各模型记录数，POST /admin/<模型复数>/purge 永久删除软删除的记录，GET /admin/metrics 进程内计数器
+{{- end}}
+{{- if .Project.ChatEnabled}}
+- **pkg/chat**: 聊天室，GET /ws/chat/:roomId 建立 WebSocket 连接，发送的文本消息保存到 Message 并通过 Redis (REDIS_ADDR) Pub/Sub 推送给所有实例上同一房间的连接{{if eq .Project.AuthMode "magiclink"}}，连接时需要 Authorization: Bearer 请求头{{end}}
+{{- end}}
+{{- if .Project.QuotaEnabled}}
+- **pkg/quota**: 每个登录用户的月度请求配额，计数保存在 Redis (REDIS_ADDR)，超过 USER_MONTHLY_QUOTA 时返回 429
+{{- end}}
 - **api**: OpenAPI规范文件
 - **migrations**: 数据库迁移脚本
-- **docs**: 文档
+{{- if .Project.KubernetesEnabled}}
+- **k8s**: Kubernetes 清单，kubectl apply -f k8s/{{if .Project.IstioEnabled}}；包含 Istio VirtualService (3 次重试、5 秒超时) 和 DestinationRule，需要集群已安装 Istio{{end}}
+{{- end}}
+{{- if or .Project.KubernetesEnabled .Project.HelmEnabled}}
+- **skaffold.yaml**: 本地 Kubernetes 开发，skaffold dev 在代码变更后自动重新构建和部署
+{{- end}}
+{{- if .Project.ArgoCD}}
+- **argocd/application.yaml**: ArgoCD 应用，推送到 {{.Project.GitRepoURL}} 后 kubectl apply -f argocd/application.yaml 即可自动同步
+{{- end}}
+{{- if .Project.HelmEnabled}}
+- **helm/{{.Project.ProjectName}}**: Kubernetes Helm chart，helm install {{.Project.ProjectName}} ./helm/{{.Project.ProjectName}} --set secrets.DB_PASSWORD=...
+{{- end}}
+{{- if eq .Project.IaCDriver "terraform"}}
+- **terraform**: AWS 基础设施 (RDS、ECS Fargate、ALB)，terraform apply 时需提供 vpc_id、子网、镜像和数据库密码
+{{- else if eq .Project.IaCDriver "pulumi"}}
+- **infra**: AWS 基础设施的 Pulumi 程序 (独立 Go 模块)，pulumi up 前需要 go mod tidy 并通过 pulumi config 设置 aws:region、vpcId、publicSubnetIds、privateSubnetIds、image 和 dbPassword (--secret)
+{{- end}}
+{{- if .Project.GithubActionsEnabled}}
+- **.github/workflows**: CI (推送和 PR 时运行测试) 与发布 (推送 v* 标签时构建并创建 GitHub Release)
+- **.github/ISSUE_TEMPLATE**、**.github/PULL_REQUEST_TEMPLATE.md**: 问题报告、功能建议表单和 PR 检查清单
+{{- end}}
+- **docs**: 文档{{if .Project.SwaggerUI}}，swagger-ui 目录内嵌到二进制中，启动后访问 /docs/ 查看 API 文档{{end}}
 
 ## 如何运行
 
 1. 创建数据库:
    bash
-   createdb {{.Project.ProjectName}}`
+   createdb {{.Project.ProjectName}}
+
+2. 下载依赖并创建表 (首次运行会执行 go mod tidy 生成 go.sum，make migrate-dry 只打印将执行的 SQL):
+   bash
+   make migrate
+
+3. 启动{{if eq .Project.AuthMode "session"}} (先在 .env 中把 SESSION_SECRET 设为随机值，为空时拒绝启动){{else if eq .Project.AuthMode "magiclink"}} (先在 .env 中把 JWT_SECRET 设为随机值，为空时拒绝启动){{end}}:
+   bash
+   make run
+{{- if .Project.GenerateCommunityFiles}}
+
+## 参与贡献
+
+欢迎提交 issue 和 PR，流程和编码规范见 [CONTRIBUTING.md](CONTRIBUTING.md)，请遵守[行为准则](CODE_OF_CONDUCT.md)。
+{{- end}}
+{{- if and .Project.License (ne .Project.License "none")}}
+
+## License
+
+{{if eq .Project.License "Proprietary"}}专有软件{{else}}{{.Project.License}}{{end}}，详见 [LICENSE](LICENSE)。Copyright © {{.Project.CopyrightOwner}}
+{{- end}}`
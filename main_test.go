@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestToJSONTag(t *testing.T) {
+	tests := []struct {
+		name  string
+		style string
+		want  string
+	}{
+		{name: "FirstName", style: "snake_case", want: "first_name"},
+		{name: "FirstName", style: "camelCase", want: "firstName"},
+		{name: "FirstName", style: "PascalCase", want: "FirstName"},
+		{name: "UserID", style: "snake_case", want: "user_id"},
+		{name: "SMSLog", style: "snake_case", want: "sms_log"},
+		{name: "URL", style: "camelCase", want: "url"},
+		{name: "APIKey", style: "camelCase", want: "apiKey"},
+		{name: "TOTPEnabled", style: "camelCase", want: "totpEnabled"},
+		{name: "UserID", style: "camelCase", want: "userID"},
+		{name: "", style: "camelCase", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.style+"/"+tt.name, func(t *testing.T) {
+			if got := toJSONTag(tt.name, tt.style); got != tt.want {
+				t.Errorf("toJSONTag(%q, %q) = %q, want %q", tt.name, tt.style, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseModelsJSONTagStyle(t *testing.T) {
+	tests := []struct {
+		style       string
+		wantJSONTag string
+	}{
+		{style: "snake_case", wantJSONTag: "first_name"},
+		{style: "camelCase", wantJSONTag: "firstName"},
+		{style: "PascalCase", wantJSONTag: "FirstName"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.style, func(t *testing.T) {
+			models := parseModels("User\nfirstName string", tt.style)
+			if len(models) != 1 || len(models[0].Fields) != 1 {
+				t.Fatalf("parseModels() = %+v, want one model with one field", models)
+			}
+			f := models[0].Fields[0]
+			if f.Name != "FirstName" {
+				t.Errorf("Name = %q, want %q", f.Name, "FirstName")
+			}
+			if f.JsonTag != tt.wantJSONTag {
+				t.Errorf("JsonTag = %q, want %q", f.JsonTag, tt.wantJSONTag)
+			}
+			// 列名不受 JSON 标签风格影响
+			if f.GormTag != "column:first_name" {
+				t.Errorf("GormTag = %q, want %q", f.GormTag, "column:first_name")
+			}
+		})
+	}
+}
+
+func TestAddTOTPFields(t *testing.T) {
+	tests := []struct {
+		style       string
+		wantJSONTag string
+	}{
+		{style: "snake_case", wantJSONTag: "totp_enabled"},
+		{style: "camelCase", wantJSONTag: "totpEnabled"},
+		{style: "PascalCase", wantJSONTag: "TOTPEnabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.style, func(t *testing.T) {
+			data := TemplateData{
+				Project: ProjectConfig{JSONTagStyle: tt.style},
+				Models:  parseModels("User\nemail string\npassword string\n\nPost\ntitle string", tt.style),
+			}
+			data.AuthUser = &AuthUser{Model: data.Models[0]}
+			if err := addTOTPFields(&data); err != nil {
+				t.Fatal(err)
+			}
+
+			user := data.Models[0]
+			secret, enabled := user.field("TOTPSecret"), user.field("TOTPEnabled")
+			if secret == nil || enabled == nil {
+				t.Fatalf("User fields = %+v, want TOTPSecret and TOTPEnabled", user.Fields)
+			}
+			if secret.JsonTag != "-" || secret.GormTag != "column:totp_secret" {
+				t.Errorf("TOTPSecret tags = %q, %q", secret.JsonTag, secret.GormTag)
+			}
+			if enabled.JsonTag != tt.wantJSONTag || enabled.GormTag != "column:totp_enabled" {
+				t.Errorf("TOTPEnabled tags = %q, %q, want %q, %q", enabled.JsonTag, enabled.GormTag, tt.wantJSONTag, "column:totp_enabled")
+			}
+			if secret.Order != 2 || enabled.Order != 3 {
+				t.Errorf("Order = %d, %d, want 2, 3", secret.Order, enabled.Order)
+			}
+			if len(data.AuthUser.Model.Fields) != len(user.Fields) {
+				t.Error("AuthUser.Model was not updated")
+			}
+			if len(data.Models[1].Fields) != 1 {
+				t.Errorf("Post fields = %+v, want unchanged", data.Models[1].Fields)
+			}
+		})
+	}
+}
+
+func TestAddTOTPFieldsRejectsClash(t *testing.T) {
+	data := TemplateData{
+		Project: ProjectConfig{JSONTagStyle: defaultJSONTagStyle},
+		Models:  parseModels("User\nemail string\npassword string\ntotpEnabled bool", defaultJSONTagStyle),
+	}
+	data.AuthUser = &AuthUser{Model: data.Models[0]}
+
+	err := addTOTPFields(&data)
+	want := "模型 User: 字段 TotpEnabled 与两步验证字段 TOTPEnabled 冲突"
+	if err == nil || err.Error() != want {
+		t.Errorf("addTOTPFields() error = %v, want %q", err, want)
+	}
+}
+
+func TestParseModelsCRLF(t *testing.T) {
+	lf := "User\nname string required\nemail string gorm:\"unique\"\n@perm DELETE admin\n\nPost\ntitle string maxlen:200\n"
+	crlf := strings.ReplaceAll(lf, "\n", "\r\n")
+
+	want := parseModels(lf, defaultJSONTagStyle)
+	got := parseModels(crlf, defaultJSONTagStyle)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseModels(CRLF) = %+v, want %+v", got, want)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d models, want 2", len(got))
+	}
+}
+
+// Windows 浏览器提交的表单生成的项目与 \n 换行的完全一致
+func TestGenerateCRLFModelsMatchesLF(t *testing.T) {
+	generate := func(models string) *MemFileSystem {
+		t.Helper()
+		fields := map[string]string{
+			"project_name": "blog",
+			"module_name":  "example.com/blog",
+			"port":         "8080",
+			"models":       models,
+		}
+		data, err := templateDataFromFields(fields)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files := NewMemFileSystem()
+		if err := generateProjectStructure(files, data); err != nil {
+			t.Fatal(err)
+		}
+		return files
+	}
+
+	lf := "User\nname string required\nage int\n\nPost\ntitle string\nbody string"
+	want := generate(lf)
+	got := generate(strings.ReplaceAll(lf, "\n", "\r\n"))
+	if !reflect.DeepEqual(got.Paths(), want.Paths()) {
+		t.Fatalf("files = %v, want %v", got.Paths(), want.Paths())
+	}
+	for _, path := range want.Paths() {
+		if !bytes.Equal(got.Files[path], want.Files[path]) {
+			t.Errorf("%s differs between CRLF and LF input", path)
+		}
+	}
+}
+
+func TestAddRoleFieldRejectsClash(t *testing.T) {
+	data := TemplateData{
+		Project: ProjectConfig{JSONTagStyle: defaultJSONTagStyle},
+		Models:  parseModels("User\nemail string\npassword string\nrole string", defaultJSONTagStyle),
+	}
+	data.AuthUser = &AuthUser{Model: data.Models[0]}
+
+	err := addRoleField(&data)
+	want := "模型 User: 字段 Role 与 RBAC 角色字段 Role 冲突"
+	if err == nil || err.Error() != want {
+		t.Errorf("addRoleField() error = %v, want %q", err, want)
+	}
+}
+
+// Web 服务器未设置 ALLOW_GO_SUM=1 时拒绝 go_sum，避免请求触发 go mod tidy
+func TestGoSumRequiresOptIn(t *testing.T) {
+	defer func(allowed bool) { goSumAllowed = allowed }(goSumAllowed)
+	fields := map[string]string{
+		"project_name": "blog",
+		"module_name":  "example.com/blog",
+		"port":         "8080",
+		"models":       "Post\ntitle string",
+		"go_sum":       "on",
+	}
+
+	goSumAllowed = false
+	if _, err := templateDataFromFields(fields); err == nil || !strings.Contains(err.Error(), "ALLOW_GO_SUM=1") {
+		t.Errorf("templateDataFromFields() error = %v, want ALLOW_GO_SUM hint", err)
+	}
+
+	goSumAllowed = true
+	data, err := templateDataFromFields(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !data.Project.GoSum {
+		t.Error("GoSum = false, want true")
+	}
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// 模型表的建表脚本，列类型与 GORM AutoMigrate 在 MySQL 上的结果一致，
+// 必填字段额外加上 NOT NULL，与接口的必填校验保持一致。可用于手动建表或交给迁移工具管理
+
+// 读取 gorm 标签中的设置，如 gormTagSetting("column:name;size:64", "size") 返回 "64", true
+func gormTagSetting(tag, key string) (string, bool) {
+	for _, part := range strings.Split(tag, ";") {
+		name, value, _ := strings.Cut(strings.TrimSpace(part), ":")
+		if strings.EqualFold(name, key) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// 迁移脚本中的列类型。GORM 为有默认值、索引或 size 的字符串使用 varchar，
+// 否则使用 longtext（MySQL 的 TEXT 列不能有默认值和普通索引）；必填字符串的默认值为空字符串，同样使用 varchar
+func migrationColumnType(f ModelField) string {
+	if t, ok := gormTagSetting(f.GormTag, "type"); ok {
+		return t
+	}
+	if f.Type == "string" {
+		if size, ok := gormTagSetting(f.GormTag, "size"); ok {
+			return "varchar(" + size + ")"
+		}
+		if f.Default != "" || f.Required || f.Unique() || hasGormIndex(f.GormTag) {
+			return "varchar(191)"
+		}
+	}
+	return sqlColumnType(f.Type)
+}
+
+func hasGormIndex(tag string) bool {
+	_, index := gormTagSetting(tag, "index")
+	_, unique := gormTagSetting(tag, "uniqueIndex")
+	return index || unique
+}
+
+// MySQL 的索引名最长 64 个字符，超过 60 个字符的索引名截断后追加 4 位哈希，避免截断后重名
+const maxIndexNameLength = 60
+
+// 索引名 idx_<table>_<columns>，未截断时与 GORM 的默认索引名相同
+func sanitizeIndexName(table, columns string) string {
+	name := "idx_" + table + "_" + columns
+	if len(name) <= maxIndexNameLength {
+		return name
+	}
+	sum := sha1.Sum([]byte(name))
+	return name[:maxIndexNameLength] + hex.EncodeToString(sum[:])[:4]
+}
+
+// 单列索引的 gorm 设置，索引名被截断时显式写出，保证 AutoMigrate 与迁移脚本一致
+func indexTag(setting, table, column string) string {
+	name := sanitizeIndexName(table, column)
+	if name == "idx_"+table+"_"+column {
+		return setting
+	}
+	return setting + ":" + name
+}
+
+// 模型结构体中的 gorm 标签：未命名的 index、uniqueIndex 换成 indexTag 的结果
+func (f ModelField) StructGormTag(table string) string {
+	parts := strings.Split(f.GormTagWithDefault(), ";")
+	for i, part := range parts {
+		for _, setting := range []string{"index", "uniqueIndex"} {
+			if strings.EqualFold(strings.TrimSpace(part), setting) {
+				parts[i] = indexTag(setting, table, f.ColumnName())
+			}
+		}
+	}
+	return strings.Join(parts, ";")
+}
+
+// 导出配置时把 StructGormTag 写出的索引名还原为未命名的 index、uniqueIndex
+func restoreIndexTag(tag, table, column string) string {
+	parts := strings.Split(tag, ";")
+	for i, part := range parts {
+		for _, setting := range []string{"index", "uniqueIndex"} {
+			if part == indexTag(setting, table, column) {
+				parts[i] = setting
+			}
+		}
+	}
+	return strings.Join(parts, ";")
+}
+
+// 迁移脚本中字段声明的索引，同名索引合并为联合索引，未命名的索引使用 sanitizeIndexName
+func migrationIndexes(table string, fields []ModelField) []string {
+	var names []string
+	columns := map[string][]string{}
+	unique := map[string]bool{}
+	for _, f := range fields {
+		for _, setting := range []string{"index", "uniqueIndex"} {
+			value, ok := gormTagSetting(f.GormTag, setting)
+			if !ok {
+				continue
+			}
+			name, options, _ := strings.Cut(value, ",")
+			if name == "" {
+				name = sanitizeIndexName(table, f.ColumnName())
+			}
+			if _, seen := columns[name]; !seen {
+				names = append(names, name)
+			}
+			columns[name] = append(columns[name], "`"+f.ColumnName()+"`")
+			if setting == "uniqueIndex" || strings.Contains(strings.ToLower(options), "unique") {
+				unique[name] = true
+			}
+		}
+	}
+
+	keys := make([]string, len(names))
+	for i, name := range names {
+		key := "KEY `" + name + "` (" + strings.Join(columns[name], ",") + ")"
+		if unique[name] {
+			key = "UNIQUE " + key
+		}
+		keys[i] = key
+	}
+	return keys
+}
+
+// 字段是否有唯一约束
+func (f ModelField) Unique() bool {
+	_, ok := gormTagSetting(f.GormTag, "unique")
+	return ok
+}
+
+// 模型结构体中的 gorm 标签，声明了默认值时追加 default 设置
+func (f ModelField) GormTagWithDefault() string {
+	if f.Default == "" {
+		return f.GormTag
+	}
+	if f.GormTag == "" {
+		return "default:" + f.Default
+	}
+	return f.GormTag + ";default:" + f.Default
+}
+
+// 默认值在 SQL 中的字面量，字符串加单引号
+func sqlDefault(f ModelField) string {
+	if f.Type == "string" {
+		return "'" + strings.ReplaceAll(f.Default, "'", "''") + "'"
+	}
+	return f.Default
+}
+
+// 列类型之后的约束：必填字段 NOT NULL，并以声明的默认值或类型零值作为默认值
+func migrationColumnOptions(f ModelField) string {
+	var options []string
+	if f.Required {
+		options = append(options, "NOT NULL")
+	}
+	if f.Default != "" {
+		options = append(options, "DEFAULT "+sqlDefault(f))
+	} else if zero := sqlZeroDefault(f.Type); f.Required && zero != "" {
+		options = append(options, "DEFAULT "+zero)
+	}
+	if f.Unique() {
+		options = append(options, "UNIQUE")
+	}
+	if len(options) == 0 {
+		return ""
+	}
+	return " " + strings.Join(options, " ")
+}
+
+// 必填字段的默认值，时间和 UUID 没有合适的零值，只加 NOT NULL
+func sqlZeroDefault(goType string) string {
+	switch goType {
+	case "string":
+		return "''"
+	case "int", "int32", "int64", "uint", "uint32", "uint64", "float32", "float64":
+		return "0"
+	}
+	return ""
+}
+
+// 默认值在 Go 代码中的字面量，数值和布尔值已由 ValidateModel 校验
+func goDefault(f ModelField) string {
+	if f.Type == "string" {
+		return strconv.Quote(f.Default)
+	}
+	return f.Default
+}
+
+// 是否有字段声明了默认值，决定是否生成 New<Model> 构造函数
+func (m Model) HasDefaults() bool {
+	for _, f := range m.Fields {
+		if f.Default != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// 默认值需要同时写入 gorm 标签、SQL 和 Go 代码，返回不合法的原因
+func fieldDefaultProblem(f ModelField) string {
+	var err error
+	switch f.Type {
+	case "string":
+		if strings.ContainsAny(f.Default, ";\"`\\") {
+			return "不能包含 ; \" ` \\"
+		}
+	case "int", "int32", "int64":
+		_, err = strconv.ParseInt(f.Default, 10, 64)
+	case "uint", "uint32", "uint64":
+		_, err = strconv.ParseUint(f.Default, 10, 64)
+	case "float32", "float64":
+		_, err = strconv.ParseFloat(f.Default, 64)
+	case "bool":
+		if f.Default != "true" && f.Default != "false" {
+			return "必须是 true 或 false"
+		}
+	default:
+		return "该类型不支持默认值"
+	}
+	if err != nil {
+		return "不是合法的 " + f.Type
+	}
+	return ""
+}
+
+const modelMigrationTemplate = `{{$table := print .Project.TablePrefix .Model.SnakeName}}-- {{.Model.Name}} 表
+CREATE TABLE IF NOT EXISTS ` + "`{{.Project.TablePrefix}}{{.Model.SnakeName}}`" + ` (
+  ` + "`id`" + ` {{.Model.IDSQLType}} NOT NULL{{if .Model.NumericID}} AUTO_INCREMENT{{end}},
+{{- range .Model.OrderedFields}}
+  ` + "`{{.ColumnName}}`" + ` {{migrationColumnType .}}{{migrationColumnOptions .}},
+{{- end}}
+  ` + "`created_at`" + ` datetime(3) NULL,
+  ` + "`updated_at`" + ` datetime(3) NULL,
+  ` + "`deleted_at`" + ` datetime(3) NULL,
+  PRIMARY KEY (` + "`id`" + `),
+{{- range migrationIndexes $table .Model.OrderedFields}}
+  {{.}},
+{{- end}}
+  KEY ` + "`{{sanitizeIndexName $table \"deleted_at\"}}`" + ` (` + "`deleted_at`" + `)
+) ENGINE=InnoDB DEFAULT CHARSET={{.Project.MySQLCharset}} COLLATE={{.Project.MySQLCollation}};
+`
+
+// cmd/migrate/main.go：用 GORM AutoMigrate 创建或更新所有模型的表，不依赖 golang-migrate
+const migrateCommandTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"{{.Project.ModuleName}}/pkg/config"
+	"{{.Project.ModuleName}}/pkg/database"
+{{- if .Project.EventSourcing}}
+	"{{.Project.ModuleName}}/pkg/events"
+{{- end}}
+	"{{.Project.ModuleName}}/pkg/models"
+)
+
+// 所有模型，down 时按相反顺序删除
+var allModels = []interface{}{
+{{- range .Models}}
+	&models.{{.Name}}{},
+{{- if .VersionHistory}}
+	&models.{{.Name}}History{},
+{{- end}}
+{{- end}}
+{{- if .Project.EventSourcing}}
+	&events.StoredEvent{},
+{{- end}}
+}
+
+func main() {
+	action := flag.String("action", "up", "up: 创建或更新表结构，down: 删除所有表，status: 查看表和列是否已创建")
+	dryRun := flag.Bool("dry-run", false, "只打印 up 或 down 将执行的 SQL，不修改数据库")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Error loading config: %v", err)
+	}
+
+	db, err := database.InitDB(cfg)
+	if err != nil {
+		log.Fatalf("Error initializing database: %v", err)
+	}
+
+	switch *action {
+	case "up":
+		tx := db
+		if *dryRun {
+			// DryRun 时 GORM 仍会查询现有表结构，并自行打印而不执行变更语句
+			tx = db.Session(&gorm.Session{DryRun: true, Logger: logger.Default.LogMode(logger.Silent)})
+		}
+		if err := tx.AutoMigrate(allModels...); err != nil {
+			log.Fatalf("Error migrating: %v", err)
+		}
+	case "down":
+		tx := db
+		if *dryRun {
+			tx = db.Session(&gorm.Session{DryRun: true, Logger: sqlPrinter{}})
+		}
+		for i := len(allModels) - 1; i >= 0; i-- {
+			if err := tx.Migrator().DropTable(allModels[i]); err != nil {
+				log.Fatalf("Error dropping table: %v", err)
+			}
+		}
+	case "status":
+		if err := printStatus(db); err != nil {
+			log.Fatalf("Error reading status: %v", err)
+		}
+		return
+	default:
+		log.Fatalf("Unknown action %q, expected up, down or status", *action)
+	}
+	if !*dryRun {
+		log.Printf("%s: done", *action)
+	}
+}
+
+// 列出每个模型的表是否存在，以及已存在的表缺少哪些列
+func printStatus(db *gorm.DB) error {
+	migrator := db.Migrator()
+	for _, model := range allModels {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return err
+		}
+		table := stmt.Schema.Table
+		if !migrator.HasTable(model) {
+			fmt.Printf("%-30s missing\n", table)
+			continue
+		}
+		var missing []string
+		for _, column := range stmt.Schema.DBNames {
+			if !migrator.HasColumn(model, column) {
+				missing = append(missing, column)
+			}
+		}
+		if len(missing) > 0 {
+			fmt.Printf("%-30s missing columns %v\n", table, missing)
+		} else {
+			fmt.Printf("%-30s up to date\n", table)
+		}
+	}
+	return nil
+}
+
+// DropTable 在 DryRun 下不会打印 SQL，由这个日志把将执行的语句打印到标准输出，跳过查询语句
+type sqlPrinter struct{}
+
+func (p sqlPrinter) LogMode(logger.LogLevel) logger.Interface    { return p }
+func (sqlPrinter) Info(context.Context, string, ...interface{})  {}
+func (sqlPrinter) Warn(context.Context, string, ...interface{})  {}
+func (sqlPrinter) Error(context.Context, string, ...interface{}) {}
+
+func (sqlPrinter) Trace(_ context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	sql, _ := fc()
+	verb, _, _ := strings.Cut(sql, " ")
+	switch strings.ToUpper(verb) {
+	case "SELECT", "SHOW", "PRAGMA":
+		return
+	}
+	fmt.Println(sql + ";")
+}
+`
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 单仓库多服务：MonorepoEnabled 时项目生成到 <ProjectName>/ 子目录，
+// ZIP 根目录额外生成 go.work 和统一构建、测试所有服务的 Makefile。
+// POST /generate 也接受 JSON 形式的多个项目，一次生成整个工作区
+
+// 把生成的文件写入 fs 下的子目录
+type subdirFileSystem struct {
+	fs  FileSystem
+	dir string
+}
+
+func (s subdirFileSystem) MkdirAll(dir string) error {
+	return s.fs.MkdirAll(path.Join(s.dir, dir))
+}
+
+func (s subdirFileSystem) WriteFile(filePath string, content []byte) error {
+	return s.fs.WriteFile(path.Join(s.dir, filePath), content)
+}
+
+// go.work 和工作区 Makefile 的模板数据
+type workspaceData struct {
+	Projects  []ProjectConfig
+	GoVersion string // 所有服务中最高的 Go 版本
+}
+
+func newWorkspaceData(projects []ProjectConfig) workspaceData {
+	ws := workspaceData{Projects: projects}
+	versions := make([]string, len(projects))
+	for i, p := range projects {
+		versions[i] = p.GoVersion
+	}
+	// supportedGoVersions 都是 1.x，按字符串排序即可比较
+	sort.Strings(versions)
+	if len(versions) > 0 {
+		ws.GoVersion = versions[len(versions)-1]
+	}
+	return ws
+}
+
+// 在 ZIP 根目录生成 go.work 和工作区 Makefile
+func generateWorkspace(fs FileSystem, projects []ProjectConfig, delims TemplateDelims) error {
+	ws := newWorkspaceData(projects)
+	if err := generateBuiltinFile(fs, "go.work", "go.work.tmpl", delims, ws); err != nil {
+		return err
+	}
+	return generateBuiltinFile(fs, "Makefile", "workspace_Makefile.tmpl", delims, ws)
+}
+
+// POST /generate 的 JSON 请求体，每个项目是一组 /generate 表单字段（含 models），
+// 与 /export-config 返回的格式相同
+type multiProjectRequest struct {
+	Projects []map[string]string `json:"projects"`
+}
+
+// 每个项目生成到同名子目录，各自有 go.mod、模型和处理器，根目录共用 go.work 和 Makefile
+func generateMultipleProjects(c *gin.Context) {
+	var req multiProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求体不是合法的 JSON: " + err.Error()})
+		return
+	}
+	if len(req.Projects) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "projects 不能为空"})
+		return
+	}
+
+	projects := make([]TemplateData, len(req.Projects))
+	configs := make([]ProjectConfig, len(req.Projects))
+	seen := make(map[string]bool)
+	for i, fields := range req.Projects {
+		data, err := templateDataFromFields(fields)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("第 %d 个项目%v", i+1, err)})
+			return
+		}
+		name := data.Project.ProjectName
+		if seen[name] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("项目名称 %q 重复，每个项目生成到同名子目录", name)})
+			return
+		}
+		seen[name] = true
+		// 工作区文件在这里统一生成，各项目不再单独生成 go.work
+		data.Project.MonorepoEnabled = false
+		projects[i] = data
+		configs[i] = data.Project
+	}
+
+	files := NewMemFileSystem()
+	for _, data := range projects {
+		if err := generateProjectStructure(subdirFileSystem{fs: files, dir: data.Project.ProjectName}, data); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "无法生成项目 " + data.Project.ProjectName + ": " + err.Error()})
+			return
+		}
+	}
+	if err := generateWorkspace(files, configs, projects[0].Delims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "无法生成工作区: " + err.Error()})
+		return
+	}
+
+	c.Header("Content-Description", "File Transfer")
+	c.Header("Content-Disposition", "attachment; filename=workspace.zip")
+	c.Header("Content-Type", "application/zip")
+	c.Status(http.StatusOK)
+
+	if err := files.WriteZip(c.Writer); err != nil {
+		log.Printf("无法创建ZIP文件: %v", err)
+		c.Abort()
+	}
+}
+
+const goWorkTemplate = `go {{.GoVersion}}
+
+use (
+{{- range .Projects}}
+	./{{.ProjectName}}
+{{- end}}
+)
+`
+
+const workspaceMakefileTemplate = `.PHONY: build test tidy sync
+
+# 工作区中的所有服务，每个服务是独立的 Go 模块
+MODULES := {{range $i, $p := .Projects}}{{if $i}} {{end}}{{$p.ProjectName}}{{end}}
+
+# 依次调用各服务的 Makefile，任一服务失败即停止
+build:
+	@for m in $(MODULES); do $(MAKE) -C $$m build || exit 1; done
+
+test:
+	@for m in $(MODULES); do $(MAKE) -C $$m test || exit 1; done
+
+tidy:
+	@for m in $(MODULES); do (cd $$m && go mod tidy) || exit 1; done
+
+# 把工作区解析出的依赖版本同步回各服务的 go.mod
+sync:
+	go work sync
+`
+
+// 读取 go.work 中 use 的目录，去掉开头的 ./
+func parseGoWork(content []byte) []string {
+	var dirs []string
+	inUse := false
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "use (":
+			inUse = true
+		case inUse && line == ")":
+			inUse = false
+		case inUse && line != "":
+			dirs = append(dirs, strings.TrimPrefix(line, "./"))
+		case strings.HasPrefix(line, "use "):
+			dirs = append(dirs, strings.TrimPrefix(strings.TrimSpace(strings.TrimPrefix(line, "use ")), "./"))
+		}
+	}
+	return dirs
+}
+
+// 取出 dir 下的文件，路径改为相对 dir
+func subdirFiles(files map[string][]byte, dir string) map[string][]byte {
+	sub := make(map[string][]byte)
+	for name, content := range files {
+		if rel, ok := strings.CutPrefix(name, dir+"/"); ok {
+			sub[rel] = content
+		}
+	}
+	return sub
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 通知中心模板，NotificationCenter 启用时添加 Notification 模型（站内通知及其已读状态）、
+// POST /notifications/bulk-read、GET /notifications/unread-count 接口和 pkg/notifications/publisher.go，
+// Notification 创建后在 AfterCreate 中投递到已注册的 webhook、Slack、FCM 渠道
+
+// addNotificationCenterModel 为 Notification 模型补充的字段
+var notificationCenterModelFields = []injectedField{
+	{"UserID", "uint", true},
+	{"Title", "string", true},
+	{"Body", "string", false},
+	{"ReadAt", "*time.Time", false},
+	{"Type", "string", false},
+}
+
+// 模板在 Notification 的 AfterCreate 中生成的投递调用，导出配置还原钩子时去掉
+const publishNotificationCallLine = "notifications.Publish(notifications.Message{UserID: m.UserID, Type: m.Type, Title: m.Title, Body: m.Body})"
+
+// 添加 Notification 模型并在 AfterCreate 中投递；同时启用 FCM 时设备 token 改为可选，
+// 未指定设备的通知只通过 FCM 渠道推送到用户主题
+func addNotificationCenterModel(data *TemplateData) error {
+	m, err := addInjectedModel(data, notificationModelName, notificationCenterModelFields)
+	if err != nil {
+		return err
+	}
+	// 版本历史同样使用 AfterCreate 钩子
+	if m.VersionHistory || m.Hook("AfterCreate") != nil {
+		return fmt.Errorf("启用通知中心后模型 %s 不能使用 @history 或自定义 AfterCreate 钩子", m.Name)
+	}
+	if f := m.field("UserID"); f != nil && f.GormTag == defaultGormTag("user_id", "uint") {
+		f.GormTag += ";index"
+	}
+	if f := m.field("DeviceToken"); f != nil {
+		f.Required = false
+	}
+	m.PublishNotification = true
+	return nil
+}
+
+// 通知中心使用的 Notification 模型，未启用时返回 nil
+func (data TemplateData) NotificationCenterModel() *Model {
+	if !data.Project.NotificationCenter {
+		return nil
+	}
+	for i := range data.Models {
+		if data.Models[i].Name == notificationModelName {
+			return &data.Models[i]
+		}
+	}
+	return nil
+}
+
+// 模型文件是否需要导入 pkg/notifications，自定义钩子中也可以调用 notifications.Publish
+func (m Model) UsesNotifications() bool {
+	if m.SlackNotify || m.PushNotification || m.PublishNotification {
+		return true
+	}
+	for _, h := range m.Hooks {
+		if strings.Contains(h.Body, "notifications.") {
+			return true
+		}
+	}
+	return false
+}
+
+const notificationPublisherTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+{{- if .Project.FCMEnabled}}
+
+	"firebase.google.com/go/v4/messaging"
+{{- end}}
+)
+
+// Message 是投递到外部渠道的一条通知
+type Message struct {
+	UserID uint   ` + "`json:\"user_id\"`" + `
+	Type   string ` + "`json:\"type\"`" + `
+	Title  string ` + "`json:\"title\"`" + `
+	Body   string ` + "`json:\"body\"`" + `
+}
+
+// Publisher 把通知投递到一个外部渠道
+type Publisher interface {
+	Publish(ctx context.Context, msg Message) error
+}
+
+// PublisherFunc 把普通函数用作 Publisher
+type PublisherFunc func(ctx context.Context, msg Message) error
+
+func (f PublisherFunc) Publish(ctx context.Context, msg Message) error {
+	return f(ctx, msg)
+}
+
+var (
+	publishersMu sync.RWMutex
+	publishers   []Publisher
+)
+
+// RegisterPublisher 添加投递渠道，启动时调用
+func RegisterPublisher(p Publisher) {
+	publishersMu.Lock()
+	defer publishersMu.Unlock()
+	publishers = append(publishers, p)
+}
+
+// ConfigurePublishers 注册内置的投递渠道，启动时调用一次：NOTIFICATION_WEBHOOK_URL 非空时 POST 到该地址
+{{- if .Project.SlackEnabled}}；发送到 Slack{{end}}
+{{- if .Project.FCMEnabled}}；通过 FCM 推送到主题 user-<UserID>，客户端登录后订阅该主题{{end}}
+func ConfigurePublishers(webhookURL string) {
+	if webhookURL != "" {
+		RegisterPublisher(WebhookPublisher(webhookURL))
+	}
+{{- if .Project.SlackEnabled}}
+	RegisterPublisher(PublisherFunc(publishToSlack))
+{{- end}}
+{{- if .Project.FCMEnabled}}
+	RegisterPublisher(PublisherFunc(publishToFCMTopic))
+{{- end}}
+}
+
+// Publish 在后台把通知投递到所有已注册的渠道，可以在模型钩子中调用；
+// 投递失败只记录日志，不影响所在的事务
+func Publish(msg Message) {
+	publishersMu.RLock()
+	targets := append([]Publisher(nil), publishers...)
+	publishersMu.RUnlock()
+
+	go func() {
+		for _, p := range targets {
+			if err := p.Publish(context.Background(), msg); err != nil {
+				log.Printf("notifications: publishing to %T: %v", p, err)
+			}
+		}
+	}()
+}
+
+// WebhookPublisher 把通知以 JSON POST 到 url，非 2xx 响应视为失败
+func WebhookPublisher(url string) Publisher {
+	return PublisherFunc(func(ctx context.Context, msg Message) error {
+		payload, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("webhook: unexpected status %d", resp.StatusCode)
+		}
+		return nil
+	})
+}
+{{- if .Project.SlackEnabled}}
+
+func publishToSlack(ctx context.Context, msg Message) error {
+	return PostMessage(fmt.Sprintf("*%s* (user %d)\n%s", msg.Title, msg.UserID, msg.Body))
+}
+{{- end}}
+{{- if .Project.FCMEnabled}}
+
+func publishToFCMTopic(ctx context.Context, msg Message) error {
+	topic := fmt.Sprintf("user-%d", msg.UserID)
+	if fcmClient == nil {
+		log.Printf("push topic=%s title=%q\n%s", topic, msg.Title, msg.Body)
+		return nil
+	}
+
+	_, err := fcmClient.Send(ctx, &messaging.Message{
+		Topic: topic,
+		Notification: &messaging.Notification{
+			Title: msg.Title,
+			Body:  msg.Body,
+		},
+	})
+	return err
+}
+{{- end}}
+`
+
+const notificationCenterHandlerTemplate = `{{$m := .NotificationCenterModel}}{{with .Project.FileHeader}}{{.}}
+{{end}}package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+{{- if eq $m.IDType "uuid"}}
+	"github.com/google/uuid"
+{{- end}}
+	"gorm.io/gorm"
+
+	"{{.Project.ModuleName}}/pkg/models"
+)
+
+type bulkReadInput struct {
+	IDs []{{$m.IDGoType}} ` + "`json:\"ids\" binding:\"required,min=1,max=1000\"`" + `
+	// 非 0 时只标记属于该用户的通知
+	UserID uint ` + "`json:\"user_id\"`" + `
+}
+
+// RegisterNotificationCenterRoutes 注册 POST /bulk-read 和 GET /unread-count，
+// Notification 的增删改查接口在 /api/v1/{{$m.PluralName}}
+func RegisterNotificationCenterRoutes(rg *gin.RouterGroup, db *gorm.DB) {
+	rg.POST("/bulk-read", bulkReadNotifications(db))
+	rg.GET("/unread-count", countUnreadNotifications(db))
+}
+
+// 把未读的通知标记为已读，已读的通知保留原来的 ReadAt
+func bulkReadNotifications(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input bulkReadInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		query := db.WithContext(c.Request.Context()).Model(&models.{{$m.Name}}{}).
+			Where("id IN ? AND read_at IS NULL", input.IDs)
+		if input.UserID != 0 {
+			query = query.Where("user_id = ?", input.UserID)
+		}
+		// 批量更新不经过模型钩子，BeforeUpdate 的字段校验只适用于单条记录
+		now := time.Now()
+		result := query.UpdateColumns(map[string]interface{}{"read_at": now, "updated_at": now})
+		if result.Error != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": result.Error.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"updated": result.RowsAffected})
+	}
+}
+
+// 返回 ?user_id= 用户的未读通知数
+func countUnreadNotifications(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := strconv.ParseUint(c.Query("user_id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
+			return
+		}
+
+		var count int64
+		if err := db.WithContext(c.Request.Context()).Model(&models.{{$m.Name}}{}).
+			Where("user_id = ? AND read_at IS NULL", userID).Count(&count).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"count": count})
+	}
+}
+`
@@ -0,0 +1,226 @@
+package main
+
+import "path"
+
+// 通知模板，生成到 pkg/notifications：EmailEnabled 时通过 SendGrid 发送邮件，HTML 邮件模板在 templates/email；
+// SlackEnabled 时通过 incoming webhook 发送 Slack 消息；FCMEnabled、TwilioEnabled 时发送推送和短信；
+// NotificationCenter 时把新建的站内通知投递到各渠道。
+// templates/email 中的 HTML 邮件模板使用 html/template 语法，与生成器的模板语法冲突，因此原样写入
+
+type emailNotificationGenerator struct {
+	templateGenerator
+}
+
+var notificationGenerator = emailNotificationGenerator{templateGenerator{
+	name: "notifications",
+	files: func(data TemplateData) map[string]string {
+		files := map[string]string{}
+		if data.Project.EmailEnabled {
+			files["pkg/notifications/email.go"] = "notifications_email.go.tmpl"
+			files["templates/email/embed.go"] = "email_embed.go.tmpl"
+		}
+		if data.Project.SlackEnabled {
+			files["pkg/notifications/slack.go"] = "slack.go.tmpl"
+		}
+		if data.Project.FCMEnabled {
+			files["pkg/notifications/fcm.go"] = "fcm.go.tmpl"
+			files["pkg/handlers/notification_send.go"] = "notification_send.go.tmpl"
+		}
+		if data.Project.TwilioEnabled {
+			files["pkg/notifications/sms.go"] = "sms.go.tmpl"
+			files["pkg/handlers/sms.go"] = "sms_handler.go.tmpl"
+		}
+		if data.Project.NotificationCenter {
+			files["pkg/notifications/publisher.go"] = "notification_publisher.go.tmpl"
+			files["pkg/handlers/notification_center.go"] = "notification_center.go.tmpl"
+		}
+		return files
+	},
+}}
+
+func (g emailNotificationGenerator) Generate(data TemplateData, fs FileSystem) error {
+	if data.Project.EmailEnabled {
+		for name, content := range emailTemplates {
+			if err := fs.WriteFile(path.Join("templates/email", name), []byte(content)); err != nil {
+				return err
+			}
+		}
+	}
+	return g.templateGenerator.Generate(data, fs)
+}
+
+const notificationsEmailTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package notifications
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"log"
+
+	"github.com/sendgrid/sendgrid-go"
+	"github.com/sendgrid/sendgrid-go/helpers/mail"
+
+	emailtemplates "{{.Project.ModuleName}}/templates/email"
+)
+
+var (
+	sendGridAPIKey string
+	fromEmail      string
+	templates      = template.Must(template.ParseFS(emailtemplates.FS, "*.html"))
+)
+
+// Configure 设置 SENDGRID_API_KEY 和 FROM_EMAIL，启动时调用一次。
+// API key 为空时邮件只输出到日志，便于本地开发
+func Configure(apiKey, from string) {
+	sendGridAPIKey = apiKey
+	fromEmail = from
+}
+
+// SendEmail 通过 SendGrid 发送 HTML 邮件
+func SendEmail(to, subject, body string) error {
+	if sendGridAPIKey == "" {
+		log.Printf("email to=%s subject=%q\n%s", to, subject, body)
+		return nil
+	}
+
+	message := mail.NewV3MailInit(mail.NewEmail("", fromEmail), subject, mail.NewEmail("", to), mail.NewContent("text/html", body))
+	response, err := sendgrid.NewSendClient(sendGridAPIKey).Send(message)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("sendgrid: unexpected status %d: %s", response.StatusCode, response.Body)
+	}
+	return nil
+}
+
+// 渲染 templates/email 中的邮件模板
+func render(name string, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// SendWelcomeEmail 发送注册欢迎邮件
+func SendWelcomeEmail(to, name string) error {
+	body, err := render("welcome.html", map[string]string{"Name": name})
+	if err != nil {
+		return err
+	}
+	return SendEmail(to, "Welcome to {{.Project.ProjectName}}", body)
+}
+
+// SendPasswordResetEmail 发送重置密码链接，resetURL 由调用方生成
+func SendPasswordResetEmail(to, resetURL string) error {
+	body, err := render("password_reset.html", map[string]string{"URL": resetURL})
+	if err != nil {
+		return err
+	}
+	return SendEmail(to, "Reset your password", body)
+}
+
+// SendVerificationEmail 发送账号验证链接，verifyURL 由调用方生成
+func SendVerificationEmail(to, verifyURL string) error {
+	body, err := render("verification.html", map[string]string{"URL": verifyURL})
+	if err != nil {
+		return err
+	}
+	return SendEmail(to, "Verify your email address", body)
+}
+`
+
+const emailEmbedTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package email
+
+import "embed"
+
+// FS 包含本目录下的 HTML 邮件模板，编译进二进制文件，部署时不需要复制模板目录
+//
+//go:embed *.html
+var FS embed.FS
+`
+
+// templates/email 下的 HTML 邮件模板，原样写入
+var emailTemplates = map[string]string{
+	"welcome.html": `<!DOCTYPE html>
+<html>
+<body>
+  <h1>Welcome{{with .Name}}, {{.}}{{end}}!</h1>
+  <p>Thanks for signing up. Your account is ready to use.</p>
+</body>
+</html>
+`,
+	"password_reset.html": `<!DOCTYPE html>
+<html>
+<body>
+  <h1>Reset your password</h1>
+  <p>Click the link below to choose a new password. If you did not request a reset, you can ignore this email.</p>
+  <p><a href="{{.URL}}">Reset password</a></p>
+</body>
+</html>
+`,
+	"verification.html": `<!DOCTYPE html>
+<html>
+<body>
+  <h1>Verify your email address</h1>
+  <p>Click the link below to confirm your email address.</p>
+  <p><a href="{{.URL}}">Verify email</a></p>
+</body>
+</html>
+`,
+}
+
+const slackTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+var slackWebhookURL string
+
+// ConfigureSlack 设置 SLACK_WEBHOOK_URL，启动时调用一次。为空时消息只输出到日志
+func ConfigureSlack(webhookURL string) {
+	slackWebhookURL = webhookURL
+}
+
+// PostMessage 通过 Slack incoming webhook 发送一条消息
+func PostMessage(text string) error {
+	if slackWebhookURL == "" {
+		log.Printf("slack: %s", text)
+		return nil
+	}
+
+	payload, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+	resp, err := http.Post(slackWebhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("slack: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// NotifyCreated 在后台发送新建记录的通知，由 @slack 模型的 AfterCreate 钩子调用；
+// 发送失败只记录日志，不影响创建记录的事务
+func NotifyCreated(model string, id interface{}) {
+	go func() {
+		if err := PostMessage(fmt.Sprintf("New %s created: %v", model, id)); err != nil {
+			log.Printf("slack: %v", err)
+		}
+	}()
+}
+`
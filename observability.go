@@ -0,0 +1,368 @@
+package main
+
+// 监控、追踪与日志相关模板
+
+const datadogTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package tracing
+
+import (
+	"github.com/gin-gonic/gin"
+	gintrace "gopkg.in/DataDog/dd-trace-go.v1/contrib/gin-gonic/gin"
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
+
+	"{{.Project.ModuleName}}/pkg/config"
+)
+
+// StartDatadog 启动 Datadog 追踪器，返回的函数用于停止追踪器
+func StartDatadog(cfg *config.Config) func() {
+	tracer.Start(
+		tracer.WithAgentAddr(cfg.DDAgentHost+":8126"),
+		tracer.WithService(cfg.DDServiceName),
+		tracer.WithEnv(cfg.DDEnv),
+	)
+	return tracer.Stop
+}
+
+// DatadogMiddleware 为每个请求创建 Datadog span
+func DatadogMiddleware(serviceName string) gin.HandlerFunc {
+	return gintrace.Middleware(serviceName)
+}
+`
+
+const sentryTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package monitoring
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/getsentry/sentry-go"
+	sentrygin "github.com/getsentry/sentry-go/gin"
+	"github.com/gin-gonic/gin"
+
+	"{{.Project.ModuleName}}/pkg/config"
+)
+
+// InitSentry 根据配置初始化 Sentry 客户端
+func InitSentry(cfg *config.Config) error {
+	return sentry.Init(sentry.ClientOptions{
+		Dsn:         cfg.SentryDSN,
+		Environment: cfg.SentryEnvironment,
+	})
+}
+
+// SentryMiddleware 上报 panic 以及所有 5xx 响应
+func SentryMiddleware() []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		sentrygin.New(sentrygin.Options{Repanic: true}),
+		reportServerErrors(),
+	}
+}
+
+func reportServerErrors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		status := c.Writer.Status()
+		if status < http.StatusInternalServerError {
+			return
+		}
+
+		hub := sentrygin.GetHubFromContext(c)
+		if hub == nil {
+			return
+		}
+		hub.WithScope(func(scope *sentry.Scope) {
+			scope.SetTag("status", fmt.Sprint(status))
+			scope.SetExtra("errors", c.Errors.String())
+			hub.CaptureMessage(fmt.Sprintf("%s %s returned %d", c.Request.Method, c.FullPath(), status))
+		})
+	}
+}
+`
+
+const otelMetricsTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+
+	"{{.Project.ModuleName}}/pkg/config"
+)
+
+// InitMetrics 注册通过 OTLP 导出的全局 MeterProvider
+func InitMetrics(ctx context.Context, cfg *config.Config) (func(context.Context) error, error) {
+	exporter, err := otlpmetricgrpc.New(ctx,
+		otlpmetricgrpc.WithEndpoint(cfg.OTelEndpoint),
+		otlpmetricgrpc.WithInsecure(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter)),
+	)
+	otel.SetMeterProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Instrument 为资源记录请求数、请求耗时与进行中的请求数，
+// 例如 users.requests.total、users.request.duration、users.requests.active
+func Instrument(resource string) gin.HandlerFunc {
+	meter := otel.Meter("{{.Project.ModuleName}}")
+
+	requests, err := meter.Int64Counter(resource+".requests.total",
+		metric.WithDescription("Total number of requests"))
+	if err != nil {
+		otel.Handle(err)
+	}
+	duration, err := meter.Float64Histogram(resource+".request.duration",
+		metric.WithDescription("Request duration"), metric.WithUnit("ms"))
+	if err != nil {
+		otel.Handle(err)
+	}
+	active, err := meter.Int64UpDownCounter(resource+".requests.active",
+		metric.WithDescription("Number of in-flight requests"))
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		start := time.Now()
+
+		active.Add(ctx, 1)
+		c.Next()
+		active.Add(ctx, -1)
+
+		attrs := metric.WithAttributes(
+			attribute.String("method", c.Request.Method),
+			attribute.Int("status", c.Writer.Status()),
+		)
+		requests.Add(ctx, 1, attrs)
+		duration.Record(ctx, float64(time.Since(start).Microseconds())/1000, attrs)
+	}
+}
+`
+
+const otelTracerTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package tracing
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"{{.Project.ModuleName}}/pkg/config"
+)
+
+// InitTracer 注册全局 TracerProvider，导出器由所选后端的 newExporter 提供
+func InitTracer(ctx context.Context, cfg *config.Config) (func(context.Context) error, error) {
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", cfg.OTelServiceName),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	return provider.Shutdown, nil
+}
+
+// Middleware 为每个请求创建 span，与具体追踪后端无关
+func Middleware(serviceName string) gin.HandlerFunc {
+	return otelgin.Middleware(serviceName)
+}
+`
+
+const otlpExporterTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"{{.Project.ModuleName}}/pkg/config"
+)
+
+func newExporter(ctx context.Context, cfg *config.Config) (sdktrace.SpanExporter, error) {
+	return otlptracegrpc.New(ctx,
+		otlptracegrpc.WithEndpoint(cfg.OTelEndpoint),
+		otlptracegrpc.WithInsecure(),
+	)
+}
+`
+
+const jaegerExporterTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package tracing
+
+import (
+	"context"
+
+	// Jaeger 导出器已被官方弃用，Jaeger 1.35+ 可直接接收 OTLP
+	"go.opentelemetry.io/otel/exporters/jaeger"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"{{.Project.ModuleName}}/pkg/config"
+)
+
+func newExporter(_ context.Context, cfg *config.Config) (sdktrace.SpanExporter, error) {
+	return jaeger.New(jaeger.WithCollectorEndpoint(jaeger.WithEndpoint(cfg.JaegerEndpoint)))
+}
+`
+
+const zipkinExporterTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/exporters/zipkin"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"{{.Project.ModuleName}}/pkg/config"
+)
+
+func newExporter(_ context.Context, cfg *config.Config) (sdktrace.SpanExporter, error) {
+	return zipkin.New(cfg.ZipkinEndpoint)
+}
+`
+
+const lokiLoggerTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package logger
+
+import (
+	"strings"
+
+	"github.com/grafana/loki-client-go/loki"
+	"github.com/prometheus/common/model"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"{{.Project.ModuleName}}/pkg/config"
+)
+
+// 从日志字段中提取为 Loki 标签的键
+var labelFields = map[string]bool{
+	"model":      true,
+	"request_id": true,
+}
+
+var global = zap.NewNop()
+
+// L 返回全局日志记录器
+func L() *zap.Logger {
+	return global
+}
+
+// Init 创建同时输出到标准输出和 Loki 的日志记录器，返回的函数用于刷新并关闭
+func Init(cfg *config.Config) (func(), error) {
+	base, err := zap.NewProduction()
+	if err != nil {
+		return nil, err
+	}
+
+	lokiCfg, err := loki.NewDefaultConfig(cfg.LokiURL)
+	if err != nil {
+		return nil, err
+	}
+	lokiCfg.TenantID = cfg.LokiTenantID
+
+	client, err := loki.New(lokiCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	core := &lokiCore{
+		LevelEnabler: zapcore.InfoLevel,
+		client:       client,
+		enc:          zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+		labels: model.LabelSet{
+			"app": "{{.Project.ProjectName}}",
+			"env": model.LabelValue(cfg.AppEnv),
+		},
+	}
+	global = zap.New(zapcore.NewTee(base.Core(), core))
+
+	return func() {
+		_ = global.Sync()
+		client.Stop()
+	}, nil
+}
+
+// lokiCore 是将日志推送到 Loki 的 zapcore.Core
+type lokiCore struct {
+	zapcore.LevelEnabler
+	client *loki.Client
+	enc    zapcore.Encoder
+	labels model.LabelSet
+}
+
+func (c *lokiCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.enc = c.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(clone.enc)
+	}
+	clone.labels = c.labels.Merge(labelsFrom(fields))
+	return &clone
+}
+
+func (c *lokiCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+	return ce
+}
+
+func (c *lokiCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(entry, fields)
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+
+	labels := c.labels.Merge(labelsFrom(fields))
+	labels["level"] = model.LabelValue(entry.Level.String())
+	return c.client.Handle(labels, entry.Time, strings.TrimSuffix(buf.String(), "\n"))
+}
+
+func (c *lokiCore) Sync() error {
+	return nil
+}
+
+func labelsFrom(fields []zapcore.Field) model.LabelSet {
+	labels := model.LabelSet{}
+	for _, f := range fields {
+		if labelFields[f.Key] && f.Type == zapcore.StringType && f.String != "" {
+			labels[model.LabelName(f.Key)] = model.LabelValue(f.String)
+		}
+	}
+	return labels
+}
+`
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+// 只解析导入模型需要的部分；JSON 是 YAML 的子集，同样可以解析
+type openAPIDocument struct {
+	OpenAPI    string `yaml:"openapi"`
+	Components struct {
+		// 使用 yaml.Node 保留定义顺序
+		Schemas yaml.Node `yaml:"schemas"`
+	} `yaml:"components"`
+}
+
+type openAPISchema struct {
+	// 3.0 为字符串，3.1 也可以是数组，如 [string, "null"]
+	Type       interface{} `yaml:"type"`
+	Format     string      `yaml:"format"`
+	Ref        string      `yaml:"$ref"`
+	Required   []string    `yaml:"required"`
+	Properties yaml.Node   `yaml:"properties"`
+	MinLength  int         `yaml:"minLength"`
+	MaxLength  int         `yaml:"maxLength"`
+}
+
+// POST /import-openapi：解析 OpenAPI 3.0/3.1 文档的 components/schemas 为模型定义
+// 文档可以通过 multipart 字段 spec 上传，也可以直接作为请求体
+func importOpenAPI(c *gin.Context) {
+	var content []byte
+	if c.ContentType() == "multipart/form-data" {
+		file, err := c.FormFile("spec")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "缺少上传文件 spec"})
+			return
+		}
+		f, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无法读取上传文件: " + err.Error()})
+			return
+		}
+		defer f.Close()
+		content, err = io.ReadAll(f)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无法读取上传文件: " + err.Error()})
+			return
+		}
+	} else {
+		var err error
+		content, err = c.GetRawData()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无法读取请求体: " + err.Error()})
+			return
+		}
+	}
+
+	models, warnings, err := parseOpenAPIModels(content)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"models": models, "warnings": warnings})
+}
+
+// 解析文档中的对象 schema，无法映射的 schema 和字段会跳过并记录在 warnings 中
+func parseOpenAPIModels(content []byte) ([]Model, []string, error) {
+	var doc openAPIDocument
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, nil, fmt.Errorf("无法解析 OpenAPI 文档: %w", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.0") && !strings.HasPrefix(doc.OpenAPI, "3.1") {
+		return nil, nil, fmt.Errorf("不支持的 OpenAPI 版本 %q，仅支持 3.0 和 3.1", doc.OpenAPI)
+	}
+
+	models := []Model{}
+	warnings := []string{}
+	err := forEachMapEntry(&doc.Components.Schemas, func(name string, node *yaml.Node) error {
+		var schema openAPISchema
+		if err := node.Decode(&schema); err != nil {
+			return fmt.Errorf("无法解析 schema %s: %w", name, err)
+		}
+		if openAPIType(schema) != "object" {
+			warnings = append(warnings, fmt.Sprintf("schema %s 不是对象类型，已跳过", name))
+			return nil
+		}
+
+		model := newModel(toCamelCase(name))
+		required := make(map[string]bool)
+		for _, prop := range schema.Required {
+			required[prop] = true
+		}
+
+		err := forEachMapEntry(&schema.Properties, func(prop string, node *yaml.Node) error {
+			var field openAPISchema
+			if err := node.Decode(&field); err != nil {
+				return fmt.Errorf("无法解析 schema %s 的属性 %s: %w", name, prop, err)
+			}
+			if builtinModelColumns[toSnakeCase(prop)] {
+				return nil
+			}
+
+			goType := openAPIGoType(field)
+			if goType == "" {
+				kind := openAPIType(field)
+				if field.Ref != "" {
+					kind = field.Ref
+				}
+				warnings = append(warnings, fmt.Sprintf("模型 %s: 属性 %s 的类型 %s 暂不支持，已跳过", model.Name, prop, kind))
+				return nil
+			}
+
+			f := ModelField{
+				Name:    toCamelCase(prop),
+				Type:    goType,
+				JsonTag: prop,
+				GormTag: defaultGormTag(toSnakeCase(prop), goType),
+				// bool 不能标记为必填，见 ValidateModel
+				Required: required[prop] && goType != "bool",
+				Order:    len(model.Fields),
+			}
+			if goType == "string" {
+				f.MinLength, f.MaxLength = field.MinLength, field.MaxLength
+			}
+			model.Fields = append(model.Fields, f)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		normalizeIDField(&model)
+		models = append(models, model)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return models, warnings, nil
+}
+
+// 按顺序遍历 YAML 映射节点，空节点视为空映射
+func forEachMapEntry(node *yaml.Node, fn func(key string, value *yaml.Node) error) error {
+	if node.Kind == 0 {
+		return nil
+	}
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("第 %d 行: 应为映射", node.Line)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if err := fn(node.Content[i].Value, node.Content[i+1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// schema 的类型，忽略 3.1 类型数组中的 "null"；有 properties 但未声明类型时视为 object
+func openAPIType(s openAPISchema) string {
+	switch t := s.Type.(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, item := range t {
+			if name, ok := item.(string); ok && name != "null" {
+				return name
+			}
+		}
+	}
+	if s.Properties.Kind == yaml.MappingNode {
+		return "object"
+	}
+	return ""
+}
+
+// OpenAPI 类型映射为受支持的Go类型，无法映射时返回空字符串
+func openAPIGoType(s openAPISchema) string {
+	if s.Ref != "" {
+		return ""
+	}
+	switch openAPIType(s) {
+	case "string":
+		switch s.Format {
+		case "date-time", "date":
+			return "time.Time"
+		case "uuid":
+			return "uuid.UUID"
+		default:
+			return "string"
+		}
+	case "integer":
+		switch s.Format {
+		case "int32":
+			return "int32"
+		case "int64":
+			return "int64"
+		default:
+			return "int"
+		}
+	case "number":
+		if s.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case "boolean":
+		return "bool"
+	default:
+		// array、object 等嵌套结构无法直接映射为数据库列
+		return ""
+	}
+}
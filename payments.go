@@ -0,0 +1,224 @@
+package main
+
+// Stripe 支付模板，StripeEnabled 启用且有模型声明了 price 金额字段时生成 pkg/payments
+// 和 /payments 下的结账、webhook、退款接口，业务处理部分留给使用者补充
+
+// 金额字段 price，类型为 float32 或 float64（以元为单位，两位小数）
+func (m Model) PriceField() *ModelField {
+	for i, f := range m.Fields {
+		if f.Name == "Price" && (f.Type == "float32" || f.Type == "float64") {
+			return &m.Fields[i]
+		}
+	}
+	return nil
+}
+
+// 结账时读取价格的模型，即第一个有 price 字段的模型
+func (data TemplateData) PaymentModel() *Model {
+	for i, m := range data.Models {
+		if m.PriceField() != nil {
+			return &data.Models[i]
+		}
+	}
+	return nil
+}
+
+const stripeTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package payments
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/stripe/stripe-go/v76/client"
+	"github.com/stripe/stripe-go/v76/webhook"
+)
+
+// 结账使用的货币，金额按该货币的最小单位（分）提交给 Stripe
+const currency = "usd"
+
+// Stripe 封装 Stripe API 客户端和 webhook 签名密钥
+type Stripe struct {
+	api           *client.API
+	webhookSecret string
+}
+
+func NewStripe(secretKey, webhookSecret string) *Stripe {
+	return &Stripe{
+		api:           client.New(secretKey, nil),
+		webhookSecret: webhookSecret,
+	}
+}
+
+// CheckoutItem 是一次结账购买的商品，Metadata 原样附加到 Checkout Session，
+// 在 webhook 中用于找回对应的记录
+type CheckoutItem struct {
+	Name     string
+	Price    float64
+	Quantity int64
+	Metadata map[string]string
+}
+
+// CreateCheckoutSession 创建 Stripe 托管的结账页面，返回的 Session.URL 供客户端跳转
+func (s *Stripe) CreateCheckoutSession(item CheckoutItem, successURL, cancelURL string) (*stripe.CheckoutSession, error) {
+	params := &stripe.CheckoutSessionParams{
+		Mode: stripe.String(string(stripe.CheckoutSessionModePayment)),
+		LineItems: []*stripe.CheckoutSessionLineItemParams{
+			{
+				PriceData: &stripe.CheckoutSessionLineItemPriceDataParams{
+					Currency: stripe.String(currency),
+					ProductData: &stripe.CheckoutSessionLineItemPriceDataProductDataParams{
+						Name: stripe.String(item.Name),
+					},
+					UnitAmount: stripe.Int64(int64(math.Round(item.Price * 100))),
+				},
+				Quantity: stripe.Int64(item.Quantity),
+			},
+		},
+		SuccessURL: stripe.String(successURL),
+		CancelURL:  stripe.String(cancelURL),
+	}
+	for key, value := range item.Metadata {
+		params.AddMetadata(key, value)
+	}
+	return s.api.CheckoutSessions.New(params)
+}
+
+// HandleWebhook 校验 Stripe-Signature 请求头后处理事件，签名不正确时返回错误。
+// 目前只记录日志，需要在对应的 case 中补充发货、更新订单状态等处理
+func (s *Stripe) HandleWebhook(payload []byte, signature string) (stripe.Event, error) {
+	event, err := webhook.ConstructEvent(payload, signature, s.webhookSecret)
+	if err != nil {
+		return event, fmt.Errorf("verifying webhook signature: %w", err)
+	}
+
+	switch event.Type {
+	case "checkout.session.completed":
+		var session stripe.CheckoutSession
+		if err := json.Unmarshal(event.Data.Raw, &session); err != nil {
+			return event, fmt.Errorf("parsing checkout session: %w", err)
+		}
+		// TODO: 根据 session.Metadata 找到对应的记录并标记为已支付
+		log.Printf("checkout session %s completed: %v", session.ID, session.Metadata)
+	case "charge.refunded":
+		// TODO: 更新退款记录的状态
+		log.Printf("charge refunded: %s", event.ID)
+	default:
+		log.Printf("unhandled Stripe event %s", event.Type)
+	}
+	return event, nil
+}
+
+// RefundPayment 全额退还 paymentIntentID 对应的付款
+func (s *Stripe) RefundPayment(paymentIntentID string) (*stripe.Refund, error) {
+	return s.api.Refunds.New(&stripe.RefundParams{
+		PaymentIntent: stripe.String(paymentIntentID),
+	})
+}
+`
+
+const paymentHandlerTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}{{$model := .PaymentModel}}package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+{{- if eq $model.IDType "uuid"}}
+	"github.com/google/uuid"
+{{- end}}
+	"gorm.io/gorm"
+
+	"{{.Project.ModuleName}}/pkg/i18n"
+	"{{.Project.ModuleName}}/pkg/models"
+	"{{.Project.ModuleName}}/pkg/payments"
+)
+
+// Stripe 建议 webhook 请求体不超过 64KB
+const maxWebhookBodyBytes = 65536
+
+type checkoutInput struct {
+	ID         {{$model.IDGoType}} ` + "`json:\"id\" binding:\"required\"`" + `
+	Quantity   int64  ` + "`json:\"quantity\" binding:\"omitempty,min=1\"`" + `
+	SuccessURL string ` + "`json:\"success_url\" binding:\"required,url\"`" + `
+	CancelURL  string ` + "`json:\"cancel_url\" binding:\"required,url\"`" + `
+}
+
+// RegisterPaymentRoutes 注册支付接口。webhook 由 Stripe 调用，只校验签名；
+// checkout 和 refund 使用 auth 中的中间件保护
+func RegisterPaymentRoutes(rg *gin.RouterGroup, db *gorm.DB, stripe *payments.Stripe, auth ...gin.HandlerFunc) {
+	rg.POST("/webhook", stripeWebhook(stripe))
+
+	protected := rg.Group("", auth...)
+	protected.POST("/checkout", createCheckout(db, stripe))
+	protected.POST("/refund/:id", refundPayment(stripe))
+}
+
+// 按 {{$model.Name}} 的价格创建结账页面，返回 Stripe Checkout Session 的 ID 和跳转地址
+func createCheckout(db *gorm.DB, stripe *payments.Stripe) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input checkoutInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if input.Quantity == 0 {
+			input.Quantity = 1
+		}
+
+		var {{$model.LowerName}} models.{{$model.Name}}
+		if result := db.First(&{{$model.LowerName}}, "id = ?", input.ID); result.Error != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(language, "not_found", "{{$model.Name}}")})
+			return
+		}
+
+		session, err := stripe.CreateCheckoutSession(payments.CheckoutItem{
+			Name:     fmt.Sprintf("{{$model.Name}} %v", {{$model.LowerName}}.ID),
+			Price:    float64({{$model.LowerName}}.Price),
+			Quantity: input.Quantity,
+			Metadata: map[string]string{"model": "{{$model.Name}}", "id": fmt.Sprint({{$model.LowerName}}.ID)},
+		}, input.SuccessURL, input.CancelURL)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"id": session.ID, "url": session.URL})
+	}
+}
+
+func stripeWebhook(stripe *payments.Stripe) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		payload, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBodyBytes))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if _, err := stripe.HandleWebhook(payload, c.GetHeader("Stripe-Signature")); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Status(http.StatusOK)
+	}
+}
+
+// :id 为 Stripe PaymentIntent 的 ID (pi_...)
+func refundPayment(stripe *payments.Stripe) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		refund, err := stripe.RefundPayment(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"id": refund.ID, "status": refund.Status})
+	}
+}
+`
@@ -0,0 +1,67 @@
+package main
+
+// HTTP/2 预取相关模板，HTTP2Push 启用时生成
+
+// 模型与其通过 <Model>ID 字段引用的其他模型
+type PrefetchLink struct {
+	Model   Model
+	Related []Model
+}
+
+// 按字段名推断模型之间的引用关系，如 BlogPost 的 UserID 字段引用 User
+func (data TemplateData) PrefetchLinks() []PrefetchLink {
+	var links []PrefetchLink
+	for _, m := range data.Models {
+		var related []Model
+		for _, other := range data.Models {
+			if other.Name == m.Name {
+				continue
+			}
+			for _, f := range m.Fields {
+				if f.Name == other.Name+"ID" {
+					related = append(related, other)
+					break
+				}
+			}
+		}
+		if len(related) > 0 {
+			links = append(links, PrefetchLink{Model: m, Related: related})
+		}
+	}
+	return links
+}
+
+const prefetchTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package middlewares
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Prefetch 为 GET 请求添加 Link: <path>; rel=prefetch 响应头，
+// 支持 HTTP/2 的客户端可以据此提前请求相关资源。
+// links 的键为路由前缀，如 /api/v1/blog_posts，值为需要预取的路径
+func Prefetch(links map[string][]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		// 响应头必须在写入响应体之前设置
+		path := c.FullPath()
+		for prefix, related := range links {
+			if path != prefix && !strings.HasPrefix(path, prefix+"/") {
+				continue
+			}
+			for _, target := range related {
+				c.Writer.Header().Add("Link", "<"+target+">; rel=prefetch")
+			}
+		}
+		c.Next()
+	}
+}
+`
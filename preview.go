@@ -0,0 +1,80 @@
+package main
+
+import (
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 预览页中的单个文件
+type previewFile struct {
+	Path     string
+	Language string
+	Content  string
+}
+
+// 按目录（即 Go 包）分组的文件
+type previewGroup struct {
+	Dir   string
+	Files []previewFile
+}
+
+// highlight.js 语言名称，未知类型按纯文本显示
+func previewLanguage(filePath string) string {
+	switch base := path.Base(filePath); {
+	case strings.HasSuffix(base, ".go"):
+		return "go"
+	case strings.HasSuffix(base, ".yaml"), strings.HasSuffix(base, ".yml"):
+		return "yaml"
+	case strings.HasSuffix(base, ".md"):
+		return "markdown"
+	case base == "Makefile":
+		return "makefile"
+	case base == ".env":
+		return "ini"
+	case base == "Dockerfile":
+		return "bash"
+	default:
+		return "plaintext"
+	}
+}
+
+// GET /preview-html：生成到内存并以语法高亮的 HTML 展示，参数与 /generate 相同
+func previewHTML(c *gin.Context) {
+	// bindTemplateData 读取表单字段，GET 请求的参数在查询串中
+	c.Request.PostForm = c.Request.URL.Query()
+	data, ok := bindTemplateData(c)
+	if !ok {
+		return
+	}
+	// 预览不需要 go.sum，避免每次刷新都运行 go mod tidy
+	data.Project.GoSum = false
+
+	files := NewMemFileSystem()
+	if err := generateProjectStructure(files, data); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "无法生成项目: " + err.Error()})
+		return
+	}
+
+	// Paths 已排序，同一目录的文件相邻
+	var groups []previewGroup
+	for _, filePath := range files.Paths() {
+		dir := path.Dir(filePath)
+		if len(groups) == 0 || groups[len(groups)-1].Dir != dir {
+			groups = append(groups, previewGroup{Dir: dir})
+		}
+		group := &groups[len(groups)-1]
+		group.Files = append(group.Files, previewFile{
+			Path:     filePath,
+			Language: previewLanguage(filePath),
+			Content:  string(files.Files[filePath]),
+		})
+	}
+
+	c.HTML(http.StatusOK, "preview.html", gin.H{
+		"Project": data.Project,
+		"Groups":  groups,
+	})
+}
@@ -0,0 +1,113 @@
+package main
+
+// 月度配额模板，QuotaEnabled 启用时生成 pkg/quota，在 Redis 中按月统计每个登录用户的请求数，
+// 需要 session 或 magiclink 认证以识别用户
+
+const quotaTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package quota
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gomodule/redigo/redis"
+{{- if eq .Project.AuthMode "session"}}
+
+	"{{.Project.ModuleName}}/pkg/middlewares"
+{{- end}}
+)
+
+// 计数在配额重置后保留的时间，便于统计上月用量
+const retention = 7 * 24 * time.Hour
+
+// Limiter 在 Redis 哈希 quota:YYYY-MM:<用户> 的 requests 字段中累计用户当月的请求数，
+// 超过 monthlyQuota 时返回 429，配额在每月 1 日 0 点 (UTC) 重置
+type Limiter struct {
+	pool         *redis.Pool
+	monthlyQuota int
+}
+
+func New(redisAddr string, monthlyQuota int) *Limiter {
+	return &Limiter{
+		pool: &redis.Pool{
+			MaxIdle:     10,
+			IdleTimeout: 4 * time.Minute,
+			Dial: func() (redis.Conn, error) {
+				return redis.Dial("tcp", redisAddr)
+			},
+		},
+		monthlyQuota: monthlyQuota,
+	}
+}
+
+// Middleware 需要放在认证中间件之后，每个请求计入当前用户的配额，
+// 响应头 X-RateLimit-Remaining、X-RateLimit-Reset 分别为剩余次数和重置时间 (Unix 秒)。
+// Redis 不可用时只记录日志，不拦截请求
+func (l *Limiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := userID(c)
+		if user == "" {
+			c.Next()
+			return
+		}
+
+		now := time.Now().UTC()
+		reset := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		key := fmt.Sprintf("quota:%s:%s", now.Format("2006-01"), user)
+
+		count, err := l.increment(key, reset.Add(retention))
+		if err != nil {
+			log.Printf("quota: %v", err)
+			c.Next()
+			return
+		}
+
+		remaining := l.monthlyQuota - count
+		if remaining < 0 {
+			remaining = 0
+		}
+		c.Header("X-RateLimit-Limit", strconv.Itoa(l.monthlyQuota))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		if count > l.monthlyQuota {
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "monthly quota exceeded"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// 请求数加一并返回累计值，当月首次请求时设置过期时间
+func (l *Limiter) increment(key string, expireAt time.Time) (int, error) {
+	conn := l.pool.Get()
+	defer conn.Close()
+
+	count, err := redis.Int(conn.Do("HINCRBY", key, "requests", 1))
+	if err != nil {
+		return 0, err
+	}
+	if count == 1 {
+		if _, err := conn.Do("EXPIREAT", key, expireAt.Unix()); err != nil {
+			return 0, err
+		}
+	}
+	return count, nil
+}
+
+// 认证中间件设置的用户标识
+func userID(c *gin.Context) string {
+{{- if eq .Project.AuthMode "session"}}
+	user, ok := c.Get(middlewares.SessionUserKey)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprint(user)
+{{- else}}
+	return c.GetString("email")
+{{- end}}
+}
+`
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// POST /reorder 的请求体，models 为完整的模型定义，字段带有调整后的 Order
+type reorderRequest struct {
+	Models []Model `json:"models" binding:"required"`
+}
+
+// POST /reorder：按 Order 重新排列各模型的字段并从 0 开始连续编号，
+// 返回排序后的模型和对应的文本定义，供拖拽排序后回填表单。
+// 列名由字段名决定，gorm:"column:..." 标签不受顺序影响
+func reorderModels(c *gin.Context) {
+	var req reorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无法解析请求: " + err.Error()})
+		return
+	}
+
+	for i := range req.Models {
+		fields := req.Models[i].OrderedFields()
+		for order := range fields {
+			fields[order].Order = order
+		}
+		req.Models[i].Fields = fields
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"models":     req.Models,
+		"definition": formatModels(req.Models),
+	})
+}
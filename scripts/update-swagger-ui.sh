@@ -0,0 +1,16 @@
+#!/bin/sh
+# 从 npm 下载 swagger-ui-dist，更新生成项目内嵌的 Swagger UI 静态文件
+# 用法: sh scripts/update-swagger-ui.sh [版本号]
+set -e
+
+VERSION="${1:-5.17.14}"
+DEST="$(dirname "$0")/../static/swagger-ui"
+TMP="$(mktemp -d)"
+trap 'rm -rf "$TMP"' EXIT
+
+curl -fsSL "https://registry.npmjs.org/swagger-ui-dist/-/swagger-ui-dist-$VERSION.tgz" | tar -xz -C "$TMP"
+for f in swagger-ui.css swagger-ui-bundle.js swagger-ui-standalone-preset.js favicon-32x32.png LICENSE; do
+  cp "$TMP/package/$f" "$DEST/$f"
+done
+echo "$VERSION" > "$DEST/VERSION"
+echo "已更新 Swagger UI 到 $VERSION"
@@ -0,0 +1,423 @@
+package main
+
+// 全文搜索模板，ElasticsearchEnabled 或 AlgoliaEnabled 启用时为 @search 模型生成 pkg/search 和 GET /api/v1/<模型复数>/search 接口，
+// 模型的 AfterCreate、AfterUpdate、AfterDelete 钩子把记录同步到与表同名的 Elasticsearch 索引或 Algolia 索引
+
+const defaultElasticsearchURL = "http://127.0.0.1:9200"
+
+// 模板在 @search 模型钩子开头生成的索引同步调用，导出配置还原钩子时去掉
+const (
+	indexSearchCallLine       = "m.indexSearch(tx)"
+	updateSearchIndexCallLine = "m.updateSearchIndex(tx)"
+	removeSearchIndexCallLine = "m.removeSearchIndex(tx)"
+)
+
+// 是否启用了 @search 模型可用的搜索服务
+func (p ProjectConfig) SearchBackendEnabled() bool {
+	return p.ElasticsearchEnabled || p.AlgoliaEnabled
+}
+
+// 是否有模型使用 @search
+func (data TemplateData) UsesSearch() bool {
+	for _, m := range data.Models {
+		if m.SearchEnabled {
+			return true
+		}
+	}
+	return false
+}
+
+// 搜索接口 fields 参数可选的字段（JSON 名称），只包含字符串字段，不含 json:"-" 的敏感字段
+func (m Model) SearchFields() []ModelField {
+	var fields []ModelField
+	for _, f := range m.HistoryFields() {
+		if f.Type == "string" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// 删除前是否需要先查询出记录，AfterDelete 钩子需要完整的记录写入历史快照或按 ID 删除索引文档
+func (m Model) DeleteLoadsRecord() bool {
+	return m.VersionHistory || m.SearchEnabled
+}
+
+var searchGenerator = templateGenerator{
+	name: "search",
+	files: func(data TemplateData) map[string]string {
+		switch {
+		case data.Project.ElasticsearchEnabled:
+			return map[string]string{"pkg/search/elasticsearch.go": "elasticsearch.go.tmpl"}
+		case data.Project.AlgoliaEnabled:
+			return map[string]string{"pkg/search/algolia.go": "algolia.go.tmpl"}
+		}
+		return nil
+	},
+	modelFiles: func(data TemplateData, model Model) map[string]string {
+		if !model.SearchEnabled {
+			return nil
+		}
+		return map[string]string{
+			"pkg/handlers/" + model.SnakeName + "_search.go": "search_handler.go.tmpl",
+		}
+	},
+}
+
+const elasticsearchTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+)
+
+// 每次搜索最多返回的文档数
+const maxResults = 50
+
+// ErrDisabled 表示未配置 ELASTICSEARCH_URL
+var ErrDisabled = errors.New("search: elasticsearch is not configured")
+
+var client *elasticsearch.Client
+
+// Configure 连接 ELASTICSEARCH_URL，启动时调用一次。为空时不同步索引，搜索返回 ErrDisabled
+func Configure(url string) error {
+	if url == "" {
+		return nil
+	}
+	c, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{url}})
+	if err != nil {
+		return err
+	}
+	client = c
+	return nil
+}
+
+// Index 以 id 写入或覆盖文档，文档内容为 doc 的 JSON，索引不存在时由 Elasticsearch 自动创建
+func Index(ctx context.Context, index, id string, doc interface{}) error {
+	if client == nil {
+		return nil
+	}
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	res, err := client.Index(index, bytes.NewReader(body), client.Index.WithDocumentID(id), client.Index.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	return checkResponse(res, "indexing "+index+"/"+id)
+}
+
+// Delete 删除文档，文档不存在时不报错
+func Delete(ctx context.Context, index, id string) error {
+	if client == nil {
+		return nil
+	}
+	res, err := client.Delete(index, id, client.Delete.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	if res.StatusCode == http.StatusNotFound {
+		res.Body.Close()
+		return nil
+	}
+	return checkResponse(res, "deleting "+index+"/"+id)
+}
+
+// Search 在 fields 中全文匹配 q，fields 为空时匹配所有字段，按相关度返回文档内容
+func Search(ctx context.Context, index, q string, fields []string) ([]json.RawMessage, error) {
+	if client == nil {
+		return nil, ErrDisabled
+	}
+
+	multiMatch := map[string]interface{}{
+		"query": q,
+		// 数值、时间字段无法匹配文本时忽略，而不是报错
+		"lenient": true,
+	}
+	if len(fields) > 0 {
+		multiMatch["fields"] = fields
+	}
+	query, err := json.Marshal(map[string]interface{}{
+		"query": map[string]interface{}{"multi_match": multiMatch},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Search(
+		client.Search.WithContext(ctx),
+		client.Search.WithIndex(index),
+		client.Search.WithBody(bytes.NewReader(query)),
+		client.Search.WithSize(maxResults),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	// 索引在第一条记录写入时才创建
+	if res.StatusCode == http.StatusNotFound {
+		return []json.RawMessage{}, nil
+	}
+	if res.IsError() {
+		return nil, fmt.Errorf("search: searching %s: %s", index, res.String())
+	}
+
+	var result struct {
+		Hits struct {
+			Hits []struct {
+				Source json.RawMessage ` + "`json:\"_source\"`" + `
+			} ` + "`json:\"hits\"`" + `
+		} ` + "`json:\"hits\"`" + `
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	docs := make([]json.RawMessage, 0, len(result.Hits.Hits))
+	for _, hit := range result.Hits.Hits {
+		docs = append(docs, hit.Source)
+	}
+	return docs, nil
+}
+
+func checkResponse(res *esapi.Response, action string) error {
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("search: %s: %s", action, res.String())
+	}
+	return nil
+}
+`
+
+const searchHandlerTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package handlers
+
+import (
+	"errors"
+	"net/http"
+{{- if .Project.AlgoliaEnabled}}
+	"sort"
+{{- if .Model.NumericID}}
+	"strconv"
+{{- end}}
+{{- end}}
+	"strings"
+
+	"github.com/gin-gonic/gin"
+{{- if .Project.AlgoliaEnabled}}
+	"gorm.io/gorm"
+{{- end}}
+
+	"{{.Project.ModuleName}}/pkg/models"
+	"{{.Project.ModuleName}}/pkg/search"
+)
+
+// 搜索接口 ?fields= 参数可选的字段
+var {{.Model.LowerName}}SearchFields = map[string]bool{ {{- range $i, $f := .Model.SearchFields}}{{if $i}}, {{end}}"{{$f.JsonTag}}": true{{end -}} }
+
+{{if .Project.AlgoliaEnabled}}// GET /search?q=...&fields=a,b 在 Algolia 中搜索 {{.Model.Name}}，按相关度返回数据库中的最新记录，fields 为空时匹配所有可搜索字段
+{{else}}// GET /search?q=...&fields=a,b 在 Elasticsearch 中搜索 {{.Model.Name}}，fields 为空时匹配所有字段
+{{end -}}
+{{block "search" .}}{{if .Project.AlgoliaEnabled}}func search{{.Model.Name}}s(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+{{- template "searchParams" .}}
+
+		objectIDs, err := search.Search(models.{{.Model.Name}}SearchIndex, q, fields)
+{{- template "searchError" .}}
+{{- if .Model.NumericID}}
+
+		// objectID 是主键的字符串形式
+		ids := make([]int64, 0, len(objectIDs))
+		for _, objectID := range objectIDs {
+			if id, err := strconv.ParseInt(objectID, 10, 64); err == nil {
+				ids = append(ids, id)
+			}
+		}
+{{- else}}
+		ids := objectIDs
+{{- end}}
+
+		// 索引中的数据可能落后于数据库，返回数据库中的记录，已删除的记录不返回
+		var {{.Model.PluralName}} []models.{{.Model.Name}}
+		if len(ids) > 0 {
+			if result := db.Where("id IN ?", ids).Find(&{{.Model.PluralName}}); result.Error != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": result.Error.Error()})
+				return
+			}
+		}
+		rank := make(map[string]int, len(objectIDs))
+		for i, objectID := range objectIDs {
+			rank[objectID] = i
+		}
+		sort.Slice({{.Model.PluralName}}, func(i, j int) bool {
+			return rank[search.ObjectID({{.Model.PluralName}}[i].ID)] < rank[search.ObjectID({{.Model.PluralName}}[j].ID)]
+		})
+		c.JSON(http.StatusOK, {{.Model.PluralName}})
+	}
+}{{else}}func search{{.Model.Name}}s() gin.HandlerFunc {
+	return func(c *gin.Context) {
+{{- template "searchParams" .}}
+
+		docs, err := search.Search(c.Request.Context(), models.{{.Model.Name}}SearchIndex, q, fields)
+{{- template "searchError" .}}
+		c.JSON(http.StatusOK, docs)
+	}
+}{{end}}{{end}}
+
+{{- define "searchParams"}}
+		q := strings.TrimSpace(c.Query("q"))
+		if q == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+			return
+		}
+		var fields []string
+		if raw := c.Query("fields"); raw != "" {
+			for _, field := range strings.Split(raw, ",") {
+				field = strings.TrimSpace(field)
+				if !{{.Model.LowerName}}SearchFields[field] {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "unknown search field: " + field})
+					return
+				}
+				fields = append(fields, field)
+			}
+		}
+{{- end}}
+
+{{- define "searchError"}}
+		if errors.Is(err, search.ErrDisabled) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+			return
+		}
+{{- end}}
+`
+
+const algoliaTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package search
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/algolia/algoliasearch-client-go/v3/algolia/opt"
+	algolia "github.com/algolia/algoliasearch-client-go/v3/algolia/search"
+)
+
+// 每次搜索最多返回的记录数
+const maxResults = 50
+
+// ErrDisabled 表示未配置 ALGOLIA_APP_ID
+var ErrDisabled = errors.New("search: algolia is not configured")
+
+var (
+	client      *algolia.Client
+	indexPrefix string
+)
+
+// Configure 使用 ALGOLIA_APP_ID、ALGOLIA_API_KEY 创建客户端，启动时调用一次；索引名前加上 ALGOLIA_INDEX_PREFIX，
+// 以便多个环境共用一个 Algolia 应用。APP_ID 为空时不同步索引，搜索返回 ErrDisabled
+func Configure(appID, apiKey, prefix string) {
+	if appID == "" {
+		return
+	}
+	client = algolia.NewClient(appID, apiKey)
+	indexPrefix = prefix
+}
+
+// ObjectID 返回主键对应的 Algolia objectID
+func ObjectID(id interface{}) string {
+	return fmt.Sprint(id)
+}
+
+// IndexRecord 以 id 写入或覆盖记录，记录内容为 record 的 JSON
+func IndexRecord(index, id string, record interface{}) error {
+	if client == nil {
+		return nil
+	}
+	object, err := toObject(id, record)
+	if err != nil {
+		return err
+	}
+	_, err = client.InitIndex(indexPrefix + index).SaveObject(object)
+	return err
+}
+
+// UpdateRecord 只更新 record 中的属性，保留在 Algolia 控制台中为记录添加的其他属性，记录不存在时创建
+func UpdateRecord(index, id string, record interface{}) error {
+	if client == nil {
+		return nil
+	}
+	object, err := toObject(id, record)
+	if err != nil {
+		return err
+	}
+	_, err = client.InitIndex(indexPrefix+index).PartialUpdateObject(object, opt.CreateIfNotExists(true))
+	return err
+}
+
+// DeleteRecord 删除记录，记录不存在时不报错
+func DeleteRecord(index, id string) error {
+	if client == nil {
+		return nil
+	}
+	_, err := client.InitIndex(indexPrefix + index).DeleteObject(id)
+	return err
+}
+
+// Search 在 fields 中全文匹配 q，fields 为空时匹配索引设置中的所有可搜索属性，按相关度返回 objectID
+func Search(index, q string, fields []string) ([]string, error) {
+	if client == nil {
+		return nil, ErrDisabled
+	}
+
+	opts := []interface{}{
+		opt.AttributesToRetrieve("objectID"),
+		opt.HitsPerPage(maxResults),
+	}
+	if len(fields) > 0 {
+		opts = append(opts, opt.RestrictSearchableAttributes(fields...))
+	}
+	res, err := client.InitIndex(indexPrefix+index).Search(q, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var hits []struct {
+		ObjectID string ` + "`json:\"objectID\"`" + `
+	}
+	if err := res.UnmarshalHits(&hits); err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ObjectID)
+	}
+	return ids, nil
+}
+
+// 把记录转换为带 objectID 的 Algolia 对象
+func toObject(id string, record interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	object["objectID"] = id
+	return object, nil
+}
+`
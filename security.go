@@ -0,0 +1,286 @@
+package main
+
+// 请求限制与安全相关的中间件模板
+
+const bodyLimitTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package middlewares
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimit 限制请求体大小，超过 maxBytes 时返回 413
+// 请求体会先读入内存，因此分块传输、未声明 Content-Length 的请求同样受限
+func BodyLimit(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > maxBytes {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body too large"})
+			return
+		}
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxBytes+1))
+		c.Request.Body.Close()
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		if int64(len(body)) > maxBytes {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body too large"})
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
+`
+
+const timeoutTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package middlewares
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-contrib/timeout"
+	"github.com/gin-gonic/gin"
+{{- if .Project.ChatEnabled}}
+	"github.com/gorilla/websocket"
+{{- end}}
+)
+
+// Timeout 为每个请求设置超时，超时后返回 503{{if .Project.ChatEnabled}}；WebSocket 连接不受限制{{end}}
+func Timeout(d time.Duration) gin.HandlerFunc {
+{{- if .Project.ChatEnabled}}
+	limited := timeout.New(
+{{- else}}
+	return timeout.New(
+{{- end}}
+		timeout.WithTimeout(d),
+		timeout.WithHandler(func(c *gin.Context) {
+			c.Next()
+		}),
+		timeout.WithResponse(func(c *gin.Context) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "request timeout"})
+		}),
+	)
+{{- if .Project.ChatEnabled}}
+	return func(c *gin.Context) {
+		// 超时中间件缓冲响应，无法升级为 WebSocket 连接
+		if websocket.IsWebSocketUpgrade(c.Request) {
+			c.Next()
+			return
+		}
+		limited(c)
+	}
+{{- end}}
+}
+`
+
+const rateLimitTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package middlewares
+
+import (
+	"net/http"
+{{- if .Project.RateLimitByAPIKey}}
+	"strings"
+{{- end}}
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+// 超过该时长没有请求的客户端，其限流器会被清理
+const limiterIdleTTL = 10 * time.Minute
+
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen atomic.Int64 // 最近一次请求的 Unix 时间
+}
+
+// RateLimit 使用令牌桶限制每个客户端的请求速率，每秒补充 rps 个令牌，最多积累 burst 个，
+// 令牌用完时返回 429。{{if .Project.RateLimitByAPIKey}}X-API-Key 属于 apiKeys（逗号分隔）的请求按 API key 限流，
+// 其余请求（包括带未知 key 的请求）按 c.ClientIP() 限流{{else}}按 c.ClientIP() 区分客户端{{end}}
+func RateLimit(rps float64, burst int{{if .Project.RateLimitByAPIKey}}, apiKeys string{{end}}) gin.HandlerFunc {
+	var limiters sync.Map // 客户端标识 -> *clientLimiter，首次请求时创建
+	go evictIdleLimiters(&limiters)
+{{- if .Project.RateLimitByAPIKey}}
+
+	knownKeys := make(map[string]bool)
+	for _, key := range strings.Split(apiKeys, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			knownKeys[key] = true
+		}
+	}
+{{- end}}
+
+	return func(c *gin.Context) {
+		key := rateLimitKey(c{{if .Project.RateLimitByAPIKey}}, knownKeys{{end}})
+		value, ok := limiters.Load(key)
+		if !ok {
+			value, _ = limiters.LoadOrStore(key, &clientLimiter{limiter: rate.NewLimiter(rate.Limit(rps), burst)})
+		}
+		client := value.(*clientLimiter)
+		client.lastSeen.Store(time.Now().Unix())
+
+		if !client.limiter.Allow() {
+			c.Header("Retry-After", "1")
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "too many requests"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// 限流的客户端标识，加上前缀避免 API key 与 IP 地址相同时共用限流器
+{{- if .Project.RateLimitByAPIKey}}
+// 只有已知的 API key 单独限流，否则客户端每次请求换一个 key 就能绕过限流
+func rateLimitKey(c *gin.Context, knownKeys map[string]bool) string {
+	if apiKey := c.GetHeader("X-API-Key"); knownKeys[apiKey] {
+		return "key:" + apiKey
+	}
+	return "ip:" + c.ClientIP()
+}
+{{- else}}
+func rateLimitKey(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+{{- end}}
+
+// 每分钟清理一次长时间没有请求的客户端，避免限流器随客户端数量无限增长
+func evictIdleLimiters(limiters *sync.Map) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		cutoff := time.Now().Add(-limiterIdleTTL).Unix()
+		limiters.Range(func(key, value any) bool {
+			if value.(*clientLimiter).lastSeen.Load() < cutoff {
+				limiters.Delete(key)
+			}
+			return true
+		})
+	}
+}
+`
+
+const securityHeadersTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package middlewares
+
+import "github.com/gin-gonic/gin"
+
+// 未配置 CONTENT_SECURITY_POLICY 时使用的内容安全策略
+const defaultContentSecurityPolicy = "default-src 'self'"
+
+// SecurityHeaders 为每个响应设置常用的安全响应头，csp 为空时使用默认策略
+func SecurityHeaders(csp string) gin.HandlerFunc {
+	if csp == "" {
+		csp = defaultContentSecurityPolicy
+	}
+	return func(c *gin.Context) {
+		h := c.Writer.Header()
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("X-Frame-Options", "DENY")
+		h.Set("X-XSS-Protection", "1; mode=block")
+		h.Set("Strict-Transport-Security", "max-age=31536000")
+		h.Set("Content-Security-Policy", csp)
+		c.Next()
+	}
+}
+`
+
+const ipFilterTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package middlewares
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IPFilter 按 c.ClientIP() 过滤请求，名单为逗号分隔的 CIDR 或单个IP
+// 白名单非空时只允许名单内的IP，黑名单内的IP总是被拒绝，被拒绝的请求返回 403
+func IPFilter(whitelist, blacklist string) (gin.HandlerFunc, error) {
+	allowed, err := parseCIDRList(whitelist)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IP_WHITELIST: %w", err)
+	}
+	blocked, err := parseCIDRList(blacklist)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IP_BLACKLIST: %w", err)
+	}
+
+	return func(c *gin.Context) {
+		ip := net.ParseIP(c.ClientIP())
+		if ip == nil || (len(allowed) > 0 && !containsIP(allowed, ip)) || containsIP(blocked, ip) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+			return
+		}
+		c.Next()
+	}, nil
+}
+
+func parseCIDRList(list string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		// 单个IP视为只包含该地址的网段
+		if !strings.Contains(item, "/") {
+			if ip := net.ParseIP(item); ip != nil && ip.To4() != nil {
+				item += "/32"
+			} else {
+				item += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+`
+
+const devCertScriptTemplate = `#!/bin/sh
+# 生成本地开发用的自签名证书，路径与 .env 中的 TLS_CERT_FILE、TLS_KEY_FILE 一致
+set -e
+
+DEFAULT_CERT_FILE={{shellQuote .Project.TLSCertFile}}
+DEFAULT_KEY_FILE={{shellQuote .Project.TLSKeyFile}}
+CERT_FILE="${TLS_CERT_FILE:-$DEFAULT_CERT_FILE}"
+KEY_FILE="${TLS_KEY_FILE:-$DEFAULT_KEY_FILE}"
+
+mkdir -p "$(dirname "$CERT_FILE")" "$(dirname "$KEY_FILE")"
+openssl req -x509 -newkey rsa:2048 -nodes -sha256 -days 365 \
+  -keyout "$KEY_FILE" -out "$CERT_FILE" \
+  -subj "/CN=localhost" \
+  -addext "subjectAltName=DNS:localhost,IP:127.0.0.1"
+
+echo "已生成 $CERT_FILE 和 $KEY_FILE"
+`
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 种子数据相关模板
+
+// 生成字段的随机值表达式，无法生成时返回空字符串
+func fakeValue(f ModelField) string {
+	name := strings.ToLower(f.Name)
+	if name == "id" || strings.Contains(f.GormTag, "primaryKey") {
+		return ""
+	}
+
+	switch f.Type {
+	case "string":
+		return fitLength(fakeString(name), f)
+	// 必填字段的零值无法通过模型的 Validate 检查
+	case "int":
+		return nonZero("rand.Intn(1000)", f)
+	case "int32":
+		return nonZero("rand.Int31n(1000)", f)
+	case "int64":
+		return nonZero("rand.Int63n(1000)", f)
+	case "uint", "uint32", "uint64":
+		return nonZero(f.Type+"(rand.Intn(1000))", f)
+	case "float32":
+		return "rand.Float32() * 1000"
+	case "float64":
+		return "rand.Float64() * 1000"
+	case "bool":
+		return "rand.Intn(2) == 1"
+	case "time.Time":
+		return "time.Now().Add(-time.Duration(rand.Intn(365*24)) * time.Hour)"
+	case "uuid.UUID":
+		return "uuid.New()"
+	}
+	return ""
+}
+
+// 按字段名选择 faker 生成的字符串
+func fakeString(name string) string {
+	switch {
+	case strings.Contains(name, "email"):
+		return "faker.Email()"
+	case strings.Contains(name, "phone"):
+		return "faker.Phonenumber()"
+	case strings.Contains(name, "url"), strings.Contains(name, "website"):
+		return "faker.URL()"
+	case strings.Contains(name, "username"):
+		return "faker.Username()"
+	case strings.Contains(name, "firstname"), strings.Contains(name, "first_name"):
+		return "faker.FirstName()"
+	case strings.Contains(name, "lastname"), strings.Contains(name, "last_name"):
+		return "faker.LastName()"
+	case strings.Contains(name, "name"):
+		return "faker.Name()"
+	case strings.Contains(name, "password"):
+		return "faker.Password()"
+	case strings.Contains(name, "title"):
+		return "faker.Sentence()"
+	case strings.Contains(name, "description"), strings.Contains(name, "body"), strings.Contains(name, "content"):
+		return "faker.Paragraph()"
+	default:
+		return "faker.Word()"
+	}
+}
+
+// 按 minlen、maxlen 用空格补齐或截断，fmt 的宽度和精度都按字符计算
+func fitLength(expr string, f ModelField) string {
+	switch {
+	case f.MinLength > 0 && f.MaxLength > 0:
+		return fmt.Sprintf("fmt.Sprintf(\"%%-*.*s\", %d, %d, %s)", f.MinLength, f.MaxLength, expr)
+	case f.MinLength > 0:
+		return fmt.Sprintf("fmt.Sprintf(\"%%-*s\", %d, %s)", f.MinLength, expr)
+	case f.MaxLength > 0:
+		return fmt.Sprintf("fmt.Sprintf(\"%%.*s\", %d, %s)", f.MaxLength, expr)
+	}
+	return expr
+}
+
+func nonZero(expr string, f ModelField) string {
+	if f.Required {
+		return "1 + " + expr
+	}
+	return expr
+}
+
+// 根据模型字段计算种子文件需要的额外导入
+func seedImports(m Model) []string {
+	set := map[string]bool{}
+	// 字符串主键不会自动生成，种子数据需要自行填充
+	if m.IDType == "string" {
+		set["github.com/bxcodec/faker/v3"] = true
+	}
+	for _, f := range m.Fields {
+		expr := fakeValue(f)
+		if strings.Contains(expr, "faker.") {
+			set["github.com/bxcodec/faker/v3"] = true
+		}
+		if strings.HasPrefix(expr, "fmt.") {
+			set["fmt"] = true
+		}
+		if strings.Contains(expr, "rand.") {
+			set["math/rand"] = true
+		}
+		if strings.Contains(expr, "time.") {
+			set["time"] = true
+		}
+		if strings.Contains(expr, "uuid.") {
+			set["github.com/google/uuid"] = true
+		}
+	}
+
+	imports := make([]string, 0, len(set))
+	for imp := range set {
+		imports = append(imports, imp)
+	}
+	// 标准库在前，第三方包在后
+	sort.Slice(imports, func(i, j int) bool {
+		iStd, jStd := !strings.Contains(imports[i], "."), !strings.Contains(imports[j], ".")
+		if iStd != jStd {
+			return iStd
+		}
+		return imports[i] < imports[j]
+	})
+	return imports
+}
+
+const seedTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package seeds
+
+import (
+{{- range seedImports .Model}}
+	"{{.}}"
+{{- end}}
+
+	"gorm.io/gorm"
+
+	"{{.Project.ModuleName}}/pkg/models"
+)
+
+// Seed{{.Model.Name}} 写入 count 条随机 {{.Model.Name}} 记录，表中已有数据时跳过
+func Seed{{.Model.Name}}(db *gorm.DB, count int) (int, error) {
+	var existing int64
+	if err := db.Model(&models.{{.Model.Name}}{}).Count(&existing).Error; err != nil {
+		return 0, err
+	}
+	if existing > 0 {
+		return 0, nil
+	}
+
+	records := make([]models.{{.Model.Name}}, 0, count)
+	for i := 0; i < count; i++ {
+		records = append(records, models.{{.Model.Name}}{
+{{- if eq .Model.IDType "string"}}
+			ID: faker.UUIDHyphenated(),
+{{- end}}
+{{- range $field := .Model.Fields}}
+{{- with fakeValue $field}}
+			{{$field.Name}}: {{.}},
+{{- end}}
+{{- end}}
+		})
+	}
+
+	if err := db.CreateInBatches(records, 100).Error; err != nil {
+		return 0, err
+	}
+	return len(records), nil
+}
+`
+
+const seedCommandTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package main
+
+import (
+	"flag"
+	"log"
+
+	"{{.Project.ModuleName}}/pkg/config"
+	"{{.Project.ModuleName}}/pkg/database"
+	"{{.Project.ModuleName}}/pkg/seeds"
+)
+
+func main() {
+	count := flag.Int("count", {{.Project.SeedCount}}, "每个模型写入的记录数")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Error loading config: %v", err)
+	}
+
+	db, err := database.InitDB(cfg)
+	if err != nil {
+		log.Fatalf("Error initializing database: %v", err)
+	}
+{{range .Models}}
+	if n, err := seeds.Seed{{.Name}}(db, *count); err != nil {
+		log.Fatalf("Error seeding {{.Name}}: %v", err)
+	} else if n == 0 {
+		log.Println("{{.Name}}: records already exist, skipped")
+	} else {
+		log.Printf("{{.Name}}: seeded %d records", n)
+	}
+{{end -}}
+}
+`
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 分享链接的签名密钥，见 loadShareSecret
+var shareSecret string
+
+var errInvalidShareToken = errors.New("分享链接无效或已被篡改")
+
+// 读取 SHARE_SECRET；未设置时随机生成，服务器重启后之前的分享链接失效
+func loadShareSecret() string {
+	if secret := os.Getenv("SHARE_SECRET"); secret != "" {
+		return secret
+	}
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		log.Fatalf("无法生成分享链接密钥: %v", err)
+	}
+	log.Println("未设置 SHARE_SECRET，使用随机密钥，重启后分享链接失效")
+	return base64.RawURLEncoding.EncodeToString(b[:])
+}
+
+func signShare(payload []byte, secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// 分享链接 /s/<token>，token 为 base64url 编码的 TemplateData JSON 和 HMAC-SHA256 签名，以 . 分隔
+func encodeShareURL(data TemplateData, secret string) string {
+	// TemplateData 只包含基本类型，序列化不会失败
+	payload, _ := json.Marshal(data)
+	return "/s/" + base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(signShare(payload, secret))
+}
+
+// 校验签名并解析 token，token 可以带 /s/ 前缀
+func decodeShareURL(token, secret string) (TemplateData, error) {
+	var data TemplateData
+	encodedPayload, encodedSig, ok := strings.Cut(strings.TrimPrefix(token, "/s/"), ".")
+	if !ok {
+		return data, errInvalidShareToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return data, errInvalidShareToken
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(encodedSig)
+	if err != nil || !hmac.Equal(sig, signShare(payload, secret)) {
+		return data, errInvalidShareToken
+	}
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return data, errInvalidShareToken
+	}
+	return data, nil
+}
+
+// POST /share：参数与 /generate 相同，校验后以 JSON 返回分享链接而不生成项目。
+// 分享链接包含完整配置，可能超过代理的响应头大小限制，只通过这里的响应体返回
+func createShareURL(c *gin.Context) {
+	data, ok := bindTemplateData(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"share_url": encodeShareURL(data, shareSecret)})
+}
+
+// GET /s/:token：打开预填了分享配置的表单
+func openShareURL(c *gin.Context) {
+	data, err := decodeShareURL(c.Param("token"), shareSecret)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.HTML(http.StatusOK, "index.html", gin.H{
+		"title":        "Gin CRUD 代码生成器",
+		"prefill":      exportFormFields(data),
+		"goSumAllowed": goSumAllowed,
+	})
+}
@@ -0,0 +1,115 @@
+package main
+
+// Twilio 短信模板，TwilioEnabled 启用时生成 pkg/notifications/sms.go、
+// POST /notifications/sms 接口和记录发送结果的 SMSLog 模型
+
+const smsLogModelName = "SMSLog"
+
+// addSMSLogModel 为 SMSLog 模型补充的字段
+var smsLogModelFields = []injectedField{
+	{"Recipient", "string", true},
+	{"Body", "string", true},
+	{"Status", "string", false},
+	{"Error", "string", false},
+}
+
+// 添加 SMSLog 模型，已存在时只补充缺少的字段
+func addSMSLogModel(data *TemplateData) error {
+	_, err := addInjectedModel(data, smsLogModelName, smsLogModelFields)
+	return err
+}
+
+const smsTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package notifications
+
+import (
+	"log"
+
+	"github.com/twilio/twilio-go"
+	twilioapi "github.com/twilio/twilio-go/rest/api/v2010"
+)
+
+var (
+	twilioClient     *twilio.RestClient
+	twilioFromNumber string
+)
+
+// ConfigureTwilio 设置 TWILIO_ACCOUNT_SID、TWILIO_AUTH_TOKEN 和 TWILIO_FROM_NUMBER，启动时调用一次。
+// Account SID 为空时短信只输出到日志
+func ConfigureTwilio(accountSID, authToken, fromNumber string) {
+	twilioFromNumber = fromNumber
+	if accountSID == "" {
+		return
+	}
+	twilioClient = twilio.NewRestClientWithParams(twilio.ClientParams{
+		Username: accountSID,
+		Password: authToken,
+	})
+}
+
+// SendSMS 向 E.164 格式的号码 (+8613800000000) 发送短信
+func SendSMS(to, body string) error {
+	if twilioClient == nil {
+		log.Printf("sms to=%s\n%s", to, body)
+		return nil
+	}
+
+	params := &twilioapi.CreateMessageParams{}
+	params.SetTo(to)
+	params.SetFrom(twilioFromNumber)
+	params.SetBody(body)
+	_, err := twilioClient.Api.CreateMessage(params)
+	return err
+}
+`
+
+const smsHandlerTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"{{.Project.ModuleName}}/pkg/models"
+	"{{.Project.ModuleName}}/pkg/notifications"
+)
+
+type sendSMSInput struct {
+	To   string ` + "`json:\"to\" binding:\"required,e164\"`" + `
+	Body string ` + "`json:\"body\" binding:\"required,max=1600\"`" + `
+}
+
+// RegisterSMSRoutes 注册 POST /sms，发送短信并保存 SMSLog 记录，发送失败时同样记录
+func RegisterSMSRoutes(rg *gin.RouterGroup, db *gorm.DB) {
+	rg.POST("/sms", sendSMS(db))
+}
+
+func sendSMS(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input sendSMSInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		smsLog := models.SMSLog{Recipient: input.To, Body: input.Body, Status: "sent"}
+		sendErr := notifications.SendSMS(input.To, input.Body)
+		if sendErr != nil {
+			smsLog.Status = "failed"
+			smsLog.Error = sendErr.Error()
+		}
+		if result := db.WithContext(c.Request.Context()).Create(&smsLog); result.Error != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": result.Error.Error()})
+			return
+		}
+
+		if sendErr != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": sendErr.Error(), "log": smsLog})
+			return
+		}
+		c.JSON(http.StatusCreated, smsLog)
+	}
+}
+`
@@ -0,0 +1,20 @@
+// 打开分享链接时，按 /export-config 格式的字段预填表单
+(function () {
+    var fields = window.prefillFields;
+    if (!fields) {
+        return;
+    }
+    var form = document.querySelector('form');
+    Object.keys(fields).forEach(function (name) {
+        var element = form.elements[name];
+        if (!element) {
+            return;
+        }
+        if (element.type === 'checkbox') {
+            element.checked = fields[name] === 'on';
+        } else {
+            element.value = fields[name];
+        }
+    });
+    document.getElementById('models').dispatchEvent(new Event('input'));
+})();
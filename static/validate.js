@@ -0,0 +1,48 @@
+// 模型定义输入停止 500ms 后调用 POST /validate，在输入框下方显示错误
+(function () {
+    var textarea = document.getElementById('models');
+    var list = document.getElementById('model-errors');
+    var tagStyle = document.getElementById('json_tag_style');
+    var timer = null;
+    var seq = 0;
+
+    function show(errors) {
+        list.innerHTML = '';
+        errors.forEach(function (message) {
+            var item = document.createElement('li');
+            item.textContent = message;
+            list.appendChild(item);
+        });
+        list.hidden = errors.length === 0;
+        textarea.classList.toggle('invalid', errors.length > 0);
+    }
+
+    function validate() {
+        var current = ++seq;
+        var body = new URLSearchParams();
+        body.set('models', textarea.value);
+        if (tagStyle) {
+            body.set('json_tag_style', tagStyle.value);
+        }
+        fetch('/validate', { method: 'POST', body: body })
+            .then(function (resp) { return resp.json(); })
+            .then(function (result) {
+                // 忽略已被后续输入取代的响应
+                if (current !== seq) {
+                    return;
+                }
+                show(result.errors || (result.error ? [result.error] : []));
+            })
+            .catch(function () {});
+    }
+
+    function schedule() {
+        clearTimeout(timer);
+        timer = setTimeout(validate, 500);
+    }
+
+    textarea.addEventListener('input', schedule);
+    if (tagStyle) {
+        tagStyle.addEventListener('change', schedule);
+    }
+})();
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"embed"
+	"io/fs"
+	"path"
+)
+
+// 内嵌到生成项目中的 Swagger UI 静态文件，通过 scripts/update-swagger-ui.sh 从 swagger-ui-dist 更新
+//
+//go:embed static/swagger-ui
+var swaggerUIAssets embed.FS
+
+// 生成 docs/swagger-ui 及 docs/embed.go，生成的服务器在 /docs/ 提供不依赖外部 CDN 的 API 文档
+type swaggerUIGenerator struct {
+	templateGenerator
+}
+
+var swaggerGenerator = swaggerUIGenerator{templateGenerator{
+	name: "swagger-ui",
+	files: func(data TemplateData) map[string]string {
+		files := map[string]string{}
+		if data.Project.SwaggerUI {
+			files["docs/embed.go"] = "docs_embed.go.tmpl"
+			files["docs/swagger-ui/swagger-initializer.js"] = "swagger-initializer.js.tmpl"
+		}
+		return files
+	},
+	modelFiles: func(data TemplateData, model Model) map[string]string {
+		files := map[string]string{}
+		if data.Project.SwaggerUI {
+			files["docs/swagger-ui/specs/"+model.SnakeName+".yaml"] = "api_spec.yaml.tmpl"
+		}
+		return files
+	},
+}}
+
+func (g swaggerUIGenerator) Generate(data TemplateData, fsys FileSystem) error {
+	if !data.Project.SwaggerUI {
+		return nil
+	}
+	err := fs.WalkDir(swaggerUIAssets, "static/swagger-ui", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := swaggerUIAssets.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		return fsys.WriteFile(path.Join("docs/swagger-ui", path.Base(p)), content)
+	})
+	if err != nil {
+		return err
+	}
+	return g.templateGenerator.Generate(data, fsys)
+}
+
+const docsEmbedTemplate = `{{with .Project.FileHeader}}{{.}}
+{{end}}package docs
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed swagger-ui
+var swaggerUI embed.FS
+
+// SwaggerUI 返回内嵌的 Swagger UI 文件系统，包含各模型的 OpenAPI 规范
+func SwaggerUI() http.FileSystem {
+	sub, err := fs.Sub(swaggerUI, "swagger-ui")
+	if err != nil {
+		panic(err)
+	}
+	return http.FS(sub)
+}
+`
+
+const swaggerInitializerTemplate = `window.onload = function () {
+  window.ui = SwaggerUIBundle({
+    urls: [
+{{- range .Models}}
+      { url: "./specs/{{.SnakeName}}.yaml", name: "{{.Name}}" },
+{{- end}}
+    ],
+    dom_id: "#swagger-ui",
+    deepLinking: true,
+    presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+    layout: "StandaloneLayout",
+  });
+};
+`
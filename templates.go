@@ -0,0 +1,203 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// 自定义模板目录，存在与内置模板同名的文件时，用其中的 define 覆盖内置模板的 block
+const customTemplateDir = "custom_templates"
+
+// 内置模板，按名称索引
+var builtinTemplates = map[string]string{
+	"main.go.tmpl":                   mainTemplate,
+	"config.go.tmpl":                 configTemplate,
+	"database.go.tmpl":               databaseTemplate,
+	"server.go.tmpl":                 serverTemplate,
+	"logger.go.tmpl":                 loggerMiddlewareTemplate,
+	"env.tmpl":                       envTemplate,
+	"go.mod.tmpl":                    goModTemplate,
+	"README.md.tmpl":                 readmeTemplate,
+	"Dockerfile.tmpl":                dockerfileTemplate,
+	"gitignore.tmpl":                 gitignoreTemplate,
+	"Makefile.tmpl":                  makefileTemplate,
+	"model.go.tmpl":                  modelTemplate,
+	"handler.go.tmpl":                handlerTemplate,
+	"api_spec.yaml.tmpl":             apiSpecTemplate,
+	"seed.go.tmpl":                   seedTemplate,
+	"seed_command.go.tmpl":           seedCommandTemplate,
+	"migrate_command.go.tmpl":        migrateCommandTemplate,
+	"rbac.go.tmpl":                   rbacMiddlewareTemplate,
+	"rbac_test.go.tmpl":              rbacTestTemplate,
+	"session.go.tmpl":                sessionMiddlewareTemplate,
+	"session_auth_handler.go.tmpl":   sessionAuthHandlerTemplate,
+	"password_hook.go.tmpl":          passwordHookTemplate,
+	"totp.go.tmpl":                   totpTemplate,
+	"magiclink.go.tmpl":              magicLinkTemplate,
+	"email_sender.go.tmpl":           emailSenderTemplate,
+	"token.go.tmpl":                  tokenMiddlewareTemplate,
+	"magiclink_auth_handler.go.tmpl": magicLinkAuthHandlerTemplate,
+	"datadog.go.tmpl":                datadogTemplate,
+	"sentry.go.tmpl":                 sentryTemplate,
+	"metrics.go.tmpl":                otelMetricsTemplate,
+	"tracer.go.tmpl":                 otelTracerTemplate,
+	"otlp.go.tmpl":                   otlpExporterTemplate,
+	"jaeger.go.tmpl":                 jaegerExporterTemplate,
+	"handler_language.go.tmpl":       handlerLanguageTemplate,
+	"i18n_messages.go.tmpl":          i18nMessagesTemplate,
+	"erd.md.tmpl":                    mermaidERDTemplate,
+	"zipkin.go.tmpl":                 zipkinExporterTemplate,
+	"loki.go.tmpl":                   lokiLoggerTemplate,
+	"history_model.go.tmpl":          historyModelTemplate,
+	"handler_bench_test.go.tmpl":     handlerBenchTemplate,
+	"body_limit.go.tmpl":             bodyLimitTemplate,
+	"rate_limit.go.tmpl":             rateLimitTemplate,
+	"quota.go.tmpl":                  quotaTemplate,
+	"admin.go.tmpl":                  adminHandlerTemplate,
+	"stripe.go.tmpl":                 stripeTemplate,
+	"payment_handler.go.tmpl":        paymentHandlerTemplate,
+	"notifications_email.go.tmpl":    notificationsEmailTemplate,
+	"email_embed.go.tmpl":            emailEmbedTemplate,
+	"slack.go.tmpl":                  slackTemplate,
+	"fcm.go.tmpl":                    fcmTemplate,
+	"notification_send.go.tmpl":      notificationSendHandlerTemplate,
+	"sms.go.tmpl":                    smsTemplate,
+	"sms_handler.go.tmpl":            smsHandlerTemplate,
+	"notification_publisher.go.tmpl": notificationPublisherTemplate,
+	"notification_center.go.tmpl":    notificationCenterHandlerTemplate,
+	"event_store.go.tmpl":            eventStoreTemplate,
+	"event_commands.go.tmpl":         eventCommandsTemplate,
+	"event_events.go.tmpl":           eventEventsTemplate,
+	"event_store.sql.tmpl":           eventStoreMigrationTemplate,
+	"cqrs_commands.go.tmpl":          cqrsCommandsTemplate,
+	"cqrs_queries.go.tmpl":           cqrsQueriesTemplate,
+	"chat_hub.go.tmpl":               chatHubTemplate,
+	"chat_client.go.tmpl":            chatClientTemplate,
+	"chat_handler.go.tmpl":           chatHandlerTemplate,
+	"elasticsearch.go.tmpl":          elasticsearchTemplate,
+	"algolia.go.tmpl":                algoliaTemplate,
+	"search_handler.go.tmpl":         searchHandlerTemplate,
+	"timeout.go.tmpl":                timeoutTemplate,
+	"security.go.tmpl":               securityHeadersTemplate,
+	"ipfilter.go.tmpl":               ipFilterTemplate,
+	"generate-dev-cert.sh.tmpl":      devCertScriptTemplate,
+	"prefetch.go.tmpl":               prefetchTemplate,
+	"docs_embed.go.tmpl":             docsEmbedTemplate,
+	"swagger-initializer.js.tmpl":    swaggerInitializerTemplate,
+	"terraform_main.tf.tmpl":         terraformMainTemplate,
+	"terraform_variables.tf.tmpl":    terraformVariablesTemplate,
+	"terraform_outputs.tf.tmpl":      terraformOutputsTemplate,
+	"Pulumi.yaml.tmpl":               pulumiProjectTemplate,
+	"pulumi_go.mod.tmpl":             pulumiGoModTemplate,
+	"pulumi_main.go.tmpl":            pulumiMainTemplate,
+	"helm_chart.yaml.tmpl":           helmChartYAMLTemplate,
+	"helm_values.yaml.tmpl":          helmValuesTemplate,
+	"k8s_namespace.yaml.tmpl":        k8sNamespaceTemplate,
+	"k8s_deployment.yaml.tmpl":       k8sDeploymentTemplate,
+	"k8s_service.yaml.tmpl":          k8sServiceTemplate,
+	"virtualservice.yaml.tmpl":       istioVirtualServiceTemplate,
+	"destinationrule.yaml.tmpl":      istioDestinationRuleTemplate,
+	"client_cert.go.tmpl":            clientCertTemplate,
+	"consul.go.tmpl":                 consulTemplate,
+	"consul_config.go.tmpl":          consulConfigTemplate,
+	"grpc_health.go.tmpl":            grpcHealthTemplate,
+	"skaffold.yaml.tmpl":             skaffoldTemplate,
+	"argocd_application.yaml.tmpl":   argoCDApplicationTemplate,
+	"ci.yml.tmpl":                    ciWorkflowTemplate,
+	"release.yml.tmpl":               releaseWorkflowTemplate,
+	"bug_report.yml.tmpl":            bugReportTemplate,
+	"feature_request.yml.tmpl":       featureRequestTemplate,
+	"PULL_REQUEST_TEMPLATE.md.tmpl":  pullRequestTemplate,
+	"pre-commit-config.yaml.tmpl":    preCommitConfigTemplate,
+	"renovaterc.json.tmpl":           renovateConfigTemplate,
+	"chglog_config.yml.tmpl":         chglogConfigTemplate,
+	"CHANGELOG.md.tmpl":              changelogStubTemplate,
+	"CONTRIBUTING.md.tmpl":           contributingTemplate,
+	"CODE_OF_CONDUCT.md.tmpl":        codeOfConductTemplate,
+	"history_context.go.tmpl":        historyContextTemplate,
+	"validation.go.tmpl":             validationErrorTemplate,
+	"history_migration.sql.tmpl":     historyMigrationTemplate,
+	"migration.sql.tmpl":             modelMigrationTemplate,
+	"go.work.tmpl":                   goWorkTemplate,
+	"workspace_Makefile.tmpl":        workspaceMakefileTemplate,
+}
+
+// 模板分隔符，零值即默认的 {{ }}
+// 自定义模板需要原样输出 {{ }}（如 Vue、Angular 模板）时可改用 [[ ]]
+type TemplateDelims struct {
+	Left, Right string
+}
+
+// 解析 template_delims 参数: 空或 "default" 为 {{ }}，"square" 为 [[ ]]
+func parseTemplateDelims(value string) (TemplateDelims, error) {
+	switch value {
+	case "", "default":
+		return TemplateDelims{Left: "{{", Right: "}}"}, nil
+	case "square":
+		return TemplateDelims{Left: "[[", Right: "]]"}, nil
+	default:
+		return TemplateDelims{}, fmt.Errorf("不支持的模板分隔符 %q，可选: default, square", value)
+	}
+}
+
+// 解析基础模板，再用 override 中的 {{define}} 覆盖同名 {{block}}，未覆盖的 block 沿用基础模板
+// 内置基础模板始终使用 {{ }}，delims 只作用于 override
+func loadTemplateWithInheritance(base, override string, delims TemplateDelims) (*template.Template, error) {
+	tmpl, err := template.New("base").Funcs(templateFuncs).Parse(base)
+	if err != nil {
+		return nil, err
+	}
+	if override != "" {
+		if _, err := tmpl.New("override").Delims(delims.Left, delims.Right).Parse(override); err != nil {
+			return nil, err
+		}
+	}
+	return tmpl, nil
+}
+
+// 读取自定义模板，不存在时返回空字符串
+func loadCustomTemplate(name string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(customTemplateDir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// 按名称渲染内置模板，并合并同名自定义模板
+func renderBuiltinTemplate(name string, delims TemplateDelims, data interface{}) ([]byte, error) {
+	base, ok := builtinTemplates[name]
+	if !ok {
+		return nil, fmt.Errorf("未知的模板 %s", name)
+	}
+
+	override, err := loadCustomTemplate(name)
+	if err != nil {
+		return nil, fmt.Errorf("无法读取自定义模板 %s: %w", name, err)
+	}
+
+	tmpl, err := loadTemplateWithInheritance(base, override, delims)
+	if err != nil {
+		return nil, fmt.Errorf("无法解析模板 %s: %w", name, err)
+	}
+	return executeTemplate(name, tmpl, data)
+}
+
+// 使用内置模板生成单个文件
+func generateBuiltinFile(fs FileSystem, filePath, name string, delims TemplateDelims, data interface{}) error {
+	content, err := renderBuiltinTemplate(name, delims, data)
+	if err != nil {
+		return err
+	}
+
+	if err := fs.WriteFile(filePath, content); err != nil {
+		return fmt.Errorf("无法写入文件 %s: %w", filePath, err)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// 有代表性的配置：Session 认证、两步验证、RBAC、限流、IP 白名单、事件溯源和版本历史
+var representativeFields = map[string]string{
+	"project_name":   "shop",
+	"module_name":    "example.com/shop",
+	"port":           "9000",
+	"auth_mode":      "session",
+	"totp":           "on",
+	"rbac":           "on",
+	"rate_limit_rps": "10",
+	"ip_whitelist":   "10.0.0.0/8",
+	"seed":           "on",
+	"event_sourcing": "on",
+	"models":         "User\nemail string required\npassword string required\n@perm DELETE admin\n\nProduct\nname string required maxlen:100\nprice float64\n@history",
+}
+
+func representativeTemplateData(t *testing.T) TemplateData {
+	t.Helper()
+	data, err := templateDataFromFields(representativeFields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestRenderBuiltinTemplates(t *testing.T) {
+	data := representativeTemplateData(t)
+
+	tests := []struct {
+		template string
+		model    string // 非空时按模型级模板渲染
+		want     []string
+		notWant  []string
+	}{
+		{template: "main.go.tmpl", want: []string{"package main", "func main() {"}},
+		{template: "go.mod.tmpl", want: []string{"module example.com/shop", "go " + defaultGoVersion, "github.com/gin-gonic/gin"}},
+		{template: "config.go.tmpl", want: []string{"type Config struct", `mapstructure:"DB_HOST"`, "if IsPlaceholderSecret(cfg.SessionSecret) {"}},
+		{template: "env.tmpl", want: []string{"APP_PORT=9000", "DB_HOST=", "TRUSTED_PROXIES=", "SESSION_SECRET=\n"}, notWant: []string{"SESSION_SECRET=change_me", "ADMIN_PASS=change_me"}},
+		{template: "database.go.tmpl", want: []string{"func InitDB(cfg *config.Config) (*gorm.DB, error)"}},
+		{template: "server.go.tmpl", want: []string{"r.SetTrustedProxies(", "middlewares.IPFilter(", "middlewares.RateLimit("}},
+		{template: "Dockerfile.tmpl", want: []string{"FROM golang:" + defaultGoVersion + "-alpine", "EXPOSE 9000"}},
+		{template: "Makefile.tmpl", want: []string{"go build -o bin/shop", "build: go.sum"}},
+		{template: "README.md.tmpl", want: []string{"shop"}},
+		{template: "rbac.go.tmpl", want: []string{"func UserRole(db *gorm.DB) RoleLookup", "func RequireRole(lookup RoleLookup, roles ...string) gin.HandlerFunc"}},
+		{template: "rbac_test.go.tmpl", want: []string{"func TestRequireRoleAdmin(t *testing.T)", "RequireSession()"}},
+		{template: "session.go.tmpl", want: []string{"func SessionMiddleware(", "func RequireSession() gin.HandlerFunc"}},
+		{template: "totp.go.tmpl", want: []string{"func Validate(code, secret string) bool"}},
+		{template: "password_hook.go.tmpl", want: []string{"func (u User) MarshalJSON() ([]byte, error)", "func (u *User) BeforeSave(tx *gorm.DB) error"}},
+		{template: "ipfilter.go.tmpl", want: []string{"func IPFilter(whitelist, blacklist string) (gin.HandlerFunc, error)"}},
+		{template: "rate_limit.go.tmpl", want: []string{"func RateLimit(rps float64, burst int) gin.HandlerFunc", "func rateLimitKey(c *gin.Context) string"}},
+		{template: "event_store.go.tmpl", want: []string{"func NewEventStore(db *gorm.DB) *EventStore", "func (s *EventStore) Append(event Event) error"}},
+		{template: "event_store.sql.tmpl", want: []string{"CREATE TABLE IF NOT EXISTS `event_store`"}},
+		{template: "model.go.tmpl", model: "Product", want: []string{"type Product struct", `gorm:"column:name" json:"name,omitempty"`, `json:"price"`}},
+		{template: "model.go.tmpl", model: "User", want: []string{"type User struct", "TOTPSecret string", `json:"totp_enabled"`, `gorm:"column:role;default:user" json:"-"`}},
+		{template: "migration.sql.tmpl", model: "Product", want: []string{"CREATE TABLE IF NOT EXISTS `product`"}},
+		// @history 的删除快照来自 AfterDelete 的接收者，删除前必须先查询出记录
+		{template: "handler.go.tmpl", model: "Product", want: []string{"func RegisterProductRoutes(rg *gin.RouterGroup, db *gorm.DB)", "func createProduct(db *gorm.DB) gin.HandlerFunc", "func getProductHistory(", ".First(&product, ", "db.Delete(&product)"}, notWant: []string{"db.Delete(&models.Product{}"}},
+		{template: "api_spec.yaml.tmpl", model: "Product", want: []string{"title: Product API", "/api/v1/Products:"}},
+		{template: "history_model.go.tmpl", model: "Product", want: []string{"type ProductHistory struct", "func (m *Product) AfterCreate(tx *gorm.DB) error"}},
+		{template: "history_migration.sql.tmpl", model: "Product", want: []string{"CREATE TABLE IF NOT EXISTS `product_histories`"}},
+		{template: "event_events.go.tmpl", model: "Product", want: []string{"type ProductCreatedEvent struct", `func (ProductCreatedEvent) EventType() string { return "ProductCreated" }`}},
+		// 事件写入 event_store，不能包含密码哈希
+		{template: "event_events.go.tmpl", model: "User", want: []string{"type UserCreatedEvent struct"}, notWant: []string{"Password"}},
+		{template: "seed.go.tmpl", model: "Product", want: []string{"func SeedProduct(db *gorm.DB, count int) (int, error)"}},
+	}
+
+	for _, tt := range tests {
+		name := tt.template
+		if tt.model != "" {
+			name += "/" + tt.model
+		}
+		t.Run(name, func(t *testing.T) {
+			var tmplData interface{} = data
+			if tt.model != "" {
+				model, ok := findModel(data.Models, tt.model)
+				if !ok {
+					t.Fatalf("没有模型 %s", tt.model)
+				}
+				tmplData = struct {
+					Project ProjectConfig
+					Model   Model
+				}{data.Project, model}
+			}
+
+			content, err := renderBuiltinTemplate(tt.template, data.Delims, tmplData)
+			if err != nil {
+				t.Fatalf("渲染失败: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(content), want) {
+					t.Errorf("输出缺少 %q", want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(string(content), notWant) {
+					t.Errorf("输出不应包含 %q", notWant)
+				}
+			}
+		})
+	}
+}
+
+// 所有内置模板都能解析，覆盖上表没有列出的模板
+func TestBuiltinTemplatesParse(t *testing.T) {
+	for name, content := range builtinTemplates {
+		if _, err := loadTemplateWithInheritance(content, "", TemplateDelims{}); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func findModel(models []Model, name string) (Model, bool) {
+	for _, m := range models {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return Model{}, false
+}
+
+// @paranoid 的管理接口只挂在经过认证的路由组下
+func TestParanoidAdminRoutesRequireAuth(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  map[string]string
+		want    string
+		wantErr bool
+	}{
+		{name: "rbac", fields: map[string]string{"auth_mode": "session", "rbac": "on"}, want: `handlers.RegisterPostAdminRoutes(api.Group("/admin", middlewares.RequireRole(userRole, "admin")), s.db)`},
+		{name: "admin panel", fields: map[string]string{"admin_panel": "on"}, want: "handlers.RegisterPostAdminRoutes(admin, s.db)"},
+		{name: "session", fields: map[string]string{"auth_mode": "session"}, want: `handlers.RegisterPostAdminRoutes(api.Group("/admin"), s.db)`},
+		{name: "no auth", fields: map[string]string{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := map[string]string{
+				"project_name": "blog",
+				"module_name":  "example.com/blog",
+				"port":         "8080",
+				"models":       "User\nemail string required\npassword string required\n\nPost\ntitle string\n@paranoid",
+			}
+			for key, value := range tt.fields {
+				fields[key] = value
+			}
+			data, err := templateDataFromFields(fields)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("没有认证时应拒绝生成 @paranoid 的管理接口")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			content, err := renderBuiltinTemplate("server.go.tmpl", data.Delims, data)
+			if err != nil {
+				t.Fatalf("渲染失败: %v", err)
+			}
+			if !strings.Contains(string(content), tt.want) {
+				t.Errorf("输出缺少 %q", tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+// Package snapshot 对比生成器输出与 golden 文件，模板改动导致生成代码变化时测试失败。
+//
+// 生成器位于 main 包，无法直接导入，测试先编译生成器，再通过命令行模式生成项目；
+// 命令行模式把 MemFileSystem 中的全部文件写入 ZIP，测试读取 ZIP 得到生成结果。
+// 模板有意修改后运行 go test ./tests/snapshot -update 重写 golden 文件。
+package snapshot
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "用当前生成结果重写 golden 文件")
+
+// 每个配置生成一个 golden 文件，字段与 /generate 表单相同
+var configs = []struct {
+	name   string
+	fields map[string]string
+}{
+	{
+		name: "basic",
+		fields: map[string]string{
+			"project_name": "blog",
+			"module_name":  "example.com/blog",
+			"port":         "8080",
+			"models":       "User\nname string required\nemail string required gorm:\"unique\"\nage int\n\nPost\ntitle string required maxlen:200\nbody string",
+		},
+	},
+	{
+		name: "session_auth",
+		fields: map[string]string{
+			"project_name":   "shop",
+			"module_name":    "example.com/shop",
+			"port":           "9000",
+			"db_driver":      "postgres",
+			"json_tag_style": "camelCase",
+			"auth_mode":      "session",
+			"totp":           "on",
+			"rbac":           "on",
+			"models":         "User\nemail string required\npassword string required\n\nProduct\nname string required\nprice float64\n@perm DELETE admin",
+		},
+	},
+	{
+		name: "event_sourcing",
+		fields: map[string]string{
+			"project_name":   "ledger",
+			"module_name":    "example.com/ledger",
+			"port":           "8081",
+			"event_sourcing": "on",
+			"cqrs":           "on",
+			"generate_tests": "on",
+			"models":         "Account\nowner string required\nbalance float64\nactive bool",
+		},
+	},
+}
+
+// TestMain 编译的生成器
+var generatorBin string
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	dir, err := os.MkdirTemp("", "gin-crud-snapshot-*")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	generatorBin = filepath.Join(dir, "gin-crud-generator")
+	cmd := exec.Command("go", "build", "-o", generatorBin, ".")
+	cmd.Dir = filepath.Join("..", "..")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		fmt.Fprintf(os.Stderr, "无法编译生成器: %v\n%s", err, out)
+		os.RemoveAll(dir)
+		os.Exit(1)
+	}
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestGeneratedProjectMatchesGolden(t *testing.T) {
+	for _, cfg := range configs {
+		t.Run(cfg.name, func(t *testing.T) {
+			files := generate(t, cfg.fields)
+			goldenPath := filepath.Join("golden", cfg.name+".golden")
+			if *update {
+				if err := os.WriteFile(goldenPath, formatGolden(files), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			content, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("无法读取 golden 文件，首次运行请加 -update: %v", err)
+			}
+			want := parseGolden(content)
+			for _, path := range sortedPaths(want) {
+				got, ok := files[path]
+				if !ok {
+					t.Errorf("缺少文件 %s", path)
+					continue
+				}
+				if !bytes.Equal(got, want[path]) {
+					t.Errorf("%s 与 golden 不一致:\n%s", path, firstDiff(want[path], got))
+				}
+			}
+			for _, path := range sortedPaths(files) {
+				if _, ok := want[path]; !ok {
+					t.Errorf("多出文件 %s", path)
+				}
+			}
+		})
+	}
+}
+
+// 通过命令行模式生成项目，返回 ZIP 中的文件
+func generate(t *testing.T, fields map[string]string) map[string][]byte {
+	t.Helper()
+	// 在临时目录中运行，避免读取工作目录下的 custom_templates
+	dir := t.TempDir()
+	config, err := json.Marshal(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(configPath, config, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	zipPath := filepath.Join(dir, "project.zip")
+	cmd := exec.Command(generatorBin, "-config", configPath, "-o", zipPath)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("生成失败: %v\n%s", err, out)
+	}
+
+	r, err := zip.OpenReader(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	files := make(map[string][]byte)
+	for _, f := range r.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name] = content
+	}
+	return files
+}
+
+// golden 文件中每个文件以 "==> 路径 <==" 行开头，内容原样写入
+const (
+	headerPrefix = "==> "
+	headerSuffix = " <==\n"
+)
+
+func formatGolden(files map[string][]byte) []byte {
+	var buf bytes.Buffer
+	for _, path := range sortedPaths(files) {
+		buf.WriteString(headerPrefix + path + headerSuffix)
+		buf.Write(files[path])
+		// 内容不以换行结尾时补一个，解析时去掉
+		if !bytes.HasSuffix(files[path], []byte("\n")) {
+			buf.WriteString("\n\\ no newline\n")
+		}
+	}
+	return buf.Bytes()
+}
+
+func parseGolden(content []byte) map[string][]byte {
+	files := make(map[string][]byte)
+	var path string
+	var body bytes.Buffer
+	flush := func() {
+		if path != "" {
+			files[path] = bytes.TrimSuffix(body.Bytes(), []byte("\n\\ no newline\n"))
+		}
+		body = bytes.Buffer{}
+	}
+	for _, line := range bytes.SplitAfter(content, []byte("\n")) {
+		s := string(line)
+		if strings.HasPrefix(s, headerPrefix) && strings.HasSuffix(s, headerSuffix) {
+			flush()
+			path = strings.TrimSuffix(strings.TrimPrefix(s, headerPrefix), headerSuffix)
+			continue
+		}
+		body.Write(line)
+	}
+	flush()
+	return files
+}
+
+func sortedPaths(files map[string][]byte) []string {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// 第一处不同的行，便于定位模板改动
+func firstDiff(want, got []byte) string {
+	wantLines := strings.Split(string(want), "\n")
+	gotLines := strings.Split(string(got), "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return fmt.Sprintf("第 %d 行\n  golden: %q\n  生成:   %q", i+1, w, g)
+		}
+	}
+	return ""
+}
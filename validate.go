@@ -0,0 +1,476 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 支持的Go版本
+var supportedGoVersions = []string{"1.20", "1.21", "1.22", "1.23"}
+
+// 支持的主键类型，见 Model.IDType
+var supportedIDTypes = []string{"uint", "int64", "string", "uuid"}
+
+// 生成项目支持的错误消息语言
+var supportedLanguages = []string{"en", "zh", "es", "fr"}
+
+// GORM 支持的模型钩子方法
+var gormHookNames = []string{
+	"BeforeSave", "BeforeCreate", "AfterCreate", "AfterSave",
+	"BeforeUpdate", "AfterUpdate", "BeforeDelete", "AfterDelete", "AfterFind",
+}
+
+var supportedJSONTagStyles = []string{"snake_case", "camelCase", "PascalCase"}
+
+var supportedIaCDrivers = []string{"none", "terraform", "pulumi"}
+
+var supportedDBDrivers = []string{"mysql", "postgres"}
+
+// 与 licenses/ 目录中的文件名对应
+var supportedLicenses = []string{"none", "MIT", "Apache-2.0", "GPL-3.0", "AGPL-3.0", "MPL-2.0", "Proprietary"}
+
+var supportedBranchingStrategies = []string{"trunk", "gitflow"}
+
+// 认证方式，见 ProjectConfig.AuthMode
+var supportedAuthModes = []string{"none", "session", "magiclink"}
+
+// 支持的字段类型
+var supportedFieldTypes = []string{
+	"string",
+	"int", "int32", "int64",
+	"uint", "uint32", "uint64",
+	"float32", "float64",
+	"bool",
+	"time.Time",
+	"uuid.UUID",
+}
+
+// 校验所有模型，返回错误信息列表
+func modelErrors(models []Model) []string {
+	var errs []string
+	for _, err := range duplicateModelErrors(models) {
+		errs = append(errs, err.Error())
+	}
+	for _, m := range models {
+		for _, err := range ValidateModel(m) {
+			errs = append(errs, err.Error())
+		}
+	}
+	return errs
+}
+
+// 同名模型会生成重复的结构体定义，块序号为 Model.Block，即输入中的位置
+func duplicateModelErrors(models []Model) []error {
+	var errs []error
+	first := make(map[string]int)
+	for _, m := range models {
+		if prev, ok := first[m.Name]; ok {
+			errs = append(errs, fmt.Errorf("模型名 '%s' 重复，位于第 %d 和第 %d 个模型块", m.Name, prev, m.Block))
+			continue
+		}
+		first[m.Name] = m.Block
+	}
+	return errs
+}
+
+// 校验模型定义，返回所有发现的问题
+func ValidateModel(m Model) []error {
+	var errs []error
+
+	if m.Name == "" {
+		errs = append(errs, errors.New("模型名不能为空"))
+	} else if !token.IsIdentifier(m.Name) {
+		errs = append(errs, fmt.Errorf("模型名 %q 不是合法的Go标识符", m.Name))
+	} else if !unicode.IsUpper([]rune(m.Name)[0]) {
+		errs = append(errs, fmt.Errorf("模型名 %q 必须以大写字母开头", m.Name))
+	}
+
+	if !isSupportedIDType(m.IDType) {
+		errs = append(errs, fmt.Errorf("模型 %s: 主键类型 %q 不受支持，可选类型: %s",
+			m.Name, m.IDType, strings.Join(supportedIDTypes, ", ")))
+	}
+
+	// 版本历史和搜索索引同步都生成 AfterCreate、AfterUpdate、AfterDelete 钩子
+	if m.VersionHistory && m.SearchEnabled {
+		errs = append(errs, fmt.Errorf("模型 %s: @history 和 @search 不能同时使用", m.Name))
+	}
+
+	for _, h := range m.Hooks {
+		if !isGormHookName(h.Name) {
+			errs = append(errs, fmt.Errorf("模型 %s: 钩子 %q 不受支持，可选钩子: %s",
+				m.Name, h.Name, strings.Join(gormHookNames, ", ")))
+		}
+		// 版本历史使用 AfterCreate、AfterUpdate、AfterDelete 写入历史表
+		if m.VersionHistory && (h.Name == "AfterCreate" || h.Name == "AfterUpdate" || h.Name == "AfterDelete") {
+			errs = append(errs, fmt.Errorf("模型 %s: 启用 @history 后不能自定义 %s 钩子", m.Name, h.Name))
+		}
+	}
+
+	scopes := make(map[string]bool)
+	for _, s := range m.Scopes {
+		if !token.IsIdentifier(s.Name) || !unicode.IsUpper([]rune(s.Name)[0]) {
+			errs = append(errs, fmt.Errorf("模型 %s: 查询条件名 %q 必须是以大写字母开头的Go标识符", m.Name, s.Name))
+		}
+		if scopes[s.Name] {
+			errs = append(errs, fmt.Errorf("模型 %s: 查询条件 %s 重复", m.Name, s.Name))
+		}
+		scopes[s.Name] = true
+	}
+
+	seen := make(map[string]string)
+	for _, f := range m.Fields {
+		if !token.IsIdentifier(f.Name) {
+			errs = append(errs, fmt.Errorf("模型 %s: 字段名 %q 不是合法的Go标识符", m.Name, f.Name))
+		}
+
+		if !isSupportedFieldType(f.Type) {
+			errs = append(errs, fmt.Errorf("模型 %s: 字段 %s 的类型 %q 不受支持，可选类型: %s",
+				m.Name, f.Name, f.Type, strings.Join(supportedFieldTypes, ", ")))
+		}
+
+		// normalizeIDField 已移除可作为主键的 ID 字段，剩下的类型不合法
+		if strings.EqualFold(f.Name, "ID") {
+			errs = append(errs, fmt.Errorf("模型 %s: ID 字段的类型 %s 不能作为主键", m.Name, f.Type))
+		}
+
+		key := strings.ToLower(f.Name)
+		if prev, ok := seen[key]; ok {
+			errs = append(errs, fmt.Errorf("模型 %s: 字段 %s 与 %s 重复", m.Name, f.Name, prev))
+		}
+		seen[key] = f.Name
+
+		if problem := fieldLengthProblem(f); problem != "" {
+			errs = append(errs, fmt.Errorf("模型 %s: 字段 %s %s", m.Name, f.Name, problem))
+		}
+
+		if f.Default != "" {
+			if problem := fieldDefaultProblem(f); problem != "" {
+				errs = append(errs, fmt.Errorf("模型 %s: 字段 %s 的默认值 %q %s", m.Name, f.Name, f.Default, problem))
+			}
+		}
+
+		// 必填校验将 false 视为缺失，bool 字段标记为必填后永远无法提交 false
+		if f.Required && f.Type == "bool" {
+			errs = append(errs, fmt.Errorf("模型 %s: bool 字段 %s 不能标记为 required", m.Name, f.Name))
+		}
+	}
+
+	return errs
+}
+
+func isSupportedFieldType(t string) bool {
+	for _, supported := range supportedFieldTypes {
+		if t == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// 是否为支持的主键类型，见 supportedIDTypes
+func isSupportedIDType(t string) bool {
+	for _, supported := range supportedIDTypes {
+		if t == supported {
+			return true
+		}
+	}
+	return false
+}
+
+func isGormHookName(name string) bool {
+	for _, supported := range gormHookNames {
+		if name == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// 校验生成项目使用的Go版本
+func validateGoVersion(v string) error {
+	for _, supported := range supportedGoVersions {
+		if v == supported {
+			return nil
+		}
+	}
+	return fmt.Errorf("不支持的Go版本 %q，可选版本: %s", v, strings.Join(supportedGoVersions, ", "))
+}
+
+func validateLanguage(lang string) error {
+	for _, supported := range supportedLanguages {
+		if lang == supported {
+			return nil
+		}
+	}
+	return fmt.Errorf("不支持的语言 %q，可选语言: %s", lang, strings.Join(supportedLanguages, ", "))
+}
+
+func validateJSONTagStyle(style string) error {
+	for _, supported := range supportedJSONTagStyles {
+		if style == supported {
+			return nil
+		}
+	}
+	return fmt.Errorf("不支持的JSON标签风格 %q，可选风格: %s", style, strings.Join(supportedJSONTagStyles, ", "))
+}
+
+func validateLicense(license string) error {
+	for _, supported := range supportedLicenses {
+		if license == supported {
+			return nil
+		}
+	}
+	return fmt.Errorf("不支持的许可证 %q，可选: %s", license, strings.Join(supportedLicenses, ", "))
+}
+
+func validateAuthMode(mode string) error {
+	for _, supported := range supportedAuthModes {
+		if mode == supported {
+			return nil
+		}
+	}
+	return fmt.Errorf("不支持的认证方式 %q，可选: %s", mode, strings.Join(supportedAuthModes, ", "))
+}
+
+func validateBranchingStrategy(strategy string) error {
+	for _, supported := range supportedBranchingStrategies {
+		if strategy == supported {
+			return nil
+		}
+	}
+	return fmt.Errorf("不支持的分支策略 %q，可选: %s", strategy, strings.Join(supportedBranchingStrategies, ", "))
+}
+
+// 只接受纯邮箱地址，不接受 "Name <addr>" 形式
+func validateAuthorEmail(email string) error {
+	if email == "" {
+		return nil
+	}
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return fmt.Errorf("作者邮箱 %q 不合法，例如 maintainers@example.com", email)
+	}
+	return nil
+}
+
+// Slack webhook 地址会写入 .env，不能包含换行等控制字符
+func validateSlackWebhookURL(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	if strings.IndexFunc(raw, unicode.IsControl) < 0 {
+		if u, err := url.Parse(raw); err == nil && u.Scheme == "https" && u.Host != "" {
+			return nil
+		}
+	}
+	return fmt.Errorf("Slack webhook 地址 %q 不合法，必须是 https:// 开头的完整地址", raw)
+}
+
+// Let's Encrypt 账号邮箱会写入 .env，与作者邮箱一样只接受纯邮箱地址
+func validateLetsEncryptEmail(email string) error {
+	if email == "" {
+		return nil
+	}
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return fmt.Errorf("Let's Encrypt 邮箱 %q 不合法，例如 admin@example.com", email)
+	}
+	return nil
+}
+
+func validateIaCDriver(driver string) error {
+	for _, supported := range supportedIaCDrivers {
+		if driver == supported {
+			return nil
+		}
+	}
+	return fmt.Errorf("不支持的基础设施工具 %q，可选: %s", driver, strings.Join(supportedIaCDrivers, ", "))
+}
+
+func validateDBDriver(driver string) error {
+	for _, supported := range supportedDBDrivers {
+		if driver == supported {
+			return nil
+		}
+	}
+	return fmt.Errorf("不支持的数据库 %q，可选: %s", driver, strings.Join(supportedDBDrivers, ", "))
+}
+
+var tablePrefixPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+var mysqlCharsetPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// 字符集和排序规则会写入 DSN 和建表语句，排序规则必须属于该字符集，如 utf8mb4_unicode_ci
+func validateMySQLCharset(charset, collation string) error {
+	if !mysqlCharsetPattern.MatchString(charset) {
+		return fmt.Errorf("MySQL 字符集 %q 不合法，例如 utf8mb4", charset)
+	}
+	if !mysqlCharsetPattern.MatchString(collation) || !strings.HasPrefix(collation, charset+"_") {
+		return fmt.Errorf("MySQL 排序规则 %q 不属于字符集 %s，例如 %s_unicode_ci", collation, charset, charset)
+	}
+	return nil
+}
+
+// 表名前缀可以为空，否则只能包含小写字母、数字和下划线
+func validateTablePrefix(prefix string) error {
+	if prefix != "" && !tablePrefixPattern.MatchString(prefix) {
+		return fmt.Errorf("表名前缀 %q 只能包含小写字母、数字和下划线，且以字母开头", prefix)
+	}
+	return nil
+}
+
+// 去掉逗号分隔列表中的空白和空项
+func normalizeCIDRList(list string) string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return strings.Join(items, ",")
+}
+
+// 校验逗号分隔的 CIDR 列表，也接受单个IP
+func validateCIDRList(list string) error {
+	if list == "" {
+		return nil
+	}
+	for _, item := range strings.Split(list, ",") {
+		if net.ParseIP(item) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(item); err != nil {
+			return fmt.Errorf("%q 不是合法的IP或CIDR", item)
+		}
+	}
+	return nil
+}
+
+var domainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,}$`)
+
+// Let's Encrypt 只为公网域名签发证书，不接受IP和通配符
+func validateDomain(domain string) error {
+	if domain != "" && !domainPattern.MatchString(domain) {
+		return fmt.Errorf("域名 %q 不合法，例如 api.example.com", domain)
+	}
+	return nil
+}
+
+var tlsFilePathPattern = regexp.MustCompile(`^[A-Za-z0-9._/-]+$`)
+
+// 证书和私钥路径会写入 .env 和 generate-dev-cert.sh，只接受不含 .. 的普通路径，
+// 不能以 - 开头，避免被 openssl 等命令当作选项
+func validateTLSFilePath(path string) error {
+	if !tlsFilePathPattern.MatchString(path) || strings.Contains(path, "..") || strings.HasPrefix(path, "-") {
+		return fmt.Errorf("证书路径 %q 不合法: 只能包含字母、数字和 . _ / -，不能包含 ..，例如 certs/server.crt", path)
+	}
+	return nil
+}
+
+var projectNamePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]*$`)
+
+// 校验项目名称，项目名称会用于文件路径和ZIP文件名
+func sanitizeProjectName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if !projectNamePattern.MatchString(name) {
+		return "", fmt.Errorf("项目名称 %q 不合法: 必须以字母开头，且只能包含字母、数字、下划线和连字符", name)
+	}
+	return name, nil
+}
+
+var modulePathSegmentPattern = regexp.MustCompile(`^[A-Za-z0-9._~-]+$`)
+
+// 按Go模块路径规则校验模块名，例如 github.com/user/project
+func validateModuleName(name string) error {
+	if name == "" {
+		return fmt.Errorf("模块名称不能为空")
+	}
+	if strings.ContainsAny(name, " \t\r\n") {
+		return fmt.Errorf("模块名称 %q 不能包含空白字符", name)
+	}
+
+	segments := strings.Split(name, "/")
+	for i, segment := range segments {
+		switch {
+		case segment == "":
+			return fmt.Errorf("模块名称 %q 包含空的路径段", name)
+		case !modulePathSegmentPattern.MatchString(segment):
+			return fmt.Errorf("模块名称 %q 的路径段 %q 包含非法字符", name, segment)
+		case strings.HasPrefix(segment, ".") || strings.HasSuffix(segment, "."):
+			return fmt.Errorf("模块名称 %q 的路径段 %q 不能以点开头或结尾", name, segment)
+		case i == 0 && strings.ToLower(segment) != segment:
+			return fmt.Errorf("模块名称 %q 的域名部分 %q 不能包含大写字母", name, segment)
+		}
+	}
+	return nil
+}
+
+// 常见服务占用的端口，允许使用但会给出警告
+var reservedPorts = map[int]string{
+	22:  "SSH",
+	80:  "HTTP",
+	443: "HTTPS",
+}
+
+// 校验API端口，需在 1024-65535 之间（reservedPorts 中的端口除外）
+func validatePort(port string) error {
+	n, err := strconv.Atoi(port)
+	if err != nil || n <= 0 {
+		return fmt.Errorf("端口 %q 必须是正整数", port)
+	}
+	if _, ok := reservedPorts[n]; ok {
+		return nil
+	}
+	if n < 1024 || n > 65535 {
+		return fmt.Errorf("端口 %d 必须在 1024 到 65535 之间", n)
+	}
+	return nil
+}
+
+// 端口的非致命警告，没有问题时返回空字符串
+func portWarning(port string) string {
+	n, _ := strconv.Atoi(port)
+	if service, ok := reservedPorts[n]; ok {
+		return fmt.Sprintf("port %d is usually reserved for %s", n, service)
+	}
+	return ""
+}
+
+// POST /validate 的请求体，支持表单和 JSON
+type validateRequest struct {
+	Models       string `form:"models" json:"models"`
+	JSONTagStyle string `form:"json_tag_style" json:"json_tag_style"`
+}
+
+// POST /validate：只解析和校验模型定义，不生成项目，供网页表单输入时实时检查
+// 模型定义本身有错误时仍返回 200，errors 为空表示校验通过
+func validateModels(c *gin.Context) {
+	var req validateRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无法解析请求: " + err.Error()})
+		return
+	}
+	if req.JSONTagStyle == "" {
+		req.JSONTagStyle = defaultJSONTagStyle
+	}
+	if err := validateJSONTagStyle(req.JSONTagStyle); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	models := parseModels(req.Models, req.JSONTagStyle)
+	errs := modelErrors(models)
+	if errs == nil {
+		errs = []string{}
+	}
+	c.JSON(http.StatusOK, gin.H{"models": len(models), "errors": errs})
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestModelErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		models string
+		want   []string
+	}{
+		{
+			name:   "valid",
+			models: "User\nname string required\nage int\n\nPost\ntitle string maxlen:200",
+		},
+		{
+			name:   "empty model name",
+			models: "   \nname string",
+			want:   []string{"模型名不能为空"},
+		},
+		{
+			name:   "lowercase model name",
+			models: "user\nname string",
+			want:   []string{`模型名 "user" 必须以大写字母开头`},
+		},
+		{
+			name:   "invalid model name",
+			models: "Blog-Post\ntitle string",
+			want:   []string{`模型名 "Blog-Post" 不是合法的Go标识符`},
+		},
+		{
+			name:   "unsupported field type",
+			models: "User\nbirthday date",
+			want:   []string{`模型 User: 字段 Birthday 的类型 "date" 不受支持，可选类型: ` + strings.Join(supportedFieldTypes, ", ")},
+		},
+		{
+			name:   "duplicate field ignoring case",
+			models: "User\nname string\nNAME string",
+			want:   []string{"模型 User: 字段 NAME 与 Name 重复"},
+		},
+		{
+			name:   "required bool",
+			models: "User\nactive bool required",
+			want:   []string{"模型 User: bool 字段 Active 不能标记为 required"},
+		},
+		{
+			name:   "history with search",
+			models: "Post\ntitle string\n@history\n@search",
+			want:   []string{"模型 Post: @history 和 @search 不能同时使用"},
+		},
+		{
+			name:   "all problems are reported",
+			models: "user\nname string\nname int\n\nPost\nbody text",
+			want: []string{
+				`模型名 "user" 必须以大写字母开头`,
+				"模型 user: 字段 Name 与 Name 重复",
+				`模型 Post: 字段 Body 的类型 "text" 不受支持，可选类型: ` + strings.Join(supportedFieldTypes, ", "),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := modelErrors(parseModels(tt.models, defaultJSONTagStyle))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("modelErrors() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProjectOptionValidators(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string // 为空表示应通过校验
+	}{
+		{name: "go version", err: validateGoVersion("1.21")},
+		{name: "unsupported go version", err: validateGoVersion("1.19"), want: `不支持的Go版本 "1.19"，可选版本: 1.20, 1.21, 1.22, 1.23`},
+		{name: "unsupported language", err: validateLanguage("de"), want: `不支持的语言 "de"，可选语言: en, zh, es, fr`},
+		{name: "unsupported json tag style", err: validateJSONTagStyle("kebab-case"), want: `不支持的JSON标签风格 "kebab-case"，可选风格: snake_case, camelCase, PascalCase`},
+		{name: "auth mode", err: validateAuthMode("magiclink")},
+		{name: "unsupported auth mode", err: validateAuthMode("jwt"), want: `不支持的认证方式 "jwt"，可选: none, session, magiclink`},
+		{name: "unsupported db driver", err: validateDBDriver("sqlite"), want: `不支持的数据库 "sqlite"，可选: mysql, postgres`},
+		{name: "empty table prefix", err: validateTablePrefix("")},
+		{name: "invalid table prefix", err: validateTablePrefix("Crm-"), want: `表名前缀 "Crm-" 只能包含小写字母、数字和下划线，且以字母开头`},
+		{name: "collation of another charset", err: validateMySQLCharset("utf8mb4", "latin1_swedish_ci"), want: `MySQL 排序规则 "latin1_swedish_ci" 不属于字符集 utf8mb4，例如 utf8mb4_unicode_ci`},
+		{name: "cidr list", err: validateCIDRList("10.0.0.0/8,192.168.1.1")},
+		{name: "invalid cidr", err: validateCIDRList("10.0.0.0/33"), want: `"10.0.0.0/33" 不是合法的IP或CIDR`},
+		{name: "tls file path", err: validateTLSFilePath("certs/server.crt")},
+		{name: "tls file path with command substitution", err: validateTLSFilePath("certs/a.crt$(touch /tmp/x)"), want: `证书路径 "certs/a.crt$(touch /tmp/x)" 不合法: 只能包含字母、数字和 . _ / -，不能包含 ..，例如 certs/server.crt`},
+		{name: "tls file path with newline", err: validateTLSFilePath("certs/a.crt\nDB_HOST=evil"), want: `证书路径 "certs/a.crt\nDB_HOST=evil" 不合法: 只能包含字母、数字和 . _ / -，不能包含 ..，例如 certs/server.crt`},
+		{name: "tls file path outside project", err: validateTLSFilePath("../secrets/server.key"), want: `证书路径 "../secrets/server.key" 不合法: 只能包含字母、数字和 . _ / -，不能包含 ..，例如 certs/server.crt`},
+		{name: "slack webhook url", err: validateSlackWebhookURL("https://hooks.slack.com/services/T000/B000/XXXX")},
+		{name: "slack webhook url over http", err: validateSlackWebhookURL("http://hooks.slack.com/services/T000"), want: `Slack webhook 地址 "http://hooks.slack.com/services/T000" 不合法，必须是 https:// 开头的完整地址`},
+		{name: "slack webhook url without host", err: validateSlackWebhookURL("https:///services/T000"), want: `Slack webhook 地址 "https:///services/T000" 不合法，必须是 https:// 开头的完整地址`},
+		{name: "slack webhook url with newline", err: validateSlackWebhookURL("https://hooks.slack.com/x\nDB_PASSWORD=owned"), want: `Slack webhook 地址 "https://hooks.slack.com/x\nDB_PASSWORD=owned" 不合法，必须是 https:// 开头的完整地址`},
+		{name: "letsencrypt email", err: validateLetsEncryptEmail("admin@example.com")},
+		{name: "letsencrypt email with newline", err: validateLetsEncryptEmail("a@example.com\nADMIN_PASS=x"), want: `Let's Encrypt 邮箱 "a@example.com\nADMIN_PASS=x" 不合法，例如 admin@example.com`},
+		{name: "author email with name", err: validateAuthorEmail("Jane <jane@example.com>"), want: `作者邮箱 "Jane <jane@example.com>" 不合法，例如 maintainers@example.com`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.want == "" {
+				if tt.err != nil {
+					t.Errorf("unexpected error: %v", tt.err)
+				}
+				return
+			}
+			if tt.err == nil || tt.err.Error() != tt.want {
+				t.Errorf("error = %v, want %q", tt.err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDuplicateModelErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		models string
+		want   []string
+	}{
+		{
+			name:   "unique names",
+			models: "User\nname string\n\nPost\ntitle string",
+		},
+		{
+			name:   "duplicate",
+			models: "User\nname string\n\nPost\ntitle string\n\nUser\nemail string",
+			want:   []string{"模型名 'User' 重复，位于第 1 和第 3 个模型块"},
+		},
+		{
+			name:   "each repeat refers to the first block",
+			models: "Post\ntitle string\n\nPost\nbody string\n\nPost\nslug string",
+			want: []string{
+				"模型名 'Post' 重复，位于第 1 和第 2 个模型块",
+				"模型名 'Post' 重复，位于第 1 和第 3 个模型块",
+			},
+		},
+		{
+			// 只有一行的块被 parseModels 跳过，序号仍按输入中的位置计算
+			name:   "skipped block keeps numbering",
+			models: "Post\ntitle string\n\nDraft\n\nPost\nbody string",
+			want:   []string{"模型名 'Post' 重复，位于第 1 和第 3 个模型块"},
+		},
+		{
+			// Go 标识符区分大小写，User 和 user 是不同的结构体，由 ValidateModel 报告首字母问题
+			name:   "case sensitive",
+			models: "User\nname string\n\nuser\nname string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, err := range duplicateModelErrors(parseModels(tt.models, defaultJSONTagStyle)) {
+				got = append(got, err.Error())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("duplicateModelErrors() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}